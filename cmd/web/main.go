@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"github.com/Bangdams/web-profile-API/internal/config"
 	"github.com/go-playground/validator/v10"
@@ -14,14 +18,20 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	db := config.NewDatabase()
 	app := config.NewFiber()
 	validate := validator.New()
+	runners := new(sync.WaitGroup)
 
 	config.Bootstrap(&config.BootstrapConfig{
 		DB:       db,
 		App:      app,
 		Validate: validate,
+		Context:  ctx,
+		Runners:  runners,
 	})
 
 	port := os.Getenv("PORT")
@@ -29,7 +39,18 @@ func main() {
 		port = "8080"
 	}
 
+	go func() {
+		<-ctx.Done()
+		if err := app.Shutdown(); err != nil {
+			log.Println("failed to shut down server : ", err)
+		}
+	}()
+
 	if err := app.Listen(":" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+
+	// the runners flush the views counted and finish the work under way
+	stop()
+	runners.Wait()
 }
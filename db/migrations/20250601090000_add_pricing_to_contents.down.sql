@@ -0,0 +1,15 @@
+SET
+  FOREIGN_KEY_CHECKS = 0;
+
+DROP TABLE IF EXISTS content_prices;
+
+SET
+  FOREIGN_KEY_CHECKS = 1;
+
+ALTER TABLE contents
+  DROP INDEX idx_contents_min_price,
+  DROP COLUMN max_price,
+  DROP COLUMN min_price,
+  DROP COLUMN price_level,
+  DROP COLUMN currency,
+  DROP COLUMN is_free;
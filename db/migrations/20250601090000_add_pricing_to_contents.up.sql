@@ -0,0 +1,19 @@
+ALTER TABLE contents
+  ADD COLUMN is_free TINYINT(1) NOT NULL DEFAULT 0 AFTER category,
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IDR' AFTER is_free,
+  ADD COLUMN price_level TINYINT UNSIGNED NULL AFTER currency,
+  ADD COLUMN min_price BIGINT NULL AFTER price_level,
+  ADD COLUMN max_price BIGINT NULL AFTER min_price,
+  ADD INDEX idx_contents_min_price (min_price);
+
+CREATE TABLE content_prices (
+  id INT AUTO_INCREMENT,
+  content_id INT NOT NULL,
+  tier ENUM('general', 'adult', 'child', 'student', 'foreign_tourist') NOT NULL,
+  label VARCHAR(100),
+  amount BIGINT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,3 @@
+ALTER TABLE announcements DROP COLUMN content_format;
+
+ALTER TABLE contents DROP COLUMN content_format;
@@ -0,0 +1,5 @@
+ALTER TABLE contents
+  ADD COLUMN content_format ENUM('markdown', 'html') NOT NULL DEFAULT 'html' AFTER content;
+
+ALTER TABLE announcements
+  ADD COLUMN content_format ENUM('markdown', 'html') NOT NULL DEFAULT 'html' AFTER content;
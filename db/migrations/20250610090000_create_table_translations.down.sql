@@ -0,0 +1,9 @@
+SET
+  FOREIGN_KEY_CHECKS = 0;
+
+DROP TABLE IF EXISTS announcement_translations;
+
+DROP TABLE IF EXISTS content_translations;
+
+SET
+  FOREIGN_KEY_CHECKS = 1;
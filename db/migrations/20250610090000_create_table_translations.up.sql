@@ -0,0 +1,27 @@
+CREATE TABLE content_translations (
+  id INT AUTO_INCREMENT,
+  content_id INT NOT NULL,
+  locale VARCHAR(10) NOT NULL,
+  title VARCHAR(150) NOT NULL,
+  content TEXT NOT NULL,
+  content_format ENUM('markdown', 'html') NOT NULL DEFAULT 'html',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_content_translations_locale (content_id, locale),
+  FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+CREATE TABLE announcement_translations (
+  id INT AUTO_INCREMENT,
+  announcement_id INT NOT NULL,
+  locale VARCHAR(10) NOT NULL,
+  title VARCHAR(150) NOT NULL,
+  content TEXT NOT NULL,
+  content_format ENUM('markdown', 'html') NOT NULL DEFAULT 'html',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_announcement_translations_locale (announcement_id, locale),
+  FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,12 @@
+SET
+  FOREIGN_KEY_CHECKS = 0;
+
+DROP TABLE IF EXISTS reviews;
+
+SET
+  FOREIGN_KEY_CHECKS = 1;
+
+ALTER TABLE contents
+  DROP INDEX idx_contents_rating,
+  DROP COLUMN review_count,
+  DROP COLUMN rating_average;
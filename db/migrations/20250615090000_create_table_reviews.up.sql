@@ -0,0 +1,22 @@
+ALTER TABLE contents
+  ADD COLUMN rating_average DECIMAL(3, 2) NOT NULL DEFAULT 0 AFTER max_price,
+  ADD COLUMN review_count INT NOT NULL DEFAULT 0 AFTER rating_average,
+  ADD INDEX idx_contents_rating (rating_average, review_count);
+
+CREATE TABLE reviews (
+  id INT AUTO_INCREMENT,
+  content_id INT NOT NULL,
+  rating TINYINT UNSIGNED NOT NULL,
+  body TEXT NOT NULL,
+  reviewer_name VARCHAR(80) NOT NULL,
+  reviewer_contact VARCHAR(100),
+  status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+  moderated_by INT NULL,
+  moderated_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_reviews_content_status (content_id, status),
+  FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE,
+  FOREIGN KEY (moderated_by) REFERENCES admins(id) ON DELETE SET NULL
+) ENGINE = InnoDB;
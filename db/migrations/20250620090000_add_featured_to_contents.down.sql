@@ -0,0 +1,4 @@
+ALTER TABLE contents
+  DROP INDEX idx_contents_featured,
+  DROP COLUMN position,
+  DROP COLUMN is_featured;
@@ -0,0 +1,4 @@
+ALTER TABLE contents
+  ADD COLUMN is_featured TINYINT(1) NOT NULL DEFAULT 0 AFTER review_count,
+  ADD COLUMN position INT NOT NULL DEFAULT 0 AFTER is_featured,
+  ADD INDEX idx_contents_featured (category, is_featured, position);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS daily_views;
+
+ALTER TABLE announcements DROP COLUMN view_count;
+
+ALTER TABLE contents DROP COLUMN view_count;
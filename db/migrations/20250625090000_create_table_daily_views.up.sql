@@ -0,0 +1,14 @@
+ALTER TABLE contents
+  ADD COLUMN view_count BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER position;
+
+ALTER TABLE announcements
+  ADD COLUMN view_count BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER published_by;
+
+CREATE TABLE daily_views (
+  item_type ENUM('content', 'announcement') NOT NULL,
+  item_id INT NOT NULL,
+  view_date DATE NOT NULL,
+  views BIGINT UNSIGNED NOT NULL,
+  PRIMARY KEY (item_type, item_id, view_date),
+  INDEX idx_daily_views_date (item_type, view_date)
+) ENGINE = InnoDB;
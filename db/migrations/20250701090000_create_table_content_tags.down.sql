@@ -0,0 +1,11 @@
+SET
+  FOREIGN_KEY_CHECKS = 0;
+
+DROP TABLE IF EXISTS content_tags;
+
+SET
+  FOREIGN_KEY_CHECKS = 1;
+
+ALTER TABLE contents
+  DROP COLUMN longitude,
+  DROP COLUMN latitude;
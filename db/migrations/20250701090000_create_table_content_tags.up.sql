@@ -0,0 +1,13 @@
+ALTER TABLE contents
+  ADD COLUMN latitude DECIMAL(9, 6) NULL AFTER contact_info,
+  ADD COLUMN longitude DECIMAL(9, 6) NULL AFTER latitude;
+
+CREATE TABLE content_tags (
+  id INT AUTO_INCREMENT,
+  content_id INT NOT NULL,
+  tag VARCHAR(50) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_content_tags_tag (content_id, tag),
+  INDEX idx_content_tags_tag (tag),
+  FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
@@ -0,0 +1,8 @@
+ALTER TABLE admins
+  DROP COLUMN version;
+
+ALTER TABLE announcements
+  DROP COLUMN version;
+
+ALTER TABLE contents
+  DROP COLUMN version;
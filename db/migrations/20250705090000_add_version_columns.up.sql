@@ -0,0 +1,8 @@
+ALTER TABLE contents
+  ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER view_count;
+
+ALTER TABLE announcements
+  ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER view_count;
+
+ALTER TABLE admins
+  ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER password;
@@ -0,0 +1,11 @@
+DELETE FROM announcements WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE announcements
+  DROP INDEX idx_announcements_deleted_at,
+  DROP COLUMN deleted_at;
+
+DELETE FROM contents WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE contents
+  DROP INDEX idx_contents_deleted_at,
+  DROP COLUMN deleted_at;
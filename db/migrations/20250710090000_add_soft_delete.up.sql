@@ -0,0 +1,7 @@
+ALTER TABLE contents
+  ADD COLUMN deleted_at DATETIME(3) NULL AFTER updated_at,
+  ADD INDEX idx_contents_deleted_at (deleted_at);
+
+ALTER TABLE announcements
+  ADD COLUMN deleted_at DATETIME(3) NULL AFTER updated_at,
+  ADD INDEX idx_announcements_deleted_at (deleted_at);
@@ -0,0 +1,7 @@
+SET
+  FOREIGN_KEY_CHECKS = 0;
+
+DROP TABLE IF EXISTS content_contacts;
+
+SET
+  FOREIGN_KEY_CHECKS = 1;
@@ -0,0 +1,16 @@
+CREATE TABLE content_contacts (
+  id INT AUTO_INCREMENT,
+  content_id INT NOT NULL,
+  type ENUM('phone', 'whatsapp', 'email', 'instagram', 'facebook', 'website') NOT NULL,
+  value VARCHAR(255) NOT NULL,
+  label VARCHAR(100) NULL,
+  position INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  INDEX idx_content_contacts_content (content_id, position),
+  FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+INSERT INTO content_contacts (content_id, type, value, position)
+SELECT id, 'phone', contact_info, 0
+FROM contents
+WHERE contact_info IS NOT NULL AND contact_info <> '';
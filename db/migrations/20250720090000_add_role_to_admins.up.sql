@@ -0,0 +1,5 @@
+ALTER TABLE admins
+  ADD COLUMN role ENUM('editor', 'publisher', 'admin') NOT NULL DEFAULT 'editor' AFTER password;
+
+-- everyone could publish so far
+UPDATE admins SET role = 'admin';
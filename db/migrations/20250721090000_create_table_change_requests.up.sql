@@ -0,0 +1,21 @@
+CREATE TABLE change_requests (
+  id INT AUTO_INCREMENT,
+  item_type ENUM('content', 'announcement') NOT NULL,
+  item_id INT NULL,
+  action ENUM('create', 'update') NOT NULL,
+  base_version INT UNSIGNED NOT NULL DEFAULT 0,
+  title VARCHAR(255) NOT NULL,
+  payload JSON NOT NULL,
+  status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+  requested_by INT NOT NULL,
+  reviewed_by INT NULL,
+  reviewed_at TIMESTAMP NULL,
+  review_comment TEXT,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_change_requests_status (status, created_at),
+  INDEX idx_change_requests_item (item_type, item_id),
+  FOREIGN KEY (requested_by) REFERENCES admins(id) ON DELETE CASCADE,
+  FOREIGN KEY (reviewed_by) REFERENCES admins(id) ON DELETE SET NULL
+) ENGINE = InnoDB;
@@ -0,0 +1,12 @@
+CREATE TABLE item_editors (
+  id INT AUTO_INCREMENT,
+  item_type ENUM('content', 'announcement') NOT NULL,
+  item_id INT NOT NULL,
+  admin_id INT NOT NULL,
+  assigned_by INT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE INDEX idx_item_editors_item_admin (item_type, item_id, admin_id),
+  FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE,
+  FOREIGN KEY (assigned_by) REFERENCES admins(id) ON DELETE SET NULL
+) ENGINE = InnoDB;
@@ -0,0 +1,20 @@
+CREATE TABLE events (
+  id INT AUTO_INCREMENT,
+  title VARCHAR(150) NOT NULL,
+  description TEXT NOT NULL,
+  location VARCHAR(255) NOT NULL DEFAULT '',
+  content_id INT NULL,
+  starts_at DATETIME NOT NULL,
+  ends_at DATETIME NOT NULL,
+  all_day BOOLEAN NOT NULL DEFAULT FALSE,
+  rrule VARCHAR(500) NOT NULL DEFAULT '',
+  series_ends_at DATETIME NULL,
+  created_by INT NOT NULL,
+  version INT NOT NULL DEFAULT 1,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_events_range (starts_at, series_ends_at),
+  FOREIGN KEY (content_id) REFERENCES contents(id) ON DELETE SET NULL,
+  FOREIGN KEY (created_by) REFERENCES admins(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
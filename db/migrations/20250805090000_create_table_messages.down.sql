@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS message_replies;
+DROP TABLE IF EXISTS message_notes;
+DROP TABLE IF EXISTS messages;
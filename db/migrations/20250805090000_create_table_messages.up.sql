@@ -0,0 +1,44 @@
+CREATE TABLE messages (
+  id INT AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  email VARCHAR(255) NOT NULL,
+  phone VARCHAR(30) NOT NULL DEFAULT '',
+  subject VARCHAR(200) NOT NULL,
+  body TEXT NOT NULL,
+  ip_address VARCHAR(45) NOT NULL DEFAULT '',
+  user_agent VARCHAR(255) NOT NULL DEFAULT '',
+  is_spam BOOLEAN NOT NULL DEFAULT FALSE,
+  read_at TIMESTAMP NULL,
+  archived_at TIMESTAMP NULL,
+  assigned_to INT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_messages_inbox (is_spam, archived_at, created_at),
+  FOREIGN KEY (assigned_to) REFERENCES admins(id) ON DELETE SET NULL
+) ENGINE = InnoDB;
+
+CREATE TABLE message_notes (
+  id INT AUTO_INCREMENT,
+  message_id INT NOT NULL,
+  parent_id INT NULL,
+  admin_id INT NOT NULL,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+  FOREIGN KEY (parent_id) REFERENCES message_notes(id) ON DELETE CASCADE,
+  FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+CREATE TABLE message_replies (
+  id INT AUTO_INCREMENT,
+  message_id INT NOT NULL,
+  admin_id INT NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  body TEXT NOT NULL,
+  sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+  FOREIGN KEY (admin_id) REFERENCES admins(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
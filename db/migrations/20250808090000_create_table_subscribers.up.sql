@@ -0,0 +1,17 @@
+CREATE TABLE subscribers (
+  id INT AUTO_INCREMENT,
+  email VARCHAR(255) NOT NULL,
+  name VARCHAR(100) NOT NULL DEFAULT '',
+  topics SET('announcements', 'kuliner', 'wisata', 'events') NOT NULL,
+  token CHAR(64) NOT NULL,
+  confirm_token CHAR(64) NULL,
+  confirm_expires_at TIMESTAMP NULL,
+  confirmed_at TIMESTAMP NULL,
+  last_sent_at TIMESTAMP NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_subscribers_email (email),
+  UNIQUE KEY uq_subscribers_token (token),
+  UNIQUE KEY uq_subscribers_confirm_token (confirm_token)
+) ENGINE = InnoDB;
@@ -0,0 +1,37 @@
+CREATE TABLE webhooks (
+  id INT AUTO_INCREMENT,
+  url VARCHAR(500) NOT NULL,
+  secret VARCHAR(64) NOT NULL,
+  events SET('content.created', 'content.updated', 'content.deleted', 'announcement.published') NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  failure_count INT NOT NULL DEFAULT 0,
+  disabled_at TIMESTAMP NULL,
+  created_by INT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (created_by) REFERENCES admins(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
+
+CREATE TABLE webhook_deliveries (
+  id INT AUTO_INCREMENT,
+  webhook_id INT NOT NULL,
+  event VARCHAR(50) NOT NULL,
+  payload MEDIUMTEXT NOT NULL,
+  status ENUM('pending', 'succeeded', 'failed') NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  response_code INT NULL,
+  response_body TEXT NOT NULL,
+  error VARCHAR(500) NOT NULL DEFAULT '',
+  next_attempt_at TIMESTAMP NULL,
+  delivered_at TIMESTAMP NULL,
+  redelivery_of INT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+  INDEX idx_webhook_deliveries_webhook (webhook_id, created_at),
+  FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE,
+  FOREIGN KEY (redelivery_of) REFERENCES webhook_deliveries(id) ON DELETE SET NULL
+) ENGINE = InnoDB;
@@ -0,0 +1,5 @@
+ALTER TABLE announcements
+  DROP INDEX idx_announcements_validity,
+  DROP COLUMN valid_until,
+  DROP COLUMN valid_from,
+  DROP COLUMN priority;
@@ -0,0 +1,5 @@
+ALTER TABLE announcements
+  ADD COLUMN priority ENUM('info','important','urgent') NOT NULL DEFAULT 'info' AFTER image,
+  ADD COLUMN valid_from TIMESTAMP NULL AFTER priority,
+  ADD COLUMN valid_until TIMESTAMP NULL AFTER valid_from,
+  ADD INDEX idx_announcements_validity (valid_from, valid_until);
@@ -0,0 +1,16 @@
+CREATE TABLE announcement_attachments (
+  id INT AUTO_INCREMENT,
+  announcement_id INT NOT NULL,
+  name VARCHAR(150) NOT NULL,
+  filename VARCHAR(255) NOT NULL,
+  stored_name VARCHAR(100) NOT NULL,
+  mime_type VARCHAR(100) NOT NULL,
+  size BIGINT NOT NULL,
+  download_count BIGINT UNSIGNED NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_announcement_attachments_stored_name (stored_name),
+  INDEX idx_announcement_attachments_announcement (announcement_id),
+  FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE
+) ENGINE = InnoDB;
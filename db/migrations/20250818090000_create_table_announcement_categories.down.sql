@@ -0,0 +1,8 @@
+ALTER TABLE announcements
+  DROP INDEX idx_announcements_pinned,
+  DROP FOREIGN KEY fk_announcements_category,
+  DROP COLUMN pinned_until,
+  DROP COLUMN pinned,
+  DROP COLUMN category_id;
+
+DROP TABLE IF EXISTS announcement_categories;
@@ -0,0 +1,23 @@
+CREATE TABLE announcement_categories (
+  id INT AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  slug VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_announcement_categories_name (name),
+  UNIQUE KEY uq_announcement_categories_slug (slug)
+) ENGINE = InnoDB;
+
+INSERT INTO announcement_categories (name, slug) VALUES
+  ('Pengumuman Resmi', 'pengumuman-resmi'),
+  ('Berita', 'berita'),
+  ('Lowongan', 'lowongan');
+
+ALTER TABLE announcements
+  ADD COLUMN category_id INT NULL AFTER image,
+  ADD COLUMN pinned TINYINT(1) NOT NULL DEFAULT 0 AFTER valid_until,
+  ADD COLUMN pinned_until TIMESTAMP NULL AFTER pinned,
+  ADD CONSTRAINT fk_announcements_category FOREIGN KEY (category_id) REFERENCES announcement_categories(id) ON DELETE SET NULL,
+  ADD INDEX idx_announcements_pinned (pinned, pinned_until);
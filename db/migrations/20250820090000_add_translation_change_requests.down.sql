@@ -0,0 +1,4 @@
+DELETE FROM change_requests WHERE item_type IN ('content_translation', 'announcement_translation');
+
+ALTER TABLE change_requests
+  MODIFY item_type ENUM('content', 'announcement') NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE change_requests
+  MODIFY item_type ENUM('content', 'announcement', 'content_translation', 'announcement_translation') NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE announcements
+  DROP INDEX idx_announcements_published_at,
+  DROP COLUMN published_at;
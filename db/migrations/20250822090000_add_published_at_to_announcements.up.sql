@@ -0,0 +1,8 @@
+ALTER TABLE announcements
+  ADD COLUMN published_at TIMESTAMP NULL AFTER pinned_until,
+  ADD INDEX idx_announcements_published_at (published_at);
+
+-- announcements already visible were announced when they were saved
+UPDATE announcements
+  SET published_at = GREATEST(created_at, COALESCE(valid_from, created_at))
+  WHERE valid_from IS NULL OR valid_from <= NOW();
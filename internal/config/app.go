@@ -1,6 +1,13 @@
 package config
 
 import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/Bangdams/web-profile-API/internal/delivery/http"
 	"github.com/Bangdams/web-profile-API/internal/delivery/http/route"
 	"github.com/Bangdams/web-profile-API/internal/repository"
@@ -10,35 +17,199 @@ import (
 	"gorm.io/gorm"
 )
 
+// BootstrapConfig carries what the app is wired from. The background
+// runners stop once Context is cancelled and Runners is done when the last
+// of them has flushed its work.
 type BootstrapConfig struct {
 	DB       *gorm.DB
 	App      *fiber.App
 	Validate *validator.Validate
+	Context  context.Context
+	Runners  *sync.WaitGroup
 }
 
 func Bootstrap(config *BootstrapConfig) {
+	run := func(runner func(ctx context.Context)) {
+		config.Runners.Add(1)
+		go func() {
+			defer config.Runners.Done()
+			runner(config.Context)
+		}()
+	}
+
 	// repo
 	adminRepo := repository.NewAdminRepository()
 	refreshTokenRepo := repository.NewRefreshTokenRepository()
 	contentRepo := repository.NewContentRepository()
+	contentPriceRepo := repository.NewContentPriceRepository()
+	contentTagRepo := repository.NewContentTagRepository()
+	contentContactRepo := repository.NewContentContactRepository()
 	announcementRepo := repository.NewAnnouncementRepository()
+	contentTranslationRepo := repository.NewContentTranslationRepository()
+	announcementTranslationRepo := repository.NewAnnouncementTranslationRepository()
+	announcementAttachmentRepo := repository.NewAnnouncementAttachmentRepository()
+	announcementCategoryRepo := repository.NewAnnouncementCategoryRepository()
+	reviewRepo := repository.NewReviewRepository()
+	viewRepo := repository.NewViewRepository()
+	changeRequestRepo := repository.NewChangeRequestRepository()
+	itemEditorRepo := repository.NewItemEditorRepository()
+	eventRepo := repository.NewEventRepository()
+	messageRepo := repository.NewMessageRepository()
+	messageNoteRepo := repository.NewMessageNoteRepository()
+	messageReplyRepo := repository.NewMessageReplyRepository()
+	subscriberRepo := repository.NewSubscriberRepository()
+	webhookRepo := repository.NewWebhookRepository()
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository()
+
+	mailer := NewMailer()
+
+	siteLinks := usecase.SiteLinks{
+		SiteURL:              os.Getenv("SITE_URL"),
+		ContentTemplate:      os.Getenv("SITE_CONTENT_URL"),
+		AnnouncementTemplate: os.Getenv("SITE_ANNOUNCEMENT_URL"),
+	}
 
 	// usecase
 	adminUsecase := usecase.NewAdminUsecase(adminRepo, refreshTokenRepo, config.DB, config.Validate)
-	contentUsecas := usecase.NewContentUsecase(contentRepo, adminRepo, config.DB, config.Validate)
-	announcementUsecase := usecase.NewAnnouncementUsecase(announcementRepo, adminRepo, config.DB, config.Validate)
+	relatedContentUsecase := usecase.NewRelatedContentUsecase(contentRepo, config.DB)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepo, webhookDeliveryRepo, adminRepo, config.DB, config.Validate)
+	run(webhookUsecase.Run)
+	newsletterUsecase := usecase.NewNewsletterUsecase(subscriberRepo, announcementRepo, contentRepo, eventRepo, adminRepo, config.DB, config.Validate, mailer, usecase.NewsletterConfig{
+		Links:               siteLinks,
+		APIURL:              apiURL(),
+		ConfirmTemplate:     os.Getenv("NEWSLETTER_CONFIRM_URL"),
+		PreferencesTemplate: os.Getenv("NEWSLETTER_PREFERENCES_URL"),
+		SiteName:            os.Getenv("SITE_NAME"),
+		MailFrom:            os.Getenv("MAIL_FROM"),
+		DigestInterval:      envDuration("NEWSLETTER_DIGEST_INTERVAL_HOURS", 24, time.Hour),
+		SendOnPublish:       envBool("NEWSLETTER_SEND_ON_PUBLISH", false),
+	})
+	run(newsletterUsecase.Run)
+	contentUsecas := usecase.NewContentUsecase(contentRepo, contentPriceRepo, contentTagRepo, contentContactRepo, changeRequestRepo, itemEditorRepo, adminRepo, relatedContentUsecase, newsletterUsecase, webhookUsecase, config.DB, config.Validate)
+	contentImportUsecase := usecase.NewContentImportUsecase(contentRepo, adminRepo, relatedContentUsecase, webhookUsecase, config.DB, config.Validate)
+	exportUsecase := usecase.NewExportUsecase(contentRepo, announcementRepo, config.DB, config.Validate)
+	announcementUsecase := usecase.NewAnnouncementUsecase(announcementRepo, announcementCategoryRepo, adminRepo, changeRequestRepo, itemEditorRepo, newsletterUsecase, webhookUsecase, config.DB, config.Validate)
+	run(announcementUsecase.Run)
+	contentTranslationUsecase := usecase.NewContentTranslationUsecase(contentTranslationRepo, contentRepo, changeRequestRepo, itemEditorRepo, adminRepo, relatedContentUsecase, config.DB, config.Validate)
+	announcementTranslationUsecase := usecase.NewAnnouncementTranslationUsecase(announcementTranslationRepo, announcementRepo, changeRequestRepo, itemEditorRepo, adminRepo, config.DB, config.Validate)
+	announcementAttachmentUsecase := usecase.NewAnnouncementAttachmentUsecase(announcementAttachmentRepo, announcementRepo, adminRepo, config.DB, config.Validate)
+	announcementCategoryUsecase := usecase.NewAnnouncementCategoryUsecase(announcementCategoryRepo, adminRepo, config.DB, config.Validate)
+	reviewUsecase := usecase.NewReviewUsecase(reviewRepo, contentRepo, relatedContentUsecase, config.DB, config.Validate)
+	viewUsecase := usecase.NewViewUsecase(viewRepo, contentRepo, announcementRepo, config.DB, envDuration("VIEW_FLUSH_INTERVAL", 30, time.Second), envDuration("VIEW_DEDUP_WINDOW", 30, time.Minute))
+	run(viewUsecase.Run)
+	trashUsecase := usecase.NewTrashUsecase(contentRepo, announcementRepo, announcementAttachmentRepo, itemEditorRepo, adminRepo, relatedContentUsecase, webhookUsecase, config.DB, envDuration("TRASH_RETENTION_DAYS", 30, 24*time.Hour))
+	run(trashUsecase.Run)
+	changeRequestUsecase := usecase.NewChangeRequestUsecase(changeRequestRepo, contentRepo, announcementRepo, contentTranslationRepo, announcementTranslationRepo, adminRepo, contentUsecas, announcementUsecase, contentTranslationUsecase, announcementTranslationUsecase, config.DB, config.Validate)
+	itemEditorUsecase := usecase.NewItemEditorUsecase(itemEditorRepo, contentRepo, announcementRepo, adminRepo, config.DB, config.Validate)
+	eventUsecase := usecase.NewEventUsecase(eventRepo, contentRepo, adminRepo, newsletterUsecase, config.DB, config.Validate, os.Getenv("SITE_NAME"))
+	messageUsecase := usecase.NewMessageUsecase(messageRepo, messageNoteRepo, messageReplyRepo, adminRepo, config.DB, config.Validate, mailer, os.Getenv("MAIL_FROM"))
+	feedUsecase := usecase.NewFeedUsecase(contentRepo, announcementRepo, config.DB, config.Validate, siteLinks, os.Getenv("SITE_NAME"), os.Getenv("FEED_GUID_HOST"))
+	sitemapUsecase := usecase.NewSitemapUsecase(contentRepo, announcementRepo, config.DB, usecase.SitemapConfig{
+		Links:          siteLinks,
+		RobotsFile:     os.Getenv("ROBOTS_TXT_FILE"),
+		RobotsDisallow: envList("ROBOTS_DISALLOW", "/api/"),
+	})
 
 	// controller
 	adminController := http.NewAdminController(adminUsecase)
-	contentController := http.NewContentController(contentUsecas)
-	announcementController := http.NewAnnouncementController(announcementUsecase)
+	contentController := http.NewContentController(contentUsecas, relatedContentUsecase, viewUsecase, usecase.UploadDir)
+	announcementController := http.NewAnnouncementController(announcementUsecase, viewUsecase, usecase.UploadDir)
+	contentTranslationController := http.NewContentTranslationController(contentTranslationUsecase)
+	announcementTranslationController := http.NewAnnouncementTranslationController(announcementTranslationUsecase)
+	announcementAttachmentController := http.NewAnnouncementAttachmentController(announcementAttachmentUsecase)
+	announcementCategoryController := http.NewAnnouncementCategoryController(announcementCategoryUsecase)
+	reviewController := http.NewReviewController(reviewUsecase)
+	contentImportController := http.NewContentImportController(contentImportUsecase, usecase.UploadDir)
+	exportController := http.NewExportController(exportUsecase)
+	trashController := http.NewTrashController(trashUsecase)
+	changeRequestController := http.NewChangeRequestController(changeRequestUsecase)
+	itemEditorController := http.NewItemEditorController(itemEditorUsecase)
+	sitemapController := http.NewSitemapController(sitemapUsecase)
+	feedController := http.NewFeedController(feedUsecase)
+	eventController := http.NewEventController(eventUsecase)
+	messageController := http.NewMessageController(messageUsecase)
+	newsletterController := http.NewNewsletterController(newsletterUsecase)
+	webhookController := http.NewWebhookController(webhookUsecase)
+	imageController := http.NewImageController(usecase.UploadDir)
 
 	routeConfig := route.RouteConfig{
-		App:                    config.App,
-		AdminController:        adminController,
-		ContentController:      contentController,
-		AnnouncementController: announcementController,
+		App:                               config.App,
+		AdminController:                   adminController,
+		ContentController:                 contentController,
+		AnnouncementController:            announcementController,
+		ContentTranslationController:      contentTranslationController,
+		AnnouncementTranslationController: announcementTranslationController,
+		AnnouncementAttachmentController:  announcementAttachmentController,
+		AnnouncementCategoryController:    announcementCategoryController,
+		ReviewController:                  reviewController,
+		ContentImportController:           contentImportController,
+		ExportController:                  exportController,
+		TrashController:                   trashController,
+		ChangeRequestController:           changeRequestController,
+		ItemEditorController:              itemEditorController,
+		SitemapController:                 sitemapController,
+		FeedController:                    feedController,
+		EventController:                   eventController,
+		MessageController:                 messageController,
+		NewsletterController:              newsletterController,
+		WebhookController:                 webhookController,
+		ImageController:                   imageController,
 	}
 
 	routeConfig.Setup()
 }
+
+// envDuration reads a whole number of units from the environment, using
+// fallback when the variable is missing or invalid.
+func envDuration(key string, fallback int, unit time.Duration) time.Duration {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		value = fallback
+	}
+
+	return time.Duration(value) * unit
+}
+
+// envBool reads a boolean from the environment, using fallback when the
+// variable is missing or invalid.
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return value
+}
+
+// apiURL is where the API is reached from outside, for links in mails sent
+// without a request at hand.
+func apiURL() string {
+	if value := os.Getenv("API_URL"); value != "" {
+		return value
+	}
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
+	return "http://localhost:" + port
+}
+
+// envList reads a comma separated list from the environment, using fallback
+// when the variable is not set. An empty value gives an empty list.
+func envList(key string, fallback string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		value = fallback
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
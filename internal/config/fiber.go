@@ -2,9 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"os"
 
 	middelware "github.com/Bangdams/web-profile-API/internal/delivery/http/middleware"
 	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -12,6 +14,16 @@ func NewFiber() *fiber.App {
 	var app = fiber.New(fiber.Config{
 		AppName:      "Quizku API",
 		ErrorHandler: NewErrorHandler(),
+		// room for an attachment of the largest size and its form fields
+		BodyLimit: usecase.AttachmentMaxSize + 1<<20,
+		// behind a reverse proxy every request comes from the proxy, so the
+		// client address is taken from its header. Only the proxies listed
+		// in TRUSTED_PROXIES are believed, anyone else could send the header
+		// and pick the address rate limits are counted against.
+		ProxyHeader:             proxyHeader(),
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          envList("TRUSTED_PROXIES", ""),
+		EnableIPValidation:      true,
 	})
 
 	middelware.Middelware(app)
@@ -36,3 +48,19 @@ func NewErrorHandler() fiber.ErrorHandler {
 		return ctx.Status(code).JSON(model.WebResponse[any]{Errors: &errorResponse})
 	}
 }
+
+// proxyHeader is the header the reverse proxy puts the client address in.
+// It defaults to X-Real-IP, which the proxy sets itself. X-Forwarded-For is
+// no safe default: proxies append to it and Fiber reads its first entry,
+// which comes from the client.
+func proxyHeader() string {
+	if len(envList("TRUSTED_PROXIES", "")) == 0 {
+		return ""
+	}
+
+	if header := os.Getenv("PROXY_HEADER"); header != "" {
+		return header
+	}
+
+	return "X-Real-IP"
+}
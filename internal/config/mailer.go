@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/Bangdams/web-profile-API/internal/util"
+)
+
+// NewMailer picks the mailer from MAILER. "file" drops every mail into
+// MAIL_DIR; "smtp" sends them to SMTP_HOST:SMTP_PORT, which defaults to
+// the port MailHog listens on.
+func NewMailer() util.Mailer {
+	switch mailer := os.Getenv("MAILER"); mailer {
+	case "", "file":
+		mailDir := os.Getenv("MAIL_DIR")
+		if mailDir == "" {
+			mailDir = "./mail"
+		}
+
+		return util.NewFileMailer(mailDir)
+	case "smtp":
+		smtpHost := os.Getenv("SMTP_HOST")
+		if smtpHost == "" {
+			smtpHost = "localhost"
+		}
+
+		smtpPort := os.Getenv("SMTP_PORT")
+		if smtpPort == "" {
+			smtpPort = "1025"
+		}
+
+		return util.NewSMTPMailer(smtpHost, smtpPort, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+	default:
+		log.Fatalf("unknown mailer: %s", mailer)
+		return nil
+	}
+}
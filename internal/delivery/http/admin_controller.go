@@ -15,6 +15,7 @@ import (
 type AdminController interface {
 	Create(ctx *fiber.Ctx) error
 	Update(ctx *fiber.Ctx) error
+	Patch(ctx *fiber.Ctx) error
 	Delete(ctx *fiber.Ctx) error
 	FindAll(ctx *fiber.Ctx) error
 	FindByUsername(ctx *fiber.Ctx) error
@@ -114,12 +115,16 @@ func (controller *AdminControllerImpl) Create(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
+	request.ActorID = adminIdFromToken(ctx)
+
 	response, err := controller.AdminUsecase.Create(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to create user")
 		return err
 	}
 
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AdminResponse]{Data: response})
 }
 
@@ -130,7 +135,12 @@ func (controller *AdminControllerImpl) Delete(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	if err := controller.AdminUsecase.Delete(ctx.UserContext(), uint(id)); err != nil {
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.AdminUsecase.Delete(ctx.UserContext(), uint(id), version, adminIdFromToken(ctx)); err != nil {
 		log.Println("failed to delete user")
 		return err
 	}
@@ -166,6 +176,8 @@ func (controller *AdminControllerImpl) FindByUsername(ctx *fiber.Ctx) error {
 		return err
 	}
 
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AdminResponse]{Data: response})
 }
 
@@ -173,16 +185,45 @@ func (controller *AdminControllerImpl) FindByUsername(ctx *fiber.Ctx) error {
 func (controller *AdminControllerImpl) Update(ctx *fiber.Ctx) error {
 	request := new(model.AdminUpdateRequest)
 
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
 	if err := ctx.BodyParser(request); err != nil {
 		log.Println("failed to parse request : ", err)
 		return fiber.ErrBadRequest
 	}
 
+	request.Version = version
+	request.ActorID = adminIdFromToken(ctx)
+
 	response, err := controller.AdminUsecase.Update(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to update admin")
 		return err
 	}
 
+	setETag(ctx, response.Version)
+
+	return ctx.JSON(model.WebResponse[*model.AdminResponse]{Data: response})
+}
+
+// Patch implements AdminController.
+func (controller *AdminControllerImpl) Patch(ctx *fiber.Ctx) error {
+	request, err := parsePatchRequest(ctx)
+	if err != nil {
+		log.Println("failed to parse request : ", err)
+		return err
+	}
+
+	response, err := controller.AdminUsecase.Patch(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to patch admin")
+		return err
+	}
+
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AdminResponse]{Data: response})
 }
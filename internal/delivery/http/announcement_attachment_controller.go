@@ -0,0 +1,183 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnnouncementAttachmentController interface {
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	Download(ctx *fiber.Ctx) error
+}
+
+type AnnouncementAttachmentControllerImpl struct {
+	AnnouncementAttachmentUsecase usecase.AnnouncementAttachmentUsecase
+}
+
+func NewAnnouncementAttachmentController(AnnouncementAttachmentUsecase usecase.AnnouncementAttachmentUsecase) AnnouncementAttachmentController {
+	return &AnnouncementAttachmentControllerImpl{
+		AnnouncementAttachmentUsecase: AnnouncementAttachmentUsecase,
+	}
+}
+
+// Create implements AnnouncementAttachmentController.
+func (controller *AnnouncementAttachmentControllerImpl) Create(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		log.Println("failed to parse request file : ", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		log.Println("failed to open attachment file : ", err)
+		return fiber.ErrInternalServerError
+	}
+	defer reader.Close()
+
+	request := &model.AnnouncementAttachmentCreateRequest{
+		AnnouncementID: uint(id),
+		Name:           ctx.FormValue("name"),
+		Filename:       file.Filename,
+		Size:           file.Size,
+		File:           reader,
+		ActorID:        adminIdFromToken(ctx),
+	}
+
+	response, err := controller.AnnouncementAttachmentUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create announcement attachment")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.AnnouncementAttachmentResponse]{Data: response})
+}
+
+// Update implements AnnouncementAttachmentController.
+func (controller *AnnouncementAttachmentControllerImpl) Update(ctx *fiber.Ctx) error {
+	request := new(model.AnnouncementAttachmentUpdateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	attachmentId, err := ctx.ParamsInt("attachment_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.AnnouncementID = uint(id)
+	request.ID = uint(attachmentId)
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.AnnouncementAttachmentUsecase.Update(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update announcement attachment")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.AnnouncementAttachmentResponse]{Data: response})
+}
+
+// Delete implements AnnouncementAttachmentController.
+func (controller *AnnouncementAttachmentControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	attachmentId, err := ctx.ParamsInt("attachment_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.AnnouncementAttachmentUsecase.Delete(ctx.UserContext(), uint(id), uint(attachmentId), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete announcement attachment")
+		return err
+	}
+
+	return nil
+}
+
+// Download implements AnnouncementAttachmentController.
+func (controller *AnnouncementAttachmentControllerImpl) Download(ctx *fiber.Ctx) error {
+	announcementId, err := ctx.ParamsInt("announcement_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	attachmentId, err := ctx.ParamsInt("attachment_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	file, err := controller.AnnouncementAttachmentUsecase.Download(ctx.UserContext(), uint(announcementId), uint(attachmentId))
+	if err != nil {
+		log.Println("failed to download announcement attachment")
+		return err
+	}
+
+	if err := ctx.SendFile(file.Path); err != nil {
+		log.Println("failed to send announcement attachment : ", err)
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, file.MimeType)
+	ctx.Set(fiber.HeaderContentDisposition, attachmentDisposition(file.Filename))
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	return nil
+}
+
+// attachmentDisposition keeps the original filename of a download. Names
+// beyond plain ASCII go in filename* as RFC 6266 asks, with an ASCII
+// fallback for older clients.
+func attachmentDisposition(filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= 0x7f || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, filename)
+
+	if fallback == filename {
+		return fmt.Sprintf(`attachment; filename="%s"`, filename)
+	}
+
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, fallback, encodeExtValue(filename))
+}
+
+// encodeExtValue percent-encodes everything but the attr-char of RFC 8187.
+func encodeExtValue(value string) string {
+	var builder strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			builder.WriteByte(c)
+			continue
+		}
+
+		fmt.Fprintf(&builder, "%%%02X", c)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,23 @@
+package http
+
+import "testing"
+
+func TestAttachmentDisposition(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"jadwal.pdf", `attachment; filename="jadwal.pdf"`},
+		{"jadwal posyandu (1).docx", `attachment; filename="jadwal posyandu (1).docx"`},
+		{`surat "resmi".pdf`, `attachment; filename="surat _resmi_.pdf"; filename*=UTF-8''surat%20%22resmi%22.pdf`},
+		{`a\b.pdf`, `attachment; filename="a_b.pdf"; filename*=UTF-8''a%5Cb.pdf`},
+		{"café.pdf", `attachment; filename="caf_.pdf"; filename*=UTF-8''caf%C3%A9.pdf`},
+		{"pengumuman\r\n.pdf", `attachment; filename="pengumuman__.pdf"; filename*=UTF-8''pengumuman%0D%0A.pdf`},
+	}
+
+	for _, test := range tests {
+		if got := attachmentDisposition(test.filename); got != test.want {
+			t.Errorf("attachmentDisposition(%q) = %q, want %q", test.filename, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnnouncementCategoryController interface {
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+}
+
+type AnnouncementCategoryControllerImpl struct {
+	AnnouncementCategoryUsecase usecase.AnnouncementCategoryUsecase
+}
+
+func NewAnnouncementCategoryController(AnnouncementCategoryUsecase usecase.AnnouncementCategoryUsecase) AnnouncementCategoryController {
+	return &AnnouncementCategoryControllerImpl{
+		AnnouncementCategoryUsecase: AnnouncementCategoryUsecase,
+	}
+}
+
+// Create implements AnnouncementCategoryController.
+func (controller *AnnouncementCategoryControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.AnnouncementCategoryRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.AnnouncementCategoryUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create announcement category")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.AnnouncementCategoryResponse]{Data: response})
+}
+
+// Update implements AnnouncementCategoryController.
+func (controller *AnnouncementCategoryControllerImpl) Update(ctx *fiber.Ctx) error {
+	request := new(model.AnnouncementCategoryRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = uint(id)
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.AnnouncementCategoryUsecase.Update(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update announcement category")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.AnnouncementCategoryResponse]{Data: response})
+}
+
+// Delete implements AnnouncementCategoryController.
+func (controller *AnnouncementCategoryControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.AnnouncementCategoryUsecase.Delete(ctx.UserContext(), uint(id), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete announcement category")
+		return err
+	}
+
+	return nil
+}
+
+// FindAll implements AnnouncementCategoryController.
+func (controller *AnnouncementCategoryControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	responses, err := controller.AnnouncementCategoryUsecase.FindAll(ctx.UserContext())
+	if err != nil {
+		log.Println("failed to find all announcement category")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.AnnouncementCategoryResponse]{Data: responses})
+}
@@ -2,18 +2,17 @@ package http
 
 import (
 	"log"
-	"path/filepath"
 	"strconv"
 
 	"github.com/Bangdams/web-profile-API/internal/model"
 	"github.com/Bangdams/web-profile-API/internal/usecase"
-	"github.com/Bangdams/web-profile-API/internal/util"
 	"github.com/gofiber/fiber/v2"
 )
 
 type AnnouncementController interface {
 	Create(ctx *fiber.Ctx) error
 	Update(ctx *fiber.Ctx) error
+	Patch(ctx *fiber.Ctx) error
 	Delete(ctx *fiber.Ctx) error
 	FindAll(ctx *fiber.Ctx) error
 	FindById(ctx *fiber.Ctx) error
@@ -22,11 +21,15 @@ type AnnouncementController interface {
 
 type AnnouncementControllerImpl struct {
 	AnnouncementUsecase usecase.AnnouncementUsecase
+	ViewUsecase         usecase.ViewUsecase
+	UploadDir           string
 }
 
-func NewAnnouncementController(AnnouncementUsecase usecase.AnnouncementUsecase) AnnouncementController {
+func NewAnnouncementController(AnnouncementUsecase usecase.AnnouncementUsecase, ViewUsecase usecase.ViewUsecase, uploadDir string) AnnouncementController {
 	return &AnnouncementControllerImpl{
 		AnnouncementUsecase: AnnouncementUsecase,
+		ViewUsecase:         ViewUsecase,
+		UploadDir:           uploadDir,
 	}
 }
 
@@ -42,7 +45,18 @@ func (controller *AnnouncementControllerImpl) Create(ctx *fiber.Ctx) error {
 
 	request.Title = ctx.FormValue("title")
 	request.Content = ctx.FormValue("content")
+	request.ContentFormat = ctx.FormValue("content_format")
+	request.Priority = ctx.FormValue("priority")
+	request.ValidFrom = ctx.FormValue("valid_from")
+	request.ValidUntil = ctx.FormValue("valid_until")
+	request.PinnedUntil = ctx.FormValue("pinned_until")
+
+	if err := parseAnnouncementForm(ctx, request); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
 	request.PublishedBy = uint(publishedBy)
+	request.ActorID = adminIdFromToken(ctx)
 
 	// upload image
 	file, err := ctx.FormFile("image")
@@ -51,23 +65,23 @@ func (controller *AnnouncementControllerImpl) Create(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "image is required"})
 	}
 
-	filename := filepath.Base(file.Filename)
-	generateFilename := util.GenerateRandomFilename(filename)
-	savePath := filepath.Join("./upload", generateFilename)
-
-	if err := ctx.SaveFile(file, savePath); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save image"})
+	image, err := saveImage(controller.UploadDir, file)
+	if err != nil {
+		return imageError(ctx, err)
 	}
 
-	request.Image = generateFilename
+	request.Image = image
 	// end upload image
 
 	response, err := controller.AnnouncementUsecase.Create(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to create announcement")
-		return err
+		discardImage(controller.UploadDir, image, err)
+		return pendingChange(ctx, err)
 	}
 
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AnnouncementResponse]{Data: response})
 }
 
@@ -78,7 +92,12 @@ func (controller *AnnouncementControllerImpl) Delete(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	if err := controller.AnnouncementUsecase.Delete(ctx.UserContext(), uint(id)); err != nil {
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.AnnouncementUsecase.Delete(ctx.UserContext(), uint(id), version, adminIdFromToken(ctx)); err != nil {
 		log.Println("failed to delete announcement")
 		return err
 	}
@@ -91,13 +110,21 @@ func (controller *AnnouncementControllerImpl) FindAll(ctx *fiber.Ctx) error {
 	var responses *[]model.AnnouncementResponse
 	var err error
 
-	order := ctx.Query("order")
-	responses, err = controller.AnnouncementUsecase.FindAll(ctx.UserContext(), order)
+	request := &model.AnnouncementFilterRequest{
+		Order:    ctx.Query("order"),
+		Priority: ctx.Query("priority"),
+		Category: ctx.Query("category"),
+		Locale:   resolveLocale(ctx),
+	}
+
+	responses, err = controller.AnnouncementUsecase.FindAll(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to find all announcement")
 		return err
 	}
 
+	ctx.Set(fiber.HeaderContentLanguage, request.Locale)
+
 	return ctx.JSON(model.WebResponses[model.AnnouncementResponse]{Data: responses})
 }
 
@@ -108,23 +135,32 @@ func (controller *AnnouncementControllerImpl) FindById(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	response, err := controller.AnnouncementUsecase.FindById(ctx.UserContext(), uint(announcementId))
+	response, err := controller.AnnouncementUsecase.FindById(ctx.UserContext(), uint(announcementId), resolveLocale(ctx))
 	if err != nil {
 		log.Println("failed to find by id announcement")
 		return err
 	}
 
+	controller.ViewUsecase.Record(usecase.ViewItemAnnouncement, response.ID, ctx.IP())
+	ctx.Set(fiber.HeaderContentLanguage, response.Locale)
+
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AnnouncementResponse]{Data: response})
 }
 
 // GetFirst implements AnnouncementController.
 func (controller *AnnouncementControllerImpl) GetFirst(ctx *fiber.Ctx) error {
-	response, err := controller.AnnouncementUsecase.GetFirst(ctx.UserContext())
+	response, err := controller.AnnouncementUsecase.GetFirst(ctx.UserContext(), resolveLocale(ctx))
 	if err != nil {
 		log.Println("failed to find by id announcement")
 		return err
 	}
 
+	ctx.Set(fiber.HeaderContentLanguage, response.Locale)
+
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AnnouncementResponse]{Data: response})
 }
 
@@ -132,6 +168,11 @@ func (controller *AnnouncementControllerImpl) GetFirst(ctx *fiber.Ctx) error {
 func (controller *AnnouncementControllerImpl) Update(ctx *fiber.Ctx) error {
 	request := new(model.AnnouncementUpdateRequest)
 
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
 	publishedBy, err := strconv.Atoi(ctx.FormValue("published_by"))
 	if err != nil {
 		log.Println("error bad request : ", err)
@@ -147,32 +188,88 @@ func (controller *AnnouncementControllerImpl) Update(ctx *fiber.Ctx) error {
 	request.ID = uint(id)
 	request.Title = ctx.FormValue("title")
 	request.Content = ctx.FormValue("content")
+	request.ContentFormat = ctx.FormValue("content_format")
+	request.Priority = ctx.FormValue("priority")
+	request.ValidFrom = ctx.FormValue("valid_from")
+	request.ValidUntil = ctx.FormValue("valid_until")
+	request.PinnedUntil = ctx.FormValue("pinned_until")
+
+	if err := parseAnnouncementForm(ctx, &request.AnnouncementCreateRequest); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
 	request.PublishedBy = uint(publishedBy)
+	request.ActorID = adminIdFromToken(ctx)
 
 	// upload image
-	var filename string
-	filename = ctx.FormValue("image_name")
+	uploaded := ""
 	file, err := ctx.FormFile("image")
 	if err == nil {
-		filename = filepath.Base(file.Filename)
-		generateFilename := util.GenerateRandomFilename(filename)
-		savePath := filepath.Join("./upload", generateFilename)
-
-		if err := ctx.SaveFile(file, savePath); err != nil {
-			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save image"})
+		uploaded, err = saveImage(controller.UploadDir, file)
+		if err != nil {
+			return imageError(ctx, err)
 		}
 
-		request.Image = generateFilename
+		request.Image = uploaded
 	} else {
-		request.Image = filename
+		request.Image = ctx.FormValue("image_name")
 	}
 	// end upload image
 
+	request.Version = version
+
 	response, err := controller.AnnouncementUsecase.Update(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to create announcement")
+		discardImage(controller.UploadDir, uploaded, err)
+		return pendingChange(ctx, err)
+	}
+
+	setETag(ctx, response.Version)
+
+	return ctx.JSON(model.WebResponse[*model.AnnouncementResponse]{Data: response})
+}
+
+// Patch implements AnnouncementController.
+func (controller *AnnouncementControllerImpl) Patch(ctx *fiber.Ctx) error {
+	request, err := parsePatchRequest(ctx)
+	if err != nil {
+		log.Println("failed to parse request : ", err)
 		return err
 	}
 
+	response, err := controller.AnnouncementUsecase.Patch(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to patch announcement")
+		return pendingChange(ctx, err)
+	}
+
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.AnnouncementResponse]{Data: response})
 }
+
+// parseAnnouncementForm reads the optional category and the pinned flag of
+// an announcement form. An empty category_id leaves it uncategorized.
+func parseAnnouncementForm(ctx *fiber.Ctx, request *model.AnnouncementCreateRequest) error {
+	if value := ctx.FormValue("category_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return err
+		}
+
+		categoryId := uint(id)
+		request.CategoryID = &categoryId
+	}
+
+	if value := ctx.FormValue("pinned"); value != "" {
+		pinned, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		request.Pinned = pinned
+	}
+
+	return nil
+}
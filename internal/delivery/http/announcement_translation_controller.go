@@ -0,0 +1,112 @@
+package http
+
+import (
+	"log"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnnouncementTranslationController interface {
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+}
+
+type AnnouncementTranslationControllerImpl struct {
+	AnnouncementTranslationUsecase usecase.AnnouncementTranslationUsecase
+}
+
+func NewAnnouncementTranslationController(AnnouncementTranslationUsecase usecase.AnnouncementTranslationUsecase) AnnouncementTranslationController {
+	return &AnnouncementTranslationControllerImpl{
+		AnnouncementTranslationUsecase: AnnouncementTranslationUsecase,
+	}
+}
+
+// Create implements AnnouncementTranslationController.
+func (controller *AnnouncementTranslationControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.TranslationRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ItemID = uint(id)
+	request.Locale = strings.ToLower(ctx.Params("locale"))
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.AnnouncementTranslationUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create announcement translation")
+		return pendingChange(ctx, err)
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TranslationResponse]{Data: response})
+}
+
+// Update implements AnnouncementTranslationController.
+func (controller *AnnouncementTranslationControllerImpl) Update(ctx *fiber.Ctx) error {
+	request := new(model.TranslationRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ItemID = uint(id)
+	request.Locale = strings.ToLower(ctx.Params("locale"))
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.AnnouncementTranslationUsecase.Update(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update announcement translation")
+		return pendingChange(ctx, err)
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TranslationResponse]{Data: response})
+}
+
+// Delete implements AnnouncementTranslationController.
+func (controller *AnnouncementTranslationControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.AnnouncementTranslationUsecase.Delete(ctx.UserContext(), uint(id), strings.ToLower(ctx.Params("locale")), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete announcement translation")
+		return err
+	}
+
+	return nil
+}
+
+// FindAll implements AnnouncementTranslationController.
+func (controller *AnnouncementTranslationControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := controller.AnnouncementTranslationUsecase.FindAll(ctx.UserContext(), uint(id))
+	if err != nil {
+		log.Println("failed to find all announcement translation")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TranslationListResponse]{Data: response})
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"errors"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ChangeRequestController interface {
+	FindPending(ctx *fiber.Ctx) error
+	FindSubmitted(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+	Approve(ctx *fiber.Ctx) error
+	Reject(ctx *fiber.Ctx) error
+}
+
+type ChangeRequestControllerImpl struct {
+	ChangeRequestUsecase usecase.ChangeRequestUsecase
+}
+
+func NewChangeRequestController(ChangeRequestUsecase usecase.ChangeRequestUsecase) ChangeRequestController {
+	return &ChangeRequestControllerImpl{
+		ChangeRequestUsecase: ChangeRequestUsecase,
+	}
+}
+
+// FindPending implements ChangeRequestController.
+func (controller *ChangeRequestControllerImpl) FindPending(ctx *fiber.Ctx) error {
+	responses, err := controller.ChangeRequestUsecase.FindPending(ctx.UserContext(), ctx.Query("type"), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to find pending change request")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.ChangeRequestResponse]{Data: responses})
+}
+
+// FindSubmitted implements ChangeRequestController.
+func (controller *ChangeRequestControllerImpl) FindSubmitted(ctx *fiber.Ctx) error {
+	responses, err := controller.ChangeRequestUsecase.FindSubmitted(ctx.UserContext(), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to find submitted change request")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.ChangeRequestResponse]{Data: responses})
+}
+
+// FindById implements ChangeRequestController.
+func (controller *ChangeRequestControllerImpl) FindById(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := controller.ChangeRequestUsecase.FindById(ctx.UserContext(), uint(id), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to find by id change request")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ChangeRequestResponse]{Data: response})
+}
+
+// Approve implements ChangeRequestController.
+func (controller *ChangeRequestControllerImpl) Approve(ctx *fiber.Ctx) error {
+	return controller.review(ctx, "approved")
+}
+
+// Reject implements ChangeRequestController.
+func (controller *ChangeRequestControllerImpl) Reject(ctx *fiber.Ctx) error {
+	return controller.review(ctx, "rejected")
+}
+
+func (controller *ChangeRequestControllerImpl) review(ctx *fiber.Ctx, status string) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request := new(model.ChangeReviewRequest)
+
+	// the comment is optional on approval, so an empty body is fine
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(request); err != nil {
+			log.Println("failed to parse request : ", err)
+			return fiber.ErrBadRequest
+		}
+	}
+
+	request.ID = uint(id)
+	request.Status = status
+	request.ReviewedBy = adminIdFromToken(ctx)
+
+	response, err := controller.ChangeRequestUsecase.Review(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to review change request")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ChangeRequestResponse]{Data: response})
+}
+
+// pendingChange answers 202 Accepted with the queued change request when a
+// create or update was sent for review instead of being applied, and passes
+// any other error on.
+func pendingChange(ctx *fiber.Ctx, err error) error {
+	var pending *usecase.ChangePendingError
+	if errors.As(err, &pending) {
+		return ctx.Status(fiber.StatusAccepted).JSON(model.WebResponse[*model.ChangeRequestResponse]{Data: pending.Change})
+	}
+
+	return err
+}
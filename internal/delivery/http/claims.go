@@ -0,0 +1,16 @@
+package http
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// adminIdFromToken returns the id of the admin whose access token was
+// verified by the jwt middleware on the /api group.
+func adminIdFromToken(ctx *fiber.Ctx) uint {
+	adminToken := ctx.Locals("admin").(*jwt.Token)
+	claims := adminToken.Claims.(jwt.MapClaims)
+	adminId := claims["admin_id"].(float64)
+
+	return uint(adminId)
+}
@@ -1,32 +1,45 @@
 package http
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
-	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/Bangdams/web-profile-API/internal/model"
 	"github.com/Bangdams/web-profile-API/internal/usecase"
-	"github.com/Bangdams/web-profile-API/internal/util"
 	"github.com/gofiber/fiber/v2"
 )
 
 type ContentController interface {
 	Create(ctx *fiber.Ctx) error
 	Update(ctx *fiber.Ctx) error
+	Patch(ctx *fiber.Ctx) error
 	Delete(ctx *fiber.Ctx) error
 	FindAll(ctx *fiber.Ctx) error
 	FindWithLimit(ctx *fiber.Ctx) error
 	FindById(ctx *fiber.Ctx) error
+	VCard(ctx *fiber.Ctx) error
+	FindFeatured(ctx *fiber.Ctx) error
+	FindPopular(ctx *fiber.Ctx) error
+	FindRelated(ctx *fiber.Ctx) error
+	Reorder(ctx *fiber.Ctx) error
 }
 
 type ContentControllerImpl struct {
-	ContentUsecase usecase.ContentUsecase
+	ContentUsecase        usecase.ContentUsecase
+	RelatedContentUsecase usecase.RelatedContentUsecase
+	ViewUsecase           usecase.ViewUsecase
+	UploadDir             string
 }
 
-func NewContentController(ContentUsecase usecase.ContentUsecase) ContentController {
+func NewContentController(ContentUsecase usecase.ContentUsecase, RelatedContentUsecase usecase.RelatedContentUsecase, ViewUsecase usecase.ViewUsecase, uploadDir string) ContentController {
 	return &ContentControllerImpl{
-		ContentUsecase: ContentUsecase,
+		ContentUsecase:        ContentUsecase,
+		RelatedContentUsecase: RelatedContentUsecase,
+		ViewUsecase:           ViewUsecase,
+		UploadDir:             uploadDir,
 	}
 }
 
@@ -37,15 +50,108 @@ func (controller *ContentControllerImpl) FindById(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	response, err := controller.ContentUsecase.FindById(ctx.UserContext(), uint(contentId))
+	response, err := controller.ContentUsecase.FindById(ctx.UserContext(), uint(contentId), resolveLocale(ctx))
 	if err != nil {
 		log.Println("failed to find by id content")
 		return err
 	}
 
+	controller.ViewUsecase.Record(usecase.ViewItemContent, response.ID, ctx.IP())
+	ctx.Set(fiber.HeaderContentLanguage, response.Locale)
+
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.ContentResponse]{Data: response})
 }
 
+// VCard implements ContentController.
+func (controller *ContentControllerImpl) VCard(ctx *fiber.Ctx) error {
+	contentId, err := ctx.ParamsInt("content_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	card, err := controller.ContentUsecase.FindVCard(ctx.UserContext(), uint(contentId))
+	if err != nil {
+		log.Println("failed to find vcard content")
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, "text/vcard; charset=utf-8")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="content-%d.vcf"`, contentId))
+
+	return ctx.SendString(card)
+}
+
+// FindFeatured implements ContentController.
+func (controller *ContentControllerImpl) FindFeatured(ctx *fiber.Ctx) error {
+	locale := resolveLocale(ctx)
+
+	response, err := controller.ContentUsecase.FindFeatured(ctx.UserContext(), locale)
+	if err != nil {
+		log.Println("failed to find featured content")
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentLanguage, locale)
+
+	return ctx.JSON(model.WebResponse[map[string][]model.ContentResponse]{Data: response})
+}
+
+// FindPopular implements ContentController.
+func (controller *ContentControllerImpl) FindPopular(ctx *fiber.Ctx) error {
+	locale := resolveLocale(ctx)
+
+	responses, err := controller.ContentUsecase.FindPopular(ctx.UserContext(), ctx.Query("period"), ctx.Query("category"), locale)
+	if err != nil {
+		log.Println("failed to find popular content")
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentLanguage, locale)
+
+	return ctx.JSON(model.WebResponses[model.ContentResponse]{Data: responses})
+}
+
+// FindRelated implements ContentController.
+func (controller *ContentControllerImpl) FindRelated(ctx *fiber.Ctx) error {
+	contentId, err := ctx.ParamsInt("content_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	locale := resolveLocale(ctx)
+
+	responses, err := controller.RelatedContentUsecase.FindRelated(ctx.UserContext(), uint(contentId), locale)
+	if err != nil {
+		log.Println("failed to find related content")
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentLanguage, locale)
+
+	return ctx.JSON(model.WebResponses[model.ContentResponse]{Data: responses})
+}
+
+// Reorder implements ContentController.
+func (controller *ContentControllerImpl) Reorder(ctx *fiber.Ctx) error {
+	request := new(model.ContentReorderRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ActorID = adminIdFromToken(ctx)
+
+	if err := controller.ContentUsecase.Reorder(ctx.UserContext(), request); err != nil {
+		log.Println("failed to reorder content")
+		return err
+	}
+
+	return nil
+}
+
 // Create implements ContentController.
 func (controller *ContentControllerImpl) Create(ctx *fiber.Ctx) error {
 	request := new(model.ContentCreateRequest)
@@ -58,10 +164,40 @@ func (controller *ContentControllerImpl) Create(ctx *fiber.Ctx) error {
 
 	request.Title = ctx.FormValue("title")
 	request.Content = ctx.FormValue("description")
+	request.ContentFormat = ctx.FormValue("content_format")
 	request.Address = ctx.FormValue("address")
 	request.ContactInfo = ctx.FormValue("contact_info")
 	request.Category = ctx.FormValue("category")
 	request.CreatedBy = uint(createdBy)
+	request.ActorID = adminIdFromToken(ctx)
+
+	if err := parseContentPricing(ctx, &request.IsFree, &request.Currency, &request.PriceLevel, &request.Prices); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	isFeatured, position, err := parseContentPlacement(ctx)
+	if err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	if isFeatured != nil {
+		request.IsFeatured = *isFeatured
+	}
+	if position != nil {
+		request.Position = *position
+	}
+
+	if err := parseContentLocation(ctx, &request.Tags, &request.Latitude, &request.Longitude); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := parseContentContacts(ctx, &request.Contacts); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
 
 	// upload image
 	file, err := ctx.FormFile("image")
@@ -70,23 +206,23 @@ func (controller *ContentControllerImpl) Create(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "image is required"})
 	}
 
-	filename := filepath.Base(file.Filename)
-	generateFilename := util.GenerateRandomFilename(filename)
-	savePath := filepath.Join("./upload", generateFilename)
-
-	if err := ctx.SaveFile(file, savePath); err != nil {
-		return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save image"})
+	image, err := saveImage(controller.UploadDir, file)
+	if err != nil {
+		return imageError(ctx, err)
 	}
 
-	request.Image = generateFilename
+	request.Image = image
 	// end upload image
 
 	response, err := controller.ContentUsecase.Create(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to create content")
-		return err
+		discardImage(controller.UploadDir, image, err)
+		return pendingChange(ctx, err)
 	}
 
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.ContentResponse]{Data: response})
 }
 
@@ -97,7 +233,12 @@ func (controller *ContentControllerImpl) Delete(ctx *fiber.Ctx) error {
 		return fiber.ErrBadRequest
 	}
 
-	if err := controller.ContentUsecase.Delete(ctx.UserContext(), uint(id)); err != nil {
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.ContentUsecase.Delete(ctx.UserContext(), uint(id), version, adminIdFromToken(ctx)); err != nil {
 		log.Println("failed to delete content")
 		return err
 	}
@@ -110,14 +251,20 @@ func (controller *ContentControllerImpl) FindAll(ctx *fiber.Ctx) error {
 	var responses *[]model.ContentResponse
 	var err error
 
-	order := ctx.Query("order")
-	category := ctx.Query("category")
-	responses, err = controller.ContentUsecase.FindAll(ctx.UserContext(), order, category)
+	request, err := parseContentFilter(ctx)
+	if err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	responses, err = controller.ContentUsecase.FindAll(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to find all content")
 		return err
 	}
 
+	ctx.Set(fiber.HeaderContentLanguage, request.Locale)
+
 	return ctx.JSON(model.WebResponses[model.ContentResponse]{Data: responses})
 }
 
@@ -126,14 +273,20 @@ func (controller *ContentControllerImpl) FindWithLimit(ctx *fiber.Ctx) error {
 	var responses *[]model.ContentResponse
 	var err error
 
-	order := ctx.Query("order")
-	category := ctx.Query("category")
-	responses, err = controller.ContentUsecase.FindWithLimit(ctx.UserContext(), order, category)
+	request, err := parseContentFilter(ctx)
+	if err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	responses, err = controller.ContentUsecase.FindWithLimit(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to FindWithLimit content")
 		return err
 	}
 
+	ctx.Set(fiber.HeaderContentLanguage, request.Locale)
+
 	return ctx.JSON(model.WebResponses[model.ContentResponse]{Data: responses})
 }
 
@@ -141,6 +294,11 @@ func (controller *ContentControllerImpl) FindWithLimit(ctx *fiber.Ctx) error {
 func (controller *ContentControllerImpl) Update(ctx *fiber.Ctx) error {
 	request := new(model.ContentUpdateRequest)
 
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
 	createdBy, err := strconv.Atoi(ctx.FormValue("created_by"))
 	if err != nil {
 		log.Println("error bad request : ", err)
@@ -156,35 +314,197 @@ func (controller *ContentControllerImpl) Update(ctx *fiber.Ctx) error {
 	request.ID = uint(id)
 	request.Title = ctx.FormValue("title")
 	request.Content = ctx.FormValue("description")
+	request.ContentFormat = ctx.FormValue("content_format")
 	request.Address = ctx.FormValue("address")
 	request.ContactInfo = ctx.FormValue("contact_info")
 	request.Category = ctx.FormValue("category")
 	request.CreatedBy = uint(createdBy)
+	request.ActorID = adminIdFromToken(ctx)
+
+	if err := parseContentPricing(ctx, &request.IsFree, &request.Currency, &request.PriceLevel, &request.Prices); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.IsFeatured, request.Position, err = parseContentPlacement(ctx)
+	if err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := parseContentLocation(ctx, &request.Tags, &request.Latitude, &request.Longitude); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := parseContentContacts(ctx, &request.Contacts); err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
 
 	// upload image
-	var filename string
-	filename = ctx.FormValue("image_name")
+	uploaded := ""
 	file, err := ctx.FormFile("image")
 	if err == nil {
-		filename = filepath.Base(file.Filename)
-		generateFilename := util.GenerateRandomFilename(filename)
-		savePath := filepath.Join("./upload", generateFilename)
-
-		if err := ctx.SaveFile(file, savePath); err != nil {
-			return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save image"})
+		uploaded, err = saveImage(controller.UploadDir, file)
+		if err != nil {
+			return imageError(ctx, err)
 		}
 
-		request.Image = generateFilename
+		request.Image = uploaded
 	} else {
-		request.Image = filename
+		request.Image = ctx.FormValue("image_name")
 	}
 	// end upload image
 
+	request.Version = version
+
 	response, err := controller.ContentUsecase.Update(ctx.UserContext(), request)
 	if err != nil {
 		log.Println("failed to create content")
+		discardImage(controller.UploadDir, uploaded, err)
+		return pendingChange(ctx, err)
+	}
+
+	setETag(ctx, response.Version)
+
+	return ctx.JSON(model.WebResponse[*model.ContentResponse]{Data: response})
+}
+
+func parseContentFilter(ctx *fiber.Ctx) (*model.ContentFilterRequest, error) {
+	request := &model.ContentFilterRequest{
+		Order:    ctx.Query("order"),
+		Category: ctx.Query("category"),
+		Sort:     ctx.Query("sort"),
+		Locale:   resolveLocale(ctx),
+	}
+
+	if value := ctx.Query("min_price"); value != "" {
+		minPrice, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		request.MinPrice = &minPrice
+	}
+
+	if value := ctx.Query("max_price"); value != "" {
+		maxPrice, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		request.MaxPrice = &maxPrice
+	}
+
+	return request, nil
+}
+
+// parseContentPricing reads the optional pricing fields of the multipart form,
+// "prices" being a JSON array such as [{"tier":"adult","amount":10000}].
+func parseContentPricing(ctx *fiber.Ctx, isFree *bool, currency *string, priceLevel *uint8, prices *[]model.ContentPriceRequest) error {
+	var err error
+
+	if value := ctx.FormValue("is_free"); value != "" {
+		if *isFree, err = strconv.ParseBool(value); err != nil {
+			return err
+		}
+	}
+
+	if value := ctx.FormValue("price_level"); value != "" {
+		level, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return err
+		}
+		*priceLevel = uint8(level)
+	}
+
+	*currency = ctx.FormValue("currency")
+
+	if value := ctx.FormValue("prices"); value != "" {
+		if err := json.Unmarshal([]byte(value), prices); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseContentContacts reads the optional "contacts" JSON array such as
+// [{"type":"whatsapp","value":"08123456789","label":"Reservasi"}].
+func parseContentContacts(ctx *fiber.Ctx, contacts *[]model.ContentContactRequest) error {
+	if value := ctx.FormValue("contacts"); value != "" {
+		return json.Unmarshal([]byte(value), contacts)
+	}
+
+	return nil
+}
+
+// parseContentPlacement reads the optional "is_featured" and "position" of
+// the multipart form, nil for those left out.
+func parseContentPlacement(ctx *fiber.Ctx) (*bool, *int, error) {
+	var isFeatured *bool
+	if value := ctx.FormValue("is_featured"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		isFeatured = &parsed
+	}
+
+	var position *int
+	if value := ctx.FormValue("position"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, nil, err
+		}
+		position = &parsed
+	}
+
+	return isFeatured, position, nil
+}
+
+// parseContentLocation reads the comma separated "tags" and the optional
+// "latitude" and "longitude" of the multipart form.
+func parseContentLocation(ctx *fiber.Ctx, tags *[]string, latitude **float64, longitude **float64) error {
+	for _, tag := range strings.Split(ctx.FormValue("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			*tags = append(*tags, tag)
+		}
+	}
+
+	if value := ctx.FormValue("latitude"); value != "" {
+		lat, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*latitude = &lat
+	}
+
+	if value := ctx.FormValue("longitude"); value != "" {
+		lng, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		*longitude = &lng
+	}
+
+	return nil
+}
+
+// Patch implements ContentController.
+func (controller *ContentControllerImpl) Patch(ctx *fiber.Ctx) error {
+	request, err := parsePatchRequest(ctx)
+	if err != nil {
+		log.Println("failed to parse request : ", err)
 		return err
 	}
 
+	response, err := controller.ContentUsecase.Patch(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to patch content")
+		return pendingChange(ctx, err)
+	}
+
+	setETag(ctx, response.Version)
+
 	return ctx.JSON(model.WebResponse[*model.ContentResponse]{Data: response})
 }
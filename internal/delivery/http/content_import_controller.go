@@ -0,0 +1,112 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ContentImportController interface {
+	Import(ctx *fiber.Ctx) error
+}
+
+type ContentImportControllerImpl struct {
+	ContentImportUsecase usecase.ContentImportUsecase
+	UploadDir            string
+}
+
+func NewContentImportController(ContentImportUsecase usecase.ContentImportUsecase, uploadDir string) ContentImportController {
+	return &ContentImportControllerImpl{
+		ContentImportUsecase: ContentImportUsecase,
+		UploadDir:            uploadDir,
+	}
+}
+
+// Import implements ContentImportController.
+func (controller *ContentImportControllerImpl) Import(ctx *fiber.Ctx) error {
+	request := &model.ContentImportRequest{
+		Mode:      ctx.FormValue("mode", "atomic"),
+		CreatedBy: adminIdFromToken(ctx),
+	}
+
+	if value := ctx.FormValue("dry_run"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Println("error bad request : ", err)
+			return fiber.ErrBadRequest
+		}
+		request.DryRun = dryRun
+	}
+
+	// mapping is a JSON object from spreadsheet header to field, e.g. {"Nama":"title"}
+	if value := ctx.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &request.Mapping); err != nil {
+			log.Println("error bad request : ", err)
+			return fiber.ErrBadRequest
+		}
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		log.Println("failed to parse request file : ", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "file is required"})
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		log.Println("failed to open import file : ", err)
+		return fiber.ErrInternalServerError
+	}
+	defer reader.Close()
+
+	request.Rows, err = util.ReadSpreadsheet(file.Filename, reader)
+	if err != nil {
+		log.Println("failed to read import file : ", err)
+		if errors.Is(err, util.ErrUnsupportedSpreadsheet) {
+			return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "failed to read file"})
+	}
+
+	// rows without an image column fall back to this uploaded image, it is
+	// only saved when the import actually writes rows
+	var image *util.UploadedImage
+	if file, err := ctx.FormFile("default_image"); err == nil {
+		reader, err := file.Open()
+		if err != nil {
+			log.Println("failed to open default image : ", err)
+			return fiber.ErrInternalServerError
+		}
+		defer reader.Close()
+
+		if image, err = util.DecodeImage(reader); err != nil {
+			return imageError(ctx, err)
+		}
+
+		request.DefaultImage = image.Name
+	}
+
+	response, err := controller.ContentImportUsecase.Import(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to import content")
+		return err
+	}
+
+	if image != nil && response.Imported > 0 {
+		if err := image.Save(controller.UploadDir); err != nil {
+			return imageError(ctx, err)
+		}
+	}
+
+	if !response.DryRun && response.Imported == 0 && len(response.Errors) > 0 {
+		ctx.Status(fiber.StatusUnprocessableEntity)
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ContentImportResponse]{Data: response})
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"log"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ContentTranslationController interface {
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+}
+
+type ContentTranslationControllerImpl struct {
+	ContentTranslationUsecase usecase.ContentTranslationUsecase
+}
+
+func NewContentTranslationController(ContentTranslationUsecase usecase.ContentTranslationUsecase) ContentTranslationController {
+	return &ContentTranslationControllerImpl{
+		ContentTranslationUsecase: ContentTranslationUsecase,
+	}
+}
+
+// Create implements ContentTranslationController.
+func (controller *ContentTranslationControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.TranslationRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ItemID = uint(id)
+	request.Locale = strings.ToLower(ctx.Params("locale"))
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.ContentTranslationUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create content translation")
+		return pendingChange(ctx, err)
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TranslationResponse]{Data: response})
+}
+
+// Update implements ContentTranslationController.
+func (controller *ContentTranslationControllerImpl) Update(ctx *fiber.Ctx) error {
+	request := new(model.TranslationRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ItemID = uint(id)
+	request.Locale = strings.ToLower(ctx.Params("locale"))
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.ContentTranslationUsecase.Update(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update content translation")
+		return pendingChange(ctx, err)
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TranslationResponse]{Data: response})
+}
+
+// Delete implements ContentTranslationController.
+func (controller *ContentTranslationControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.ContentTranslationUsecase.Delete(ctx.UserContext(), uint(id), strings.ToLower(ctx.Params("locale")), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete content translation")
+		return err
+	}
+
+	return nil
+}
+
+// FindAll implements ContentTranslationController.
+func (controller *ContentTranslationControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := controller.ContentTranslationUsecase.FindAll(ctx.UserContext(), uint(id))
+	if err != nil {
+		log.Println("failed to find all content translation")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.TranslationListResponse]{Data: response})
+}
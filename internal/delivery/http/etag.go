@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// setETag exposes the row version as a strong entity tag.
+func setETag(ctx *fiber.Ctx, version uint) {
+	ctx.Set(fiber.HeaderETag, strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// ifMatchVersion reads the version an editor last saw from If-Match. Writes
+// without it are refused with 428, "*" matches any version and is returned as
+// zero.
+func ifMatchVersion(ctx *fiber.Ctx) (uint, error) {
+	header := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		errorResponse := model.ErrorResponse{
+			Message: "Precondition required",
+			Details: []string{"the If-Match header with the ETag of the data is required"},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return 0, fiber.NewError(fiber.StatusPreconditionRequired, string(jsonString))
+	}
+
+	if header == "*" {
+		return 0, nil
+	}
+
+	// weak tags never match under the strong comparison If-Match requires
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		if version, err := strconv.ParseUint(unquoted, 10, 32); err == nil && version > 0 {
+			return uint(version), nil
+		}
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "Precondition failed",
+		Details: []string{"the If-Match header does not match the current ETag"},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return 0, fiber.NewError(fiber.StatusPreconditionFailed, string(jsonString))
+}
+
+// notModified answers a conditional GET. If-None-Match takes precedence over
+// If-Modified-Since, as RFC 9110 asks, and uses the weak comparison.
+func notModified(ctx *fiber.Ctx, etag string, lastModified time.Time) bool {
+	if header := ctx.Get(fiber.HeaderIfNoneMatch); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	since, err := http.ParseTime(ctx.Get(fiber.HeaderIfModifiedSince))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type EventController interface {
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+	FindRange(ctx *fiber.Ctx) error
+	Calendar(ctx *fiber.Ctx) error
+	EventCalendar(ctx *fiber.Ctx) error
+}
+
+type EventControllerImpl struct {
+	EventUsecase usecase.EventUsecase
+}
+
+func NewEventController(EventUsecase usecase.EventUsecase) EventController {
+	return &EventControllerImpl{
+		EventUsecase: EventUsecase,
+	}
+}
+
+// Create implements EventController.
+func (controller *EventControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.EventCreateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.EventUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create event")
+		return err
+	}
+
+	setETag(ctx, response.Version)
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.EventResponse]{Data: response})
+}
+
+// Update implements EventController.
+func (controller *EventControllerImpl) Update(ctx *fiber.Ctx) error {
+	request := new(model.EventUpdateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	request.ID = uint(id)
+	request.Version = version
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.EventUsecase.Update(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update event")
+		return err
+	}
+
+	setETag(ctx, response.Version)
+
+	return ctx.JSON(model.WebResponse[*model.EventResponse]{Data: response})
+}
+
+// Delete implements EventController.
+func (controller *EventControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.EventUsecase.Delete(ctx.UserContext(), uint(id), version, adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete event")
+		return err
+	}
+
+	return nil
+}
+
+// FindAll implements EventController.
+func (controller *EventControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	responses, err := controller.EventUsecase.FindAll(ctx.UserContext())
+	if err != nil {
+		log.Println("failed to find all event")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.EventResponse]{Data: responses})
+}
+
+// FindById implements EventController.
+func (controller *EventControllerImpl) FindById(ctx *fiber.Ctx) error {
+	eventId, err := ctx.ParamsInt("event_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := controller.EventUsecase.FindById(ctx.UserContext(), uint(eventId))
+	if err != nil {
+		log.Println("failed to find by id event")
+		return err
+	}
+
+	setETag(ctx, response.Version)
+
+	return ctx.JSON(model.WebResponse[*model.EventResponse]{Data: response})
+}
+
+// FindRange implements EventController.
+func (controller *EventControllerImpl) FindRange(ctx *fiber.Ctx) error {
+	request := &model.EventRangeRequest{
+		From: ctx.Query("from"),
+		To:   ctx.Query("to"),
+	}
+
+	responses, err := controller.EventUsecase.FindRange(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to find range event")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.EventResponse]{Data: responses})
+}
+
+// Calendar implements EventController.
+func (controller *EventControllerImpl) Calendar(ctx *fiber.Ctx) error {
+	return controller.calendar(ctx, 0)
+}
+
+// EventCalendar implements EventController.
+func (controller *EventControllerImpl) EventCalendar(ctx *fiber.Ctx) error {
+	eventId, err := ctx.ParamsInt("event_id")
+	if err != nil || eventId <= 0 {
+		return fiber.ErrBadRequest
+	}
+
+	return controller.calendar(ctx, uint(eventId))
+}
+
+func (controller *EventControllerImpl) calendar(ctx *fiber.Ctx, eventId uint) error {
+	request := &model.CalendarRequest{
+		EventID: eventId,
+		BaseURL: ctx.BaseURL(),
+	}
+
+	response, err := controller.EventUsecase.Calendar(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to build event calendar")
+		return err
+	}
+
+	return sendCacheable(ctx, response)
+}
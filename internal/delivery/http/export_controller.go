@@ -0,0 +1,106 @@
+package http
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportController interface {
+	ExportContents(ctx *fiber.Ctx) error
+	ExportAnnouncements(ctx *fiber.Ctx) error
+}
+
+type ExportControllerImpl struct {
+	ExportUsecase usecase.ExportUsecase
+}
+
+func NewExportController(ExportUsecase usecase.ExportUsecase) ExportController {
+	return &ExportControllerImpl{
+		ExportUsecase: ExportUsecase,
+	}
+}
+
+// ExportContents implements ExportController.
+func (controller *ExportControllerImpl) ExportContents(ctx *fiber.Ctx) error {
+	filter, err := parseContentFilter(ctx)
+	if err != nil {
+		log.Println("error bad request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request := &model.ContentExportRequest{
+		Format:  ctx.Query("format", util.ExportFormatCSV),
+		Columns: parseExportColumns(ctx),
+		Filter:  *filter,
+	}
+
+	export, err := controller.ExportUsecase.ExportContents(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to export content")
+		return err
+	}
+
+	return streamExport(ctx, "contents", request.Format, export)
+}
+
+// ExportAnnouncements implements ExportController.
+func (controller *ExportControllerImpl) ExportAnnouncements(ctx *fiber.Ctx) error {
+	request := &model.AnnouncementExportRequest{
+		Format:  ctx.Query("format", util.ExportFormatCSV),
+		Columns: parseExportColumns(ctx),
+		Filter: model.AnnouncementFilterRequest{
+			Order:    ctx.Query("order"),
+			Priority: ctx.Query("priority"),
+			Category: ctx.Query("category"),
+		},
+	}
+
+	export, err := controller.ExportUsecase.ExportAnnouncements(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to export announcement")
+		return err
+	}
+
+	return streamExport(ctx, "announcements", request.Format, export)
+}
+
+// parseExportColumns reads the comma separated "columns" query parameter.
+func parseExportColumns(ctx *fiber.Ctx) []string {
+	var columns []string
+	for _, column := range strings.Split(ctx.Query("columns"), ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, strings.ToLower(column))
+		}
+	}
+
+	return columns
+}
+
+// streamExport sends the export as an attachment, writing rows to the
+// connection as they are read instead of buffering the whole body.
+func streamExport(ctx *fiber.Ctx, name string, format string, export usecase.ExportFunc) error {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+
+	ctx.Set(fiber.HeaderContentType, util.ExportContentType(format))
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	ctx.Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		if err := export(writer); err != nil {
+			log.Println("failed to stream export : ", err)
+		}
+
+		if err := writer.Flush(); err != nil {
+			log.Println("failed to flush export : ", err)
+		}
+	})
+
+	return nil
+}
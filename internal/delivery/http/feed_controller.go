@@ -0,0 +1,104 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net/http"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type FeedController interface {
+	Announcements(ctx *fiber.Ctx) error
+	Contents(ctx *fiber.Ctx) error
+}
+
+type FeedControllerImpl struct {
+	FeedUsecase usecase.FeedUsecase
+}
+
+func NewFeedController(feedUsecase usecase.FeedUsecase) FeedController {
+	return &FeedControllerImpl{
+		FeedUsecase: feedUsecase,
+	}
+}
+
+// Announcements implements FeedController.
+func (controller *FeedControllerImpl) Announcements(ctx *fiber.Ctx) error {
+	request, err := parseFeedRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := controller.FeedUsecase.Announcements(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to build announcement feed")
+		return err
+	}
+
+	return sendFeed(ctx, request, response)
+}
+
+// Contents implements FeedController.
+func (controller *FeedControllerImpl) Contents(ctx *fiber.Ctx) error {
+	request, err := parseFeedRequest(ctx)
+	if err != nil {
+		return err
+	}
+	request.Category = ctx.Params("category")
+
+	response, err := controller.FeedUsecase.Contents(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to build content feed")
+		return err
+	}
+
+	return sendFeed(ctx, request, response)
+}
+
+// parseFeedRequest takes the format from the extension of the path, so an
+// unknown extension is a missing page rather than a bad request.
+func parseFeedRequest(ctx *fiber.Ctx) (*model.FeedRequest, error) {
+	format := ctx.Params("format")
+	if format != usecase.FeedFormatRSS && format != usecase.FeedFormatAtom {
+		return nil, fiber.ErrNotFound
+	}
+
+	return &model.FeedRequest{
+		Format:  format,
+		Locale:  resolveLocale(ctx),
+		SelfURL: ctx.BaseURL() + ctx.OriginalURL(),
+		BaseURL: ctx.BaseURL(),
+	}, nil
+}
+
+// sendFeed sends a feed in the locale it was written in.
+func sendFeed(ctx *fiber.Ctx, request *model.FeedRequest, response *model.FeedResponse) error {
+	ctx.Set(fiber.HeaderContentLanguage, request.Locale)
+
+	return sendCacheable(ctx, response)
+}
+
+// sendCacheable tags a generated document with a hash of its body and its
+// last change and answers 304 when the client already has it.
+func sendCacheable(ctx *fiber.Ctx, response *model.FeedResponse) error {
+	sum := sha256.Sum256([]byte(response.Body))
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	ctx.Set(fiber.HeaderETag, etag)
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if !response.LastModified.IsZero() {
+		ctx.Set(fiber.HeaderLastModified, response.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(ctx, etag, response.LastModified) {
+		return ctx.SendStatus(fiber.StatusNotModified)
+	}
+
+	ctx.Set(fiber.HeaderContentType, response.ContentType)
+
+	return ctx.SendString(response.Body)
+}
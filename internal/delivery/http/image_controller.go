@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImageController interface {
+	Serve(ctx *fiber.Ctx) error
+}
+
+type ImageControllerImpl struct {
+	UploadDir string
+}
+
+func NewImageController(uploadDir string) ImageController {
+	return &ImageControllerImpl{
+		UploadDir: uploadDir,
+	}
+}
+
+// Serve implements ImageController. Browsers that accept WebP get the WebP
+// copy of the file, others the file itself. Sizes an older upload was never
+// stored in fall back to the upload as it is.
+func (controller *ImageControllerImpl) Serve(ctx *fiber.Ctx) error {
+	filename := filepath.Base(ctx.Params("filename"))
+	if !controller.exists(filename) {
+		filename = util.ImageOriginalName(filename)
+	}
+
+	ctx.Vary(fiber.HeaderAccept)
+
+	if webpName := util.WebPName(filename); webpName != filename && acceptsWebP(ctx.Get(fiber.HeaderAccept)) && controller.exists(webpName) {
+		filename = webpName
+	}
+
+	if err := ctx.SendFile(filepath.Join(controller.UploadDir, filename)); err != nil {
+		log.Println("failed to send image : ", err)
+		return err
+	}
+
+	if filepath.Ext(filename) == ".webp" {
+		ctx.Set(fiber.HeaderContentType, util.MimeTypeWebP)
+	}
+
+	return nil
+}
+
+func (controller *ImageControllerImpl) exists(filename string) bool {
+	info, err := os.Stat(filepath.Join(controller.UploadDir, filename))
+	return err == nil && !info.IsDir()
+}
+
+// acceptsWebP looks for image/webp itself in the Accept header: a bare */*
+// is sent by clients that can not show WebP as well.
+func acceptsWebP(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), util.MimeTypeWebP) {
+			continue
+		}
+
+		for _, param := range params[1:] {
+			if key, value, _ := strings.Cut(strings.TrimSpace(param), "="); key == "q" && strings.Trim(strings.TrimSpace(value), "0.") == "" {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// saveImage stores an uploaded image in all its sizes and returns the name
+// rows refer to it by.
+func saveImage(uploadDir string, file *multipart.FileHeader) (string, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	image, err := util.DecodeImage(reader)
+	if err != nil {
+		return "", err
+	}
+
+	if err := image.Save(uploadDir); err != nil {
+		return "", err
+	}
+
+	return image.Name, nil
+}
+
+// discardImage removes an image saved for a request the usecase turned
+// down. A change queued for review refers to the image, so it is kept then.
+func discardImage(uploadDir string, image string, err error) {
+	var pending *usecase.ChangePendingError
+	if image == "" || errors.As(err, &pending) {
+		return
+	}
+
+	for _, filename := range util.ImageFiles([]string{image}) {
+		if err := os.Remove(filepath.Join(uploadDir, filename)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Println("failed to remove image : ", err)
+		}
+	}
+}
+
+// imageError answers a failed saveImage or DecodeImage: files that are no
+// image are the client's fault, anything else is ours.
+func imageError(ctx *fiber.Ctx, err error) error {
+	if errors.Is(err, util.ErrUnsupportedImage) || errors.Is(err, util.ErrImageTooLarge) {
+		return ctx.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	log.Println("failed to save image : ", err)
+	return ctx.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed to save image"})
+}
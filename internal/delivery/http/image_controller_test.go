@@ -0,0 +1,29 @@
+package http
+
+import "testing"
+
+func TestAcceptsWebP(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"image/*", false},
+		{"image/png,image/jpeg", false},
+		{"image/webp", true},
+		{"image/avif,image/webp,image/apng,*/*;q=0.8", true},
+		{"IMAGE/WEBP", true},
+		{"image/png, image/webp ;q=0.5", true},
+		{"image/webp;q=0", false},
+		{"image/webp; q=0.000", false},
+		{"image/webp;q=0.001", true},
+		{"image/webpx", false},
+	}
+
+	for _, test := range tests {
+		if got := acceptsWebP(test.accept); got != test.want {
+			t.Errorf("acceptsWebP(%q) = %v, want %v", test.accept, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ItemEditorController interface {
+	FindAll(ctx *fiber.Ctx) error
+	Assign(ctx *fiber.Ctx) error
+	Remove(ctx *fiber.Ctx) error
+}
+
+type ItemEditorControllerImpl struct {
+	ItemEditorUsecase usecase.ItemEditorUsecase
+}
+
+func NewItemEditorController(ItemEditorUsecase usecase.ItemEditorUsecase) ItemEditorController {
+	return &ItemEditorControllerImpl{
+		ItemEditorUsecase: ItemEditorUsecase,
+	}
+}
+
+// FindAll implements ItemEditorController.
+func (controller *ItemEditorControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	responses, err := controller.ItemEditorUsecase.FindAll(ctx.UserContext(), ctx.Params("type"), uint(id), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to find all item editor")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.ItemEditorResponse]{Data: responses})
+}
+
+// Assign implements ItemEditorController.
+func (controller *ItemEditorControllerImpl) Assign(ctx *fiber.Ctx) error {
+	request, err := parseItemEditorRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := controller.ItemEditorUsecase.Assign(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to assign item editor")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.ItemEditorResponse]{Data: response})
+}
+
+// Remove implements ItemEditorController.
+func (controller *ItemEditorControllerImpl) Remove(ctx *fiber.Ctx) error {
+	request, err := parseItemEditorRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := controller.ItemEditorUsecase.Remove(ctx.UserContext(), request); err != nil {
+		log.Println("failed to remove item editor")
+		return err
+	}
+
+	return nil
+}
+
+func parseItemEditorRequest(ctx *fiber.Ctx) (*model.ItemEditorRequest, error) {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return nil, fiber.ErrBadRequest
+	}
+
+	adminId, err := ctx.ParamsInt("admin_id")
+	if err != nil {
+		return nil, fiber.ErrBadRequest
+	}
+
+	return &model.ItemEditorRequest{
+		ItemType: ctx.Params("type"),
+		ItemID:   uint(id),
+		AdminID:  uint(adminId),
+		ActorID:  adminIdFromToken(ctx),
+	}, nil
+}
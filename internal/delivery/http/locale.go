@@ -0,0 +1,24 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+// resolveLocale picks the response locale from ?lang= first, then from the
+// Accept-Language header, and falls back to the default locale.
+func resolveLocale(ctx *fiber.Ctx) string {
+	ctx.Vary(fiber.HeaderAcceptLanguage)
+
+	if lang := strings.ToLower(ctx.Query("lang")); util.IsSupportedLocale(lang) {
+		return lang
+	}
+
+	if lang := ctx.AcceptsLanguages(util.SupportedLocales...); lang != "" {
+		return lang
+	}
+
+	return util.DefaultLocale
+}
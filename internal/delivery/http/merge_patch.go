@@ -0,0 +1,37 @@
+package http
+
+import (
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+const mimeMergePatchJSON = "application/merge-patch+json"
+
+// parsePatchRequest reads a JSON Merge Patch body for the item in the "id"
+// route parameter along with its If-Match version and the admin sending it.
+// Plain application/json is accepted as well.
+func parsePatchRequest(ctx *fiber.Ctx) (*model.PatchRequest, error) {
+	id, err := ctx.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return nil, fiber.ErrBadRequest
+	}
+
+	contentType := strings.ToLower(ctx.Get(fiber.HeaderContentType))
+	if !strings.HasPrefix(contentType, mimeMergePatchJSON) && !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+		return nil, fiber.ErrUnsupportedMediaType
+	}
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PatchRequest{
+		ID:      uint(id),
+		Version: version,
+		Patch:   append([]byte(nil), ctx.Body()...),
+		ActorID: adminIdFromToken(ctx),
+	}, nil
+}
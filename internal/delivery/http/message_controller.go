@@ -0,0 +1,201 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type MessageController interface {
+	Create(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+	UpdateState(ctx *fiber.Ctx) error
+	Assign(ctx *fiber.Ctx) error
+	AddNote(ctx *fiber.Ctx) error
+	Reply(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+}
+
+type MessageControllerImpl struct {
+	MessageUsecase usecase.MessageUsecase
+}
+
+func NewMessageController(MessageUsecase usecase.MessageUsecase) MessageController {
+	return &MessageControllerImpl{
+		MessageUsecase: MessageUsecase,
+	}
+}
+
+// Create implements MessageController.
+func (controller *MessageControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.MessageCreateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.IPAddress = ctx.IP()
+	request.UserAgent = ctx.Get(fiber.HeaderUserAgent)
+
+	if err := controller.MessageUsecase.Create(ctx.UserContext(), request); err != nil {
+		log.Println("failed to create message")
+		return err
+	}
+
+	return ctx.SendStatus(fiber.StatusAccepted)
+}
+
+// FindAll implements MessageController.
+func (controller *MessageControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	request := &model.MessageFilterRequest{
+		Folder:     ctx.Query("folder"),
+		Status:     ctx.Query("status"),
+		AssignedTo: ctx.Query("assigned_to"),
+		ActorID:    adminIdFromToken(ctx),
+	}
+
+	responses, err := controller.MessageUsecase.FindAll(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to find all message")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.MessageResponse]{Data: responses})
+}
+
+// FindById implements MessageController.
+func (controller *MessageControllerImpl) FindById(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := controller.MessageUsecase.FindById(ctx.UserContext(), uint(id))
+	if err != nil {
+		log.Println("failed to find by id message")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.MessageResponse]{Data: response})
+}
+
+// UpdateState implements MessageController.
+func (controller *MessageControllerImpl) UpdateState(ctx *fiber.Ctx) error {
+	request := new(model.MessageStateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = uint(id)
+
+	response, err := controller.MessageUsecase.UpdateState(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update state message")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.MessageResponse]{Data: response})
+}
+
+// Assign implements MessageController.
+func (controller *MessageControllerImpl) Assign(ctx *fiber.Ctx) error {
+	request := new(model.MessageAssignRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = uint(id)
+
+	response, err := controller.MessageUsecase.Assign(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to assign message")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.MessageResponse]{Data: response})
+}
+
+// AddNote implements MessageController.
+func (controller *MessageControllerImpl) AddNote(ctx *fiber.Ctx) error {
+	request := new(model.MessageNoteRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.MessageID = uint(id)
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.MessageUsecase.AddNote(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to add note message")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.MessageNoteResponse]{Data: response})
+}
+
+// Reply implements MessageController.
+func (controller *MessageControllerImpl) Reply(ctx *fiber.Ctx) error {
+	request := new(model.MessageReplyRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.MessageID = uint(id)
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.MessageUsecase.Reply(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to reply message")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.MessageReplyResponse]{Data: response})
+}
+
+// Delete implements MessageController.
+func (controller *MessageControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.MessageUsecase.Delete(ctx.UserContext(), uint(id)); err != nil {
+		log.Println("failed to delete message")
+		return err
+	}
+
+	return nil
+}
@@ -12,7 +12,8 @@ func Middelware(app *fiber.App) {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://127.0.0.1:5500", // asal frontend
 		AllowCredentials: true,
-		AllowHeaders:     "Content-Type",
+		AllowHeaders:     "Content-Type, If-Match",
+		ExposeHeaders:    "ETag",
 	}))
 
 	app.Use("/api", jwtware.New(jwtware.Config{
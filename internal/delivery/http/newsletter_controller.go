@@ -0,0 +1,179 @@
+package http
+
+import (
+	"errors"
+	"html"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type NewsletterController interface {
+	Subscribe(ctx *fiber.Ctx) error
+	Confirm(ctx *fiber.Ctx) error
+	FindPreferences(ctx *fiber.Ctx) error
+	UpdatePreferences(ctx *fiber.Ctx) error
+	UnsubscribePage(ctx *fiber.Ctx) error
+	Unsubscribe(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	SendDigest(ctx *fiber.Ctx) error
+}
+
+type NewsletterControllerImpl struct {
+	NewsletterUsecase usecase.NewsletterUsecase
+}
+
+func NewNewsletterController(NewsletterUsecase usecase.NewsletterUsecase) NewsletterController {
+	return &NewsletterControllerImpl{
+		NewsletterUsecase: NewsletterUsecase,
+	}
+}
+
+// Subscribe implements NewsletterController.
+func (controller *NewsletterControllerImpl) Subscribe(ctx *fiber.Ctx) error {
+	request := new(model.SubscribeRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.NewsletterUsecase.Subscribe(ctx.UserContext(), request); err != nil {
+		log.Println("failed to subscribe newsletter")
+		return err
+	}
+
+	return ctx.SendStatus(fiber.StatusAccepted)
+}
+
+// Confirm implements NewsletterController.
+func (controller *NewsletterControllerImpl) Confirm(ctx *fiber.Ctx) error {
+	response, err := controller.NewsletterUsecase.Confirm(ctx.UserContext(), ctx.Params("token"))
+	if err != nil {
+		log.Println("failed to confirm newsletter")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SubscriberResponse]{Data: response})
+}
+
+// FindPreferences implements NewsletterController.
+func (controller *NewsletterControllerImpl) FindPreferences(ctx *fiber.Ctx) error {
+	response, err := controller.NewsletterUsecase.FindByToken(ctx.UserContext(), ctx.Params("token"))
+	if err != nil {
+		log.Println("failed to find preferences newsletter")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SubscriberResponse]{Data: response})
+}
+
+// UpdatePreferences implements NewsletterController.
+func (controller *NewsletterControllerImpl) UpdatePreferences(ctx *fiber.Ctx) error {
+	request := new(model.SubscriberPreferenceRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.Token = ctx.Params("token")
+
+	response, err := controller.NewsletterUsecase.UpdatePreferences(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update preferences newsletter")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.SubscriberResponse]{Data: response})
+}
+
+// UnsubscribePage implements NewsletterController. The link in the mail
+// only asks for a confirmation, as link scanners of mail providers open
+// every link they find; the button on the page does the unsubscribing.
+func (controller *NewsletterControllerImpl) UnsubscribePage(ctx *fiber.Ctx) error {
+	response, err := controller.NewsletterUsecase.FindByToken(ctx.UserContext(), ctx.Params("token"))
+	if err != nil {
+		var fiberError *fiber.Error
+		if errors.As(err, &fiberError) && fiberError.Code == fiber.StatusNotFound {
+			return sendNewsletterPage(ctx, "You are not subscribed", "<p>This address does not receive the newsletter.</p>")
+		}
+
+		log.Println("failed to find subscriber newsletter")
+		return err
+	}
+
+	return sendNewsletterPage(ctx, "Unsubscribe",
+		"<p>Stop sending the newsletter to "+html.EscapeString(response.Email)+"?</p>\n"+
+			"<form method=\"post\"><button type=\"submit\">Unsubscribe</button></form>")
+}
+
+// Unsubscribe implements NewsletterController. It serves both the button on
+// the confirmation page and the one-click POST of mail clients (RFC 8058).
+func (controller *NewsletterControllerImpl) Unsubscribe(ctx *fiber.Ctx) error {
+	if err := controller.NewsletterUsecase.Unsubscribe(ctx.UserContext(), ctx.Params("token")); err != nil {
+		log.Println("failed to unsubscribe newsletter")
+		return err
+	}
+
+	if ctx.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML {
+		return sendNewsletterPage(ctx, "Unsubscribed", "<p>You have been unsubscribed.</p>")
+	}
+
+	return ctx.JSON(fiber.Map{"message": "you have been unsubscribed"})
+}
+
+// sendNewsletterPage answers a newsletter link opened in a browser with a
+// bare page. The body is HTML already escaped by the caller.
+func sendNewsletterPage(ctx *fiber.Ctx, title string, body string) error {
+	ctx.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+
+	return ctx.SendString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<meta name=\"robots\" content=\"noindex\">\n" +
+		"<title>" + html.EscapeString(title) + "</title>\n</head>\n<body>\n<h1>" + html.EscapeString(title) + "</h1>\n" +
+		body + "\n</body>\n</html>\n")
+}
+
+// FindAll implements NewsletterController.
+func (controller *NewsletterControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	request := &model.SubscriberFilterRequest{
+		Status:  ctx.Query("status"),
+		ActorID: adminIdFromToken(ctx),
+	}
+
+	responses, err := controller.NewsletterUsecase.FindAll(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to find all subscriber")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.SubscriberResponse]{Data: responses})
+}
+
+// Delete implements NewsletterController.
+func (controller *NewsletterControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.NewsletterUsecase.Delete(ctx.UserContext(), uint(id), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete subscriber")
+		return err
+	}
+
+	return nil
+}
+
+// SendDigest implements NewsletterController.
+func (controller *NewsletterControllerImpl) SendDigest(ctx *fiber.Ctx) error {
+	response, err := controller.NewsletterUsecase.SendDigest(ctx.UserContext(), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to send digest")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.DigestResponse]{Data: response})
+}
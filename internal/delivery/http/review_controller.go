@@ -0,0 +1,126 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewController interface {
+	Create(ctx *fiber.Ctx) error
+	Approve(ctx *fiber.Ctx) error
+	Reject(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	FindByContentId(ctx *fiber.Ctx) error
+}
+
+type ReviewControllerImpl struct {
+	ReviewUsecase usecase.ReviewUsecase
+}
+
+func NewReviewController(ReviewUsecase usecase.ReviewUsecase) ReviewController {
+	return &ReviewControllerImpl{
+		ReviewUsecase: ReviewUsecase,
+	}
+}
+
+// Create implements ReviewController.
+func (controller *ReviewControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.ReviewCreateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	contentId, err := ctx.ParamsInt("content_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ContentID = uint(contentId)
+
+	response, err := controller.ReviewUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create review")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusAccepted).JSON(model.WebResponse[*model.ReviewResponse]{Data: response})
+}
+
+// Approve implements ReviewController.
+func (controller *ReviewControllerImpl) Approve(ctx *fiber.Ctx) error {
+	return controller.moderate(ctx, "approved")
+}
+
+// Reject implements ReviewController.
+func (controller *ReviewControllerImpl) Reject(ctx *fiber.Ctx) error {
+	return controller.moderate(ctx, "rejected")
+}
+
+func (controller *ReviewControllerImpl) moderate(ctx *fiber.Ctx, status string) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request := &model.ReviewModerateRequest{
+		ID:          uint(id),
+		Status:      status,
+		ModeratedBy: adminIdFromToken(ctx),
+	}
+
+	response, err := controller.ReviewUsecase.Moderate(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to moderate review")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.ReviewResponse]{Data: response})
+}
+
+// Delete implements ReviewController.
+func (controller *ReviewControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.ReviewUsecase.Delete(ctx.UserContext(), uint(id)); err != nil {
+		log.Println("failed to delete review")
+		return err
+	}
+
+	return nil
+}
+
+// FindAll implements ReviewController.
+func (controller *ReviewControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	responses, err := controller.ReviewUsecase.FindAll(ctx.UserContext(), ctx.Query("status"))
+	if err != nil {
+		log.Println("failed to find all review")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.ReviewResponse]{Data: responses})
+}
+
+// FindByContentId implements ReviewController.
+func (controller *ReviewControllerImpl) FindByContentId(ctx *fiber.Ctx) error {
+	contentId, err := ctx.ParamsInt("content_id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	responses, err := controller.ReviewUsecase.FindByContentId(ctx.UserContext(), uint(contentId))
+	if err != nil {
+		log.Println("failed to find by content id review")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.ReviewResponse]{Data: responses})
+}
@@ -1,17 +1,32 @@
 package route
 
 import (
-	"path/filepath"
-
 	"github.com/Bangdams/web-profile-API/internal/delivery/http"
 	"github.com/gofiber/fiber/v2"
 )
 
 type RouteConfig struct {
-	App                    *fiber.App
-	AdminController        http.AdminController
-	ContentController      http.ContentController
-	AnnouncementController http.AnnouncementController
+	App                               *fiber.App
+	AdminController                   http.AdminController
+	ContentController                 http.ContentController
+	AnnouncementController            http.AnnouncementController
+	ContentTranslationController      http.ContentTranslationController
+	AnnouncementTranslationController http.AnnouncementTranslationController
+	AnnouncementAttachmentController  http.AnnouncementAttachmentController
+	AnnouncementCategoryController    http.AnnouncementCategoryController
+	ReviewController                  http.ReviewController
+	ContentImportController           http.ContentImportController
+	ExportController                  http.ExportController
+	TrashController                   http.TrashController
+	ChangeRequestController           http.ChangeRequestController
+	ItemEditorController              http.ItemEditorController
+	SitemapController                 http.SitemapController
+	FeedController                    http.FeedController
+	EventController                   http.EventController
+	MessageController                 http.MessageController
+	NewsletterController              http.NewsletterController
+	WebhookController                 http.WebhookController
+	ImageController                   http.ImageController
 }
 
 func (config *RouteConfig) Setup() {
@@ -29,27 +44,126 @@ func (config *RouteConfig) Setup() {
 	config.App.Post("/api/admins", config.AdminController.Create)
 	config.App.Delete("/api/admins/:id", config.AdminController.Delete)
 	config.App.Put("/api/admins", config.AdminController.Update)
+	config.App.Patch("/api/admins/:id", config.AdminController.Patch)
 
 	// API for content
 	config.App.Get("contents", config.ContentController.FindAll)
 	config.App.Get("contents/limit", config.ContentController.FindWithLimit)
+	config.App.Get("contents/featured", config.ContentController.FindFeatured)
+	config.App.Get("contents/popular", config.ContentController.FindPopular)
 	config.App.Get("contents/:content_id", config.ContentController.FindById)
+	config.App.Get("contents/:content_id/related", config.ContentController.FindRelated)
+	config.App.Get("contents/:content_id/vcard", config.ContentController.VCard)
 	config.App.Post("/api/contents", config.ContentController.Create)
 	config.App.Delete("/api/contents/:id", config.ContentController.Delete)
 	config.App.Put("/api/contents", config.ContentController.Update)
+	config.App.Patch("/api/contents/:id", config.ContentController.Patch)
+	config.App.Put("/api/contents/reorder", config.ContentController.Reorder)
+	config.App.Post("/api/contents/import", config.ContentImportController.Import)
+	config.App.Get("/api/contents/export", config.ExportController.ExportContents)
+	config.App.Get("/api/contents/:id/translations", config.ContentTranslationController.FindAll)
+	config.App.Post("/api/contents/:id/translations/:locale", config.ContentTranslationController.Create)
+	config.App.Put("/api/contents/:id/translations/:locale", config.ContentTranslationController.Update)
+	config.App.Delete("/api/contents/:id/translations/:locale", config.ContentTranslationController.Delete)
 
 	// API for announcement
 	config.App.Get("announcements", config.AnnouncementController.FindAll)
 	config.App.Get("announcements/first", config.AnnouncementController.GetFirst)
+	config.App.Get("announcements/categories", config.AnnouncementCategoryController.FindAll)
 	config.App.Get("announcements/:announcement_id", config.AnnouncementController.FindById)
+	config.App.Get("announcements/:announcement_id/attachments/:attachment_id", config.AnnouncementAttachmentController.Download)
 	config.App.Post("/api/announcements", config.AnnouncementController.Create)
 	config.App.Delete("/api/announcements/:id", config.AnnouncementController.Delete)
 	config.App.Put("/api/announcements", config.AnnouncementController.Update)
+	config.App.Patch("/api/announcements/:id", config.AnnouncementController.Patch)
+	config.App.Get("/api/announcements/export", config.ExportController.ExportAnnouncements)
+	config.App.Get("/api/announcements/:id/translations", config.AnnouncementTranslationController.FindAll)
+	config.App.Post("/api/announcements/:id/translations/:locale", config.AnnouncementTranslationController.Create)
+	config.App.Put("/api/announcements/:id/translations/:locale", config.AnnouncementTranslationController.Update)
+	config.App.Delete("/api/announcements/:id/translations/:locale", config.AnnouncementTranslationController.Delete)
+	config.App.Post("/api/announcements/:id/attachments", config.AnnouncementAttachmentController.Create)
+	config.App.Put("/api/announcements/:id/attachments/:attachment_id", config.AnnouncementAttachmentController.Update)
+	config.App.Delete("/api/announcements/:id/attachments/:attachment_id", config.AnnouncementAttachmentController.Delete)
+	config.App.Post("/api/announcements/categories", config.AnnouncementCategoryController.Create)
+	config.App.Put("/api/announcements/categories/:id", config.AnnouncementCategoryController.Update)
+	config.App.Delete("/api/announcements/categories/:id", config.AnnouncementCategoryController.Delete)
+
+	// API for event
+	config.App.Get("events", config.EventController.FindRange)
+	config.App.Get("events.ics", config.EventController.Calendar)
+	config.App.Get("events/:event_id.ics", config.EventController.EventCalendar)
+	config.App.Get("events/:event_id", config.EventController.FindById)
+	config.App.Get("/api/events", config.EventController.FindAll)
+	config.App.Post("/api/events", config.EventController.Create)
+	config.App.Put("/api/events/:id", config.EventController.Update)
+	config.App.Delete("/api/events/:id", config.EventController.Delete)
+
+	// API for contact messages
+	config.App.Post("messages", config.MessageController.Create)
+	config.App.Get("/api/messages", config.MessageController.FindAll)
+	config.App.Get("/api/messages/:id", config.MessageController.FindById)
+	config.App.Put("/api/messages/:id/state", config.MessageController.UpdateState)
+	config.App.Put("/api/messages/:id/assign", config.MessageController.Assign)
+	config.App.Post("/api/messages/:id/notes", config.MessageController.AddNote)
+	config.App.Post("/api/messages/:id/replies", config.MessageController.Reply)
+	config.App.Delete("/api/messages/:id", config.MessageController.Delete)
+
+	// API for newsletter
+	config.App.Post("newsletter/subscribe", config.NewsletterController.Subscribe)
+	config.App.Get("newsletter/confirm/:token", config.NewsletterController.Confirm)
+	config.App.Get("newsletter/preferences/:token", config.NewsletterController.FindPreferences)
+	config.App.Put("newsletter/preferences/:token", config.NewsletterController.UpdatePreferences)
+	config.App.Get("newsletter/unsubscribe/:token", config.NewsletterController.UnsubscribePage)
+	config.App.Post("newsletter/unsubscribe/:token", config.NewsletterController.Unsubscribe)
+	config.App.Get("/api/subscribers", config.NewsletterController.FindAll)
+	config.App.Delete("/api/subscribers/:id", config.NewsletterController.Delete)
+	config.App.Post("/api/subscribers/digest", config.NewsletterController.SendDigest)
+
+	// API for webhooks
+	config.App.Get("/api/webhooks", config.WebhookController.FindAll)
+	config.App.Post("/api/webhooks", config.WebhookController.Create)
+	config.App.Get("/api/webhooks/:id", config.WebhookController.FindById)
+	config.App.Put("/api/webhooks/:id", config.WebhookController.Update)
+	config.App.Delete("/api/webhooks/:id", config.WebhookController.Delete)
+	config.App.Get("/api/webhooks/:id/deliveries", config.WebhookController.FindDeliveries)
+	config.App.Get("/api/webhooks/:id/deliveries/:delivery_id", config.WebhookController.FindDelivery)
+	config.App.Post("/api/webhooks/:id/deliveries/:delivery_id/redeliver", config.WebhookController.Redeliver)
+
+	// API for review
+	config.App.Get("contents/:content_id/reviews", config.ReviewController.FindByContentId)
+	config.App.Post("contents/:content_id/reviews", config.ReviewController.Create)
+	config.App.Get("/api/reviews", config.ReviewController.FindAll)
+	config.App.Put("/api/reviews/:id/approve", config.ReviewController.Approve)
+	config.App.Put("/api/reviews/:id/reject", config.ReviewController.Reject)
+	config.App.Delete("/api/reviews/:id", config.ReviewController.Delete)
+
+	// API for editorial review of content and announcement changes
+	config.App.Get("/api/reviews/pending", config.ChangeRequestController.FindPending)
+	config.App.Get("/api/reviews/submitted", config.ChangeRequestController.FindSubmitted)
+	config.App.Get("/api/reviews/pending/:id", config.ChangeRequestController.FindById)
+	config.App.Put("/api/reviews/pending/:id/approve", config.ChangeRequestController.Approve)
+	config.App.Put("/api/reviews/pending/:id/reject", config.ChangeRequestController.Reject)
+
+	// API for co-editors of contents and announcements
+	config.App.Get("/api/editors/:type/:id", config.ItemEditorController.FindAll)
+	config.App.Put("/api/editors/:type/:id/:admin_id", config.ItemEditorController.Assign)
+	config.App.Delete("/api/editors/:type/:id/:admin_id", config.ItemEditorController.Remove)
+
+	// API for trash
+	config.App.Get("/api/trash", config.TrashController.FindAll)
+	config.App.Put("/api/trash/:type/:id/restore", config.TrashController.Restore)
+	config.App.Delete("/api/trash/:type/:id", config.TrashController.Purge)
+
+	// Sitemap and robots.txt for crawlers
+	config.App.Get("/sitemap.xml", config.SitemapController.Index)
+	config.App.Get("/sitemap-:page.xml", config.SitemapController.Page)
+	config.App.Get("/robots.txt", config.SitemapController.Robots)
+
+	// RSS and Atom feeds
+	config.App.Get("/feeds/announcements.:format", config.FeedController.Announcements)
+	config.App.Get("/feeds/contents.:format", config.FeedController.Contents)
+	config.App.Get("/feeds/contents/:category.:format", config.FeedController.Contents)
 
 	// API for image
-	config.App.Get("/assets/image/:filename", func(ctx *fiber.Ctx) error {
-		filename := ctx.Params("filename")
-		filepath := filepath.Join("./upload", filename)
-		return ctx.SendFile(filepath)
-	})
+	config.App.Get("/assets/image/:filename", config.ImageController.Serve)
 }
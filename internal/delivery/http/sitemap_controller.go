@@ -0,0 +1,66 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SitemapController interface {
+	Index(ctx *fiber.Ctx) error
+	Page(ctx *fiber.Ctx) error
+	Robots(ctx *fiber.Ctx) error
+}
+
+type SitemapControllerImpl struct {
+	SitemapUsecase usecase.SitemapUsecase
+}
+
+func NewSitemapController(sitemapUsecase usecase.SitemapUsecase) SitemapController {
+	return &SitemapControllerImpl{
+		SitemapUsecase: sitemapUsecase,
+	}
+}
+
+// Index implements SitemapController.
+func (controller *SitemapControllerImpl) Index(ctx *fiber.Ctx) error {
+	body, err := controller.SitemapUsecase.Index(ctx.UserContext(), ctx.BaseURL())
+	if err != nil {
+		log.Println("failed to build sitemap")
+		return err
+	}
+
+	return sendSitemap(ctx, body)
+}
+
+// Page implements SitemapController.
+func (controller *SitemapControllerImpl) Page(ctx *fiber.Ctx) error {
+	page, err := ctx.ParamsInt("page")
+	if err != nil {
+		return fiber.ErrNotFound
+	}
+
+	body, err := controller.SitemapUsecase.Page(ctx.UserContext(), page)
+	if err != nil {
+		log.Println("failed to build sitemap page")
+		return err
+	}
+
+	return sendSitemap(ctx, body)
+}
+
+// Robots implements SitemapController.
+func (controller *SitemapControllerImpl) Robots(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+
+	return ctx.Send(controller.SitemapUsecase.Robots(ctx.BaseURL()))
+}
+
+func sendSitemap(ctx *fiber.Ctx, body []byte) error {
+	ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+
+	return ctx.Send(body)
+}
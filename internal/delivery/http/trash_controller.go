@@ -0,0 +1,66 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TrashController interface {
+	FindAll(ctx *fiber.Ctx) error
+	Restore(ctx *fiber.Ctx) error
+	Purge(ctx *fiber.Ctx) error
+}
+
+type TrashControllerImpl struct {
+	TrashUsecase usecase.TrashUsecase
+}
+
+func NewTrashController(TrashUsecase usecase.TrashUsecase) TrashController {
+	return &TrashControllerImpl{
+		TrashUsecase: TrashUsecase,
+	}
+}
+
+// FindAll implements TrashController.
+func (controller *TrashControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	responses, err := controller.TrashUsecase.FindAll(ctx.UserContext(), ctx.Query("type"))
+	if err != nil {
+		log.Println("failed to find all trash")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.TrashItemResponse]{Data: responses})
+}
+
+// Restore implements TrashController.
+func (controller *TrashControllerImpl) Restore(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.TrashUsecase.Restore(ctx.UserContext(), ctx.Params("type"), uint(id), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to restore trash")
+		return err
+	}
+
+	return nil
+}
+
+// Purge implements TrashController.
+func (controller *TrashControllerImpl) Purge(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.TrashUsecase.Purge(ctx.UserContext(), ctx.Params("type"), uint(id), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to purge trash")
+		return err
+	}
+
+	return nil
+}
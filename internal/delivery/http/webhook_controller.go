@@ -0,0 +1,190 @@
+package http
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookController interface {
+	Create(ctx *fiber.Ctx) error
+	Update(ctx *fiber.Ctx) error
+	Delete(ctx *fiber.Ctx) error
+	FindById(ctx *fiber.Ctx) error
+	FindAll(ctx *fiber.Ctx) error
+	FindDeliveries(ctx *fiber.Ctx) error
+	FindDelivery(ctx *fiber.Ctx) error
+	Redeliver(ctx *fiber.Ctx) error
+}
+
+type WebhookControllerImpl struct {
+	WebhookUsecase usecase.WebhookUsecase
+}
+
+func NewWebhookController(WebhookUsecase usecase.WebhookUsecase) WebhookController {
+	return &WebhookControllerImpl{
+		WebhookUsecase: WebhookUsecase,
+	}
+}
+
+// Create implements WebhookController.
+func (controller *WebhookControllerImpl) Create(ctx *fiber.Ctx) error {
+	request := new(model.WebhookCreateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.WebhookUsecase.Create(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to create webhook")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.WebhookResponse]{Data: response})
+}
+
+// Update implements WebhookController.
+func (controller *WebhookControllerImpl) Update(ctx *fiber.Ctx) error {
+	request := new(model.WebhookUpdateRequest)
+
+	if err := ctx.BodyParser(request); err != nil {
+		log.Println("failed to parse request : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request.ID = uint(id)
+	request.ActorID = adminIdFromToken(ctx)
+
+	response, err := controller.WebhookUsecase.Update(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to update webhook")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.WebhookResponse]{Data: response})
+}
+
+// Delete implements WebhookController.
+func (controller *WebhookControllerImpl) Delete(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	if err := controller.WebhookUsecase.Delete(ctx.UserContext(), uint(id), adminIdFromToken(ctx)); err != nil {
+		log.Println("failed to delete webhook")
+		return err
+	}
+
+	return nil
+}
+
+// FindById implements WebhookController.
+func (controller *WebhookControllerImpl) FindById(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	response, err := controller.WebhookUsecase.FindById(ctx.UserContext(), uint(id), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to find by id webhook")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.WebhookResponse]{Data: response})
+}
+
+// FindAll implements WebhookController.
+func (controller *WebhookControllerImpl) FindAll(ctx *fiber.Ctx) error {
+	responses, err := controller.WebhookUsecase.FindAll(ctx.UserContext(), adminIdFromToken(ctx))
+	if err != nil {
+		log.Println("failed to find all webhook")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.WebhookResponse]{Data: responses})
+}
+
+// FindDeliveries implements WebhookController.
+func (controller *WebhookControllerImpl) FindDeliveries(ctx *fiber.Ctx) error {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return fiber.ErrBadRequest
+	}
+
+	request := &model.WebhookDeliveryFilterRequest{
+		WebhookID: uint(id),
+		Status:    ctx.Query("status"),
+		ActorID:   adminIdFromToken(ctx),
+	}
+
+	responses, err := controller.WebhookUsecase.FindDeliveries(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to find deliveries webhook")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponses[model.WebhookDeliveryResponse]{Data: responses})
+}
+
+// FindDelivery implements WebhookController.
+func (controller *WebhookControllerImpl) FindDelivery(ctx *fiber.Ctx) error {
+	request, err := deliveryRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := controller.WebhookUsecase.FindDelivery(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to find delivery webhook")
+		return err
+	}
+
+	return ctx.JSON(model.WebResponse[*model.WebhookDeliveryResponse]{Data: response})
+}
+
+// Redeliver implements WebhookController.
+func (controller *WebhookControllerImpl) Redeliver(ctx *fiber.Ctx) error {
+	request, err := deliveryRequest(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := controller.WebhookUsecase.Redeliver(ctx.UserContext(), request)
+	if err != nil {
+		log.Println("failed to redeliver webhook")
+		return err
+	}
+
+	return ctx.Status(fiber.StatusCreated).JSON(model.WebResponse[*model.WebhookDeliveryResponse]{Data: response})
+}
+
+func deliveryRequest(ctx *fiber.Ctx) (*model.WebhookDeliveryRequest, error) {
+	id, err := ctx.ParamsInt("id")
+	if err != nil {
+		return nil, fiber.ErrBadRequest
+	}
+
+	deliveryId, err := ctx.ParamsInt("delivery_id")
+	if err != nil {
+		return nil, fiber.ErrBadRequest
+	}
+
+	return &model.WebhookDeliveryRequest{
+		WebhookID:  uint(id),
+		DeliveryID: uint(deliveryId),
+		ActorID:    adminIdFromToken(ctx),
+	}, nil
+}
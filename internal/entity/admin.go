@@ -5,6 +5,8 @@ type Admin struct {
 	Name          string         `gorm:"not null"`
 	Username      string         `gorm:"not null;unique"`
 	Password      string         `gorm:"not null"`
+	Role          string         `gorm:"not null;default:editor"`
+	Version       uint           `gorm:"not null;default:1"`
 	Contents      []Content      `gorm:"foreignKey:created_by;references:id"`
 	Announcements []Announcement `gorm:"foreignKey:published_by;references:id"`
 }
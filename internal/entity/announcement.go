@@ -1,14 +1,33 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Announcement struct {
-	ID          uint   `gorm:"primaryKey"`
-	Title       string `gorm:"not null"`
-	Content     string `gorm:"not null"`
-	Image       string
-	PublishedBy uint `gorm:"not null"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Admin       Admin `gorm:"foreignKey:published_by;references:id"`
+	ID            uint   `gorm:"primaryKey"`
+	Title         string `gorm:"not null"`
+	Content       string `gorm:"not null"`
+	ContentFormat string `gorm:"not null;default:html"`
+	Image         string
+	CategoryID    *uint
+	Priority      string `gorm:"not null;default:info"`
+	ValidFrom     *time.Time
+	ValidUntil    *time.Time
+	Pinned        bool `gorm:"not null;default:false"`
+	PinnedUntil   *time.Time
+	PublishedAt   *time.Time
+	PublishedBy   uint   `gorm:"not null"`
+	ViewCount     uint64 `gorm:"not null;default:0"`
+	Version       uint   `gorm:"not null;default:1"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt            `gorm:"index"`
+	Admin         Admin                     `gorm:"foreignKey:published_by;references:id"`
+	Category      *AnnouncementCategory     `gorm:"foreignKey:category_id;references:id"`
+	Translations  []AnnouncementTranslation `gorm:"foreignKey:announcement_id;references:id"`
+	Attachments   []AnnouncementAttachment  `gorm:"foreignKey:announcement_id;references:id"`
+	Locale        string                    `gorm:"-"`
 }
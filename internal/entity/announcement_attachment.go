@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// AnnouncementAttachment is a file that comes with an announcement, kept
+// under StoredName and handed out under the uploaded Filename.
+type AnnouncementAttachment struct {
+	ID             uint   `gorm:"primaryKey"`
+	AnnouncementID uint   `gorm:"not null"`
+	Name           string `gorm:"not null"`
+	Filename       string `gorm:"not null"`
+	StoredName     string `gorm:"not null"`
+	MimeType       string `gorm:"not null"`
+	Size           int64  `gorm:"not null"`
+	DownloadCount  uint64 `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
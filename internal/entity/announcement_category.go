@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type AnnouncementCategory struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"not null"`
+	Slug        string `gorm:"not null"`
+	Description string `gorm:"not null"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// ChangeRequest holds a create or update of a content, an announcement or a
+// translation of one that waits for a reviewer. Payload is the JSON of the
+// create or update request.
+type ChangeRequest struct {
+	ID            uint   `gorm:"primaryKey"`
+	ItemType      string `gorm:"not null"`
+	ItemID        *uint
+	Action        string `gorm:"not null"`
+	BaseVersion   uint   `gorm:"not null;default:0"`
+	Title         string `gorm:"not null"`
+	Payload       string `gorm:"type:json;not null"`
+	Status        string `gorm:"not null;default:pending"`
+	RequestedBy   uint   `gorm:"not null"`
+	ReviewedBy    *uint
+	ReviewedAt    *time.Time
+	ReviewComment string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Requester     Admin `gorm:"foreignKey:requested_by;references:id"`
+	Reviewer      Admin `gorm:"foreignKey:reviewed_by;references:id"`
+}
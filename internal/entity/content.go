@@ -1,17 +1,41 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Content struct {
-	ID          uint   `gorm:"primaryKey"`
-	Title       string `gorm:"not null"`
-	Content     string `gorm:"not null"`
-	Image       string
-	Address     string
-	ContactInfo string
-	Category    string `gorm:"not null"`
-	CreatedBy   uint   `gorm:"not null"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Admin       Admin `gorm:"foreignKey:created_by;references:id"`
+	ID            uint   `gorm:"primaryKey"`
+	Title         string `gorm:"not null"`
+	Content       string `gorm:"not null"`
+	ContentFormat string `gorm:"not null;default:html"`
+	Image         string
+	Address       string
+	ContactInfo   string
+	Latitude      *float64
+	Longitude     *float64
+	Category      string `gorm:"not null"`
+	IsFree        bool   `gorm:"not null"`
+	Currency      string `gorm:"not null;default:IDR"`
+	PriceLevel    *uint8
+	MinPrice      *int64
+	MaxPrice      *int64
+	RatingAverage float64 `gorm:"not null;default:0"`
+	ReviewCount   uint    `gorm:"not null;default:0"`
+	IsFeatured    bool    `gorm:"not null;default:false"`
+	Position      int     `gorm:"not null;default:0"`
+	ViewCount     uint64  `gorm:"not null;default:0"`
+	Version       uint    `gorm:"not null;default:1"`
+	CreatedBy     uint    `gorm:"not null"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     gorm.DeletedAt       `gorm:"index"`
+	Admin         Admin                `gorm:"foreignKey:created_by;references:id"`
+	Prices        []ContentPrice       `gorm:"foreignKey:content_id;references:id"`
+	Translations  []ContentTranslation `gorm:"foreignKey:content_id;references:id"`
+	Tags          []ContentTag         `gorm:"foreignKey:content_id;references:id"`
+	Contacts      []ContentContact     `gorm:"foreignKey:content_id;references:id"`
+	Locale        string               `gorm:"-"`
 }
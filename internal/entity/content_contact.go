@@ -0,0 +1,10 @@
+package entity
+
+type ContentContact struct {
+	ID        uint   `gorm:"primaryKey"`
+	ContentID uint   `gorm:"not null"`
+	Type      string `gorm:"not null"`
+	Value     string `gorm:"not null"`
+	Label     string
+	Position  int `gorm:"not null;default:0"`
+}
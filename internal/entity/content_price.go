@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type ContentPrice struct {
+	ID        uint   `gorm:"primaryKey"`
+	ContentID uint   `gorm:"not null"`
+	Tier      string `gorm:"not null"`
+	Label     string
+	Amount    int64 `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
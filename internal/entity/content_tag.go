@@ -0,0 +1,7 @@
+package entity
+
+type ContentTag struct {
+	ID        uint   `gorm:"primaryKey"`
+	ContentID uint   `gorm:"not null"`
+	Tag       string `gorm:"not null"`
+}
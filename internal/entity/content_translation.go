@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type ContentTranslation struct {
+	ID            uint   `gorm:"primaryKey"`
+	ContentID     uint   `gorm:"not null"`
+	Locale        string `gorm:"not null"`
+	Title         string `gorm:"not null"`
+	Content       string `gorm:"not null"`
+	ContentFormat string `gorm:"not null;default:html"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,10 @@
+package entity
+
+import "time"
+
+type DailyView struct {
+	ItemType string    `gorm:"primaryKey"`
+	ItemID   uint      `gorm:"primaryKey"`
+	ViewDate time.Time `gorm:"primaryKey;type:date"`
+	Views    uint64    `gorm:"not null"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+// Event is a date on the village calendar. A recurring event repeats by
+// RRule from StartsAt; SeriesEndsAt is no earlier than the end of its last
+// occurrence and stays empty for series that never end. The dates of an all-day event are
+// whole days, EndsAt being the last one.
+type Event struct {
+	ID           uint   `gorm:"primaryKey"`
+	Title        string `gorm:"not null"`
+	Description  string `gorm:"not null"`
+	Location     string `gorm:"not null"`
+	ContentID    *uint
+	StartsAt     time.Time `gorm:"not null"`
+	EndsAt       time.Time `gorm:"not null"`
+	AllDay       bool      `gorm:"not null;default:false"`
+	RRule        string    `gorm:"column:rrule;not null"`
+	SeriesEndsAt *time.Time
+	CreatedBy    uint `gorm:"not null"`
+	Version      uint `gorm:"not null;default:1"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Admin        Admin    `gorm:"foreignKey:created_by;references:id"`
+	Content      *Content `gorm:"foreignKey:content_id;references:id"`
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// ItemEditor delegates editing of one content or announcement to an admin
+// who does not own it.
+type ItemEditor struct {
+	ID         uint   `gorm:"primaryKey"`
+	ItemType   string `gorm:"not null"`
+	ItemID     uint   `gorm:"not null"`
+	AdminID    uint   `gorm:"not null"`
+	AssignedBy *uint
+	CreatedAt  time.Time
+	Admin      Admin `gorm:"foreignKey:admin_id;references:id"`
+}
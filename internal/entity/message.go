@@ -0,0 +1,48 @@
+package entity
+
+import "time"
+
+// Message is sent by a visitor through the contact form. Messages caught by
+// the spam checks are kept, flagged, out of the inbox.
+type Message struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"not null"`
+	Email      string `gorm:"not null"`
+	Phone      string `gorm:"not null"`
+	Subject    string `gorm:"not null"`
+	Body       string `gorm:"not null"`
+	IPAddress  string `gorm:"column:ip_address;not null"`
+	UserAgent  string `gorm:"not null"`
+	IsSpam     bool   `gorm:"not null;default:false"`
+	ReadAt     *time.Time
+	ArchivedAt *time.Time
+	AssignedTo *uint
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Assignee   *Admin         `gorm:"foreignKey:assigned_to;references:id"`
+	Notes      []MessageNote  `gorm:"foreignKey:message_id;references:id"`
+	Replies    []MessageReply `gorm:"foreignKey:message_id;references:id"`
+}
+
+// MessageNote is an internal remark of an admin on a message, optionally
+// answering another note.
+type MessageNote struct {
+	ID        uint `gorm:"primaryKey"`
+	MessageID uint `gorm:"not null"`
+	ParentID  *uint
+	AdminID   uint   `gorm:"not null"`
+	Body      string `gorm:"not null"`
+	CreatedAt time.Time
+	Admin     Admin `gorm:"foreignKey:admin_id;references:id"`
+}
+
+// MessageReply is an answer mailed to the sender of a message.
+type MessageReply struct {
+	ID        uint   `gorm:"primaryKey"`
+	MessageID uint   `gorm:"not null"`
+	AdminID   uint   `gorm:"not null"`
+	Subject   string `gorm:"not null"`
+	Body      string `gorm:"not null"`
+	SentAt    time.Time
+	Admin     Admin `gorm:"foreignKey:admin_id;references:id"`
+}
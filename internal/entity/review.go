@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+type Review struct {
+	ID              uint   `gorm:"primaryKey"`
+	ContentID       uint   `gorm:"not null"`
+	Rating          uint8  `gorm:"not null"`
+	Body            string `gorm:"not null"`
+	ReviewerName    string `gorm:"not null"`
+	ReviewerContact string
+	Status          string `gorm:"not null;default:pending"`
+	ModeratedBy     *uint
+	ModeratedAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Content         Content `gorm:"foreignKey:content_id;references:id"`
+}
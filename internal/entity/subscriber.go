@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// Subscriber gets the newsletter once ConfirmedAt is set. Topics is the
+// comma separated list stored in a MySQL SET. Token is handed out in every
+// mail to manage or end the subscription; ConfirmToken only holds the hash
+// of the opt-in token until it is used.
+type Subscriber struct {
+	ID               uint   `gorm:"primaryKey"`
+	Email            string `gorm:"not null"`
+	Name             string `gorm:"not null"`
+	Topics           string `gorm:"not null"`
+	Token            string `gorm:"not null"`
+	ConfirmToken     *string
+	ConfirmExpiresAt *time.Time
+	ConfirmedAt      *time.Time
+	LastSentAt       *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
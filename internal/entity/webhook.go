@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+// Webhook posts the events it subscribes to, a comma separated list stored
+// in a MySQL SET, to URL. FailureCount counts the deliveries that failed in
+// a row; too many of them disable the webhook.
+type Webhook struct {
+	ID           uint   `gorm:"primaryKey"`
+	URL          string `gorm:"column:url;not null"`
+	Secret       string `gorm:"not null"`
+	Events       string `gorm:"not null"`
+	Description  string `gorm:"not null"`
+	Active       bool   `gorm:"not null"`
+	FailureCount uint   `gorm:"not null;default:0"`
+	DisabledAt   *time.Time
+	CreatedBy    uint `gorm:"not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Admin        Admin `gorm:"foreignKey:created_by;references:id"`
+}
+
+// WebhookDelivery is one event on its way to a webhook. A pending delivery
+// is attempted again at NextAttemptAt; it has none while an attempt is
+// running or once it succeeded or failed for good.
+type WebhookDelivery struct {
+	ID            uint   `gorm:"primaryKey"`
+	WebhookID     uint   `gorm:"not null"`
+	Event         string `gorm:"not null"`
+	Payload       string `gorm:"not null"`
+	Status        string `gorm:"not null"`
+	Attempts      uint   `gorm:"not null;default:0"`
+	ResponseCode  *int
+	ResponseBody  string `gorm:"not null"`
+	Error         string `gorm:"not null"`
+	NextAttemptAt *time.Time
+	DeliveredAt   *time.Time
+	RedeliveryOf  *uint
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Webhook       Webhook `gorm:"foreignKey:webhook_id;references:id"`
+}
@@ -6,16 +6,21 @@ type AdminResponse struct {
 	ID       uint   `json:"id"`
 	Username string `json:"username"`
 	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Version  uint   `json:"version"`
 }
 
 type AdminCreateRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
+	Role     string `json:"role" validate:"omitempty,oneof=editor publisher admin"`
+	ActorID  uint   `json:"-"`
 }
 
 type AdminUpdateRequest struct {
-	ID uint `json:"id" validate:"required"`
+	ID      uint `json:"id" validate:"required"`
+	Version uint `json:"-"`
 	AdminCreateRequest
 }
 
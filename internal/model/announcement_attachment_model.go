@@ -0,0 +1,40 @@
+package model
+
+import "io"
+
+type AnnouncementAttachmentResponse struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Filename      string `json:"filename"`
+	MimeType      string `json:"mime_type"`
+	Size          int64  `json:"size"`
+	DownloadCount uint64 `json:"download_count"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// AnnouncementAttachmentCreateRequest carries an uploaded file. Name is the
+// display name and defaults to the filename without its extension.
+type AnnouncementAttachmentCreateRequest struct {
+	AnnouncementID uint        `json:"-" validate:"required"`
+	Name           string      `json:"name" validate:"max=150"`
+	Filename       string      `json:"-" validate:"required,max=255"`
+	Size           int64       `json:"-"`
+	File           io.ReaderAt `json:"-"`
+	ActorID        uint        `json:"-"`
+}
+
+type AnnouncementAttachmentUpdateRequest struct {
+	ID             uint   `json:"-" validate:"required"`
+	AnnouncementID uint   `json:"-" validate:"required"`
+	Name           string `json:"name" validate:"required,max=150"`
+	ActorID        uint   `json:"-"`
+}
+
+// AnnouncementAttachmentFile tells where a download is read from and how it
+// is handed out.
+type AnnouncementAttachmentFile struct {
+	Path     string
+	Filename string
+	MimeType string
+	Size     int64
+}
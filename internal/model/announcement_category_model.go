@@ -0,0 +1,18 @@
+package model
+
+type AnnouncementCategoryResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description,omitempty"`
+}
+
+// AnnouncementCategoryRequest creates or renames a category. Slug is what
+// GET /announcements?category= filters on and is made from Name when empty.
+type AnnouncementCategoryRequest struct {
+	ID          uint   `json:"-"`
+	Name        string `json:"name" validate:"required,max=100"`
+	Slug        string `json:"slug" validate:"omitempty,max=100"`
+	Description string `json:"description" validate:"max=255"`
+	ActorID     uint   `json:"-"`
+}
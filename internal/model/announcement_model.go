@@ -1,22 +1,59 @@
 package model
 
 type AnnouncementResponse struct {
-	ID          uint   `json:"id"`
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	Image       string `json:"image"`
-	PublishedBy string `json:"published_by"`
-	CreatedAt   string `json:"created_at"`
+	ID            uint              `json:"id"`
+	Title         string            `json:"title"`
+	Content       string            `json:"content"`
+	ContentFormat string            `json:"content_format"`
+	ContentHTML   string            `json:"content_html"`
+	Excerpt       string            `json:"excerpt"`
+	Locale        string            `json:"locale"`
+	Image         string            `json:"image"`
+	Srcset        map[string]string `json:"srcset"`
+	Priority      string            `json:"priority"`
+	ValidFrom     string            `json:"valid_from,omitempty"`
+	ValidUntil    string            `json:"valid_until,omitempty"`
+	Pinned        bool              `json:"pinned"`
+	PinnedUntil   string            `json:"pinned_until,omitempty"`
+	PublishedBy   string            `json:"published_by"`
+	ViewCount     uint64            `json:"view_count"`
+	Version       uint              `json:"version"`
+	CreatedAt     string            `json:"created_at"`
+
+	Category    *AnnouncementCategoryResponse    `json:"category"`
+	Attachments []AnnouncementAttachmentResponse `json:"attachments"`
 }
 
+// AnnouncementCreateRequest takes ValidFrom, ValidUntil and PinnedUntil as
+// RFC 3339 times or as YYYY-MM-DD dates, where a date for an end includes
+// the whole day. Either may be left empty for an open end.
 type AnnouncementCreateRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Content     string `json:"content" validate:"required"`
-	Image       string `json:"image" validate:"required"`
-	PublishedBy uint   `json:"published_by" validate:"required"`
+	Title         string `json:"title" validate:"required"`
+	Content       string `json:"content" validate:"required"`
+	ContentFormat string `json:"content_format" validate:"omitempty,oneof=markdown html"`
+	Image         string `json:"image" validate:"required"`
+	Priority      string `json:"priority" validate:"omitempty,oneof=info important urgent"`
+	ValidFrom     string `json:"valid_from"`
+	ValidUntil    string `json:"valid_until"`
+	CategoryID    *uint  `json:"category_id" validate:"omitempty,min=1"`
+	Pinned        bool   `json:"pinned"`
+	PinnedUntil   string `json:"pinned_until"`
+	PublishedBy   uint   `json:"published_by" validate:"required"`
+	ActorID       uint   `json:"-"`
 }
 
 type AnnouncementUpdateRequest struct {
-	ID uint `json:"id" validate:"required"`
+	ID      uint `json:"id" validate:"required"`
+	Version uint `json:"-"`
 	AnnouncementCreateRequest
 }
+
+// AnnouncementFilterRequest lists the announcements valid right now, the
+// pinned ones first, then the most urgent and then by date in Order.
+// Category is the slug of a category.
+type AnnouncementFilterRequest struct {
+	Order    string `json:"order"`
+	Priority string `json:"priority"`
+	Category string `json:"category"`
+	Locale   string `json:"lang"`
+}
@@ -0,0 +1,32 @@
+package model
+
+type ChangeRequestResponse struct {
+	ID            uint                 `json:"id"`
+	ItemType      string               `json:"item_type"`
+	ItemID        *uint                `json:"item_id"`
+	Action        string               `json:"action"`
+	Title         string               `json:"title"`
+	Status        string               `json:"status"`
+	RequestedBy   string               `json:"requested_by"`
+	ReviewedBy    string               `json:"reviewed_by,omitempty"`
+	ReviewComment string               `json:"review_comment,omitempty"`
+	ReviewedAt    string               `json:"reviewed_at,omitempty"`
+	CreatedAt     string               `json:"created_at"`
+	Stale         bool                 `json:"stale,omitempty"`
+	Changes       []ChangeDiffResponse `json:"changes,omitempty"`
+}
+
+// ChangeDiffResponse is one field that differs between the live item and the
+// proposed change. Live is null for items that do not exist yet.
+type ChangeDiffResponse struct {
+	Field    string `json:"field"`
+	Live     any    `json:"live"`
+	Proposed any    `json:"proposed"`
+}
+
+type ChangeReviewRequest struct {
+	ID         uint   `json:"-" validate:"required"`
+	Status     string `json:"-" validate:"required,oneof=approved rejected"`
+	Comment    string `json:"comment" validate:"required_if=Status rejected,max=2000"`
+	ReviewedBy uint   `json:"-" validate:"required"`
+}
@@ -1,34 +1,123 @@
 package model
 
 type ContentResponse struct {
-	ID          uint   `json:"id"`
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	Image       string `json:"image"`
-	Address     string `json:"address"`
-	ContactInfo string `json:"contact_info"`
-	Category    string `json:"category"`
-	CreatedBy   string `json:"created_by"`
-	CreatedAt   string `json:"created_at"`
+	ID            uint                     `json:"id"`
+	Title         string                   `json:"title"`
+	Content       string                   `json:"content"`
+	ContentFormat string                   `json:"content_format"`
+	ContentHTML   string                   `json:"content_html"`
+	Excerpt       string                   `json:"excerpt"`
+	Locale        string                   `json:"locale"`
+	Image         string                   `json:"image"`
+	Srcset        map[string]string        `json:"srcset"`
+	Address       string                   `json:"address"`
+	ContactInfo   string                   `json:"contact_info"`
+	Contacts      []ContentContactResponse `json:"contacts"`
+	Category      string                   `json:"category"`
+	Tags          []string                 `json:"tags"`
+	Latitude      *float64                 `json:"latitude"`
+	Longitude     *float64                 `json:"longitude"`
+	Pricing       ContentPricingResponse   `json:"pricing"`
+	RatingAverage float64                  `json:"rating_average"`
+	ReviewCount   uint                     `json:"review_count"`
+	IsFeatured    bool                     `json:"is_featured"`
+	Position      int                      `json:"position"`
+	ViewCount     uint64                   `json:"view_count"`
+	Version       uint                     `json:"version"`
+	CreatedBy     string                   `json:"created_by"`
+	CreatedAt     string                   `json:"created_at"`
+}
+
+type ContentPricingResponse struct {
+	IsFree     bool                   `json:"is_free"`
+	Currency   string                 `json:"currency"`
+	PriceLevel *uint8                 `json:"price_level"`
+	MinPrice   *int64                 `json:"min_price"`
+	MaxPrice   *int64                 `json:"max_price"`
+	Prices     []ContentPriceResponse `json:"prices"`
+}
+
+type ContentPriceResponse struct {
+	Tier   string `json:"tier"`
+	Label  string `json:"label"`
+	Amount int64  `json:"amount"`
+}
+
+type ContentPriceRequest struct {
+	Tier   string `json:"tier" validate:"required,oneof=general adult child student foreign_tourist"`
+	Label  string `json:"label" validate:"max=100"`
+	Amount int64  `json:"amount" validate:"gte=0"`
+}
+
+type ContentContactResponse struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Link  string `json:"link"`
+}
+
+type ContentContactRequest struct {
+	Type  string `json:"type" validate:"required,oneof=phone whatsapp email instagram facebook website"`
+	Value string `json:"value" validate:"required,max=255"`
+	Label string `json:"label" validate:"max=100"`
 }
 
 type ContentCreateRequest struct {
-	Title       string `json:"title" validate:"required"`
-	Content     string `json:"content" validate:"required"`
-	Image       string `json:"image" validate:"required"`
-	Address     string `json:"address" validate:"required"`
-	ContactInfo string `json:"contact_info" validate:"required,e164"`
-	Category    string `json:"category" validate:"required,oneof=kuliner wisata kerajinan"`
-	CreatedBy   uint   `json:"created_by" validate:"required"`
+	Title         string                  `json:"title" validate:"required"`
+	Content       string                  `json:"content" validate:"required"`
+	ContentFormat string                  `json:"content_format" validate:"omitempty,oneof=markdown html"`
+	Image         string                  `json:"image" validate:"required"`
+	Address       string                  `json:"address" validate:"required"`
+	ContactInfo   string                  `json:"contact_info" validate:"required_without=Contacts,omitempty,e164"`
+	Contacts      []ContentContactRequest `json:"contacts" validate:"max=20,dive"`
+	Category      string                  `json:"category" validate:"required,oneof=kuliner wisata kerajinan"`
+	Tags          []string                `json:"tags" validate:"max=20,dive,required,max=50"`
+	Latitude      *float64                `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude     *float64                `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	IsFree        bool                    `json:"is_free"`
+	Currency      string                  `json:"currency" validate:"omitempty,iso4217"`
+	PriceLevel    uint8                   `json:"price_level" validate:"omitempty,min=1,max=4,excluded_unless=Category kuliner"`
+	IsFeatured    bool                    `json:"is_featured"`
+	Position      int                     `json:"position" validate:"gte=0"`
+	Prices        []ContentPriceRequest   `json:"prices" validate:"dive"`
+	CreatedBy     uint                    `json:"created_by" validate:"required"`
+	ActorID       uint                    `json:"-"`
 }
 
 type ContentUpdateRequest struct {
-	ID          uint   `json:"id" validate:"required"`
-	Title       string `json:"title" validate:"required"`
-	Content     string `json:"content" validate:"required"`
-	Image       string `json:"image"`
-	Address     string `json:"address" validate:"required"`
-	ContactInfo string `json:"contact_info" validate:"required,e164"`
-	Category    string `json:"category" validate:"required,oneof=kuliner wisata kerajinan"`
-	CreatedBy   uint   `json:"created_by" validate:"required"`
+	ID            uint                    `json:"id" validate:"required"`
+	Version       uint                    `json:"-"`
+	Title         string                  `json:"title" validate:"required"`
+	Content       string                  `json:"content" validate:"required"`
+	ContentFormat string                  `json:"content_format" validate:"omitempty,oneof=markdown html"`
+	Image         string                  `json:"image"`
+	Address       string                  `json:"address" validate:"required"`
+	ContactInfo   string                  `json:"contact_info" validate:"required_without=Contacts,omitempty,e164"`
+	Contacts      []ContentContactRequest `json:"contacts" validate:"max=20,dive"`
+	Category      string                  `json:"category" validate:"required,oneof=kuliner wisata kerajinan"`
+	Tags          []string                `json:"tags" validate:"max=20,dive,required,max=50"`
+	Latitude      *float64                `json:"latitude" validate:"required_with=Longitude,omitempty,latitude"`
+	Longitude     *float64                `json:"longitude" validate:"required_with=Latitude,omitempty,longitude"`
+	IsFree        bool                    `json:"is_free"`
+	Currency      string                  `json:"currency" validate:"omitempty,iso4217"`
+	PriceLevel    uint8                   `json:"price_level" validate:"omitempty,min=1,max=4,excluded_unless=Category kuliner"`
+	IsFeatured    *bool                   `json:"is_featured"`
+	Position      *int                    `json:"position" validate:"omitempty,gte=0"`
+	Prices        []ContentPriceRequest   `json:"prices" validate:"dive"`
+	CreatedBy     uint                    `json:"created_by" validate:"required"`
+	ActorID       uint                    `json:"-"`
+}
+
+type ContentFilterRequest struct {
+	Order    string `json:"order"`
+	Category string `json:"category"`
+	Sort     string `json:"sort"`
+	MinPrice *int64 `json:"min_price"`
+	MaxPrice *int64 `json:"max_price"`
+	Locale   string `json:"lang"`
+}
+
+type ContentReorderRequest struct {
+	IDs     []uint `json:"ids" validate:"required,min=1,unique,dive,required"`
+	ActorID uint   `json:"-"`
 }
@@ -0,0 +1,25 @@
+package model
+
+type ContentImportRequest struct {
+	Rows         [][]string        `json:"-"`
+	Mapping      map[string]string `json:"mapping"`
+	Mode         string            `json:"mode" validate:"required,oneof=atomic skip_invalid"`
+	DryRun       bool              `json:"dry_run"`
+	DefaultImage string            `json:"-"`
+	CreatedBy    uint              `json:"-" validate:"required"`
+}
+
+type ContentImportResponse struct {
+	Mode      string                   `json:"mode"`
+	DryRun    bool                     `json:"dry_run"`
+	TotalRows int                      `json:"total_rows"`
+	ValidRows int                      `json:"valid_rows"`
+	Imported  int                      `json:"imported"`
+	Skipped   int                      `json:"skipped"`
+	Errors    []ContentImportRowResult `json:"errors"`
+}
+
+type ContentImportRowResult struct {
+	Row     int      `json:"row"`
+	Details []string `json:"details"`
+}
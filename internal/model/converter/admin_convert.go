@@ -14,6 +14,8 @@ func AdminToResponse(admin *entity.Admin) *model.AdminResponse {
 		ID:       admin.ID,
 		Username: admin.Username,
 		Name:     admin.Name,
+		Role:     admin.Role,
+		Version:  admin.Version,
 	}
 }
 
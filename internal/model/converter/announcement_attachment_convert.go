@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func AnnouncementAttachmentToResponse(attachment *entity.AnnouncementAttachment) *model.AnnouncementAttachmentResponse {
+	return &model.AnnouncementAttachmentResponse{
+		ID:            attachment.ID,
+		Name:          attachment.Name,
+		Filename:      attachment.Filename,
+		MimeType:      attachment.MimeType,
+		Size:          attachment.Size,
+		DownloadCount: attachment.DownloadCount,
+		CreatedAt:     attachment.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func AnnouncementAttachmentsToResponses(attachments []entity.AnnouncementAttachment) []model.AnnouncementAttachmentResponse {
+	responses := []model.AnnouncementAttachmentResponse{}
+
+	for i := range attachments {
+		responses = append(responses, *AnnouncementAttachmentToResponse(&attachments[i]))
+	}
+
+	return responses
+}
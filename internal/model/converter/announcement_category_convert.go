@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func AnnouncementCategoryToResponse(category *entity.AnnouncementCategory) *model.AnnouncementCategoryResponse {
+	return &model.AnnouncementCategoryResponse{
+		ID:          category.ID,
+		Name:        category.Name,
+		Slug:        category.Slug,
+		Description: category.Description,
+	}
+}
+
+func AnnouncementCategoriesToResponses(categories *[]entity.AnnouncementCategory) *[]model.AnnouncementCategoryResponse {
+	responses := []model.AnnouncementCategoryResponse{}
+
+	for i := range *categories {
+		responses = append(responses, *AnnouncementCategoryToResponse(&(*categories)[i]))
+	}
+
+	return &responses
+}
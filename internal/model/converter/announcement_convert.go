@@ -2,22 +2,55 @@ package converter
 
 import (
 	"log"
+	"time"
 
 	"github.com/Bangdams/web-profile-API/internal/entity"
 	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/util"
 )
 
 func AnnouncementToResponse(announcement *entity.Announcement) *model.AnnouncementResponse {
 	log.Println("log from announcement to response")
 
-	return &model.AnnouncementResponse{
-		ID:          announcement.ID,
-		Title:       announcement.Title,
-		Content:     announcement.Content,
-		Image:       announcement.Image,
-		PublishedBy: announcement.Admin.Name,
-		CreatedAt:   announcement.CreatedAt.Format("2006-01-02"),
+	contentHTML := util.RenderContentHTML(announcement.ContentFormat, announcement.Content)
+
+	response := &model.AnnouncementResponse{
+		ID:            announcement.ID,
+		Title:         announcement.Title,
+		Content:       announcement.Content,
+		ContentFormat: announcement.ContentFormat,
+		ContentHTML:   contentHTML,
+		Excerpt:       util.GenerateExcerpt(contentHTML),
+		Locale:        localeOrDefault(announcement.Locale),
+		Image:         announcement.Image,
+		Srcset:        util.ImageSrcset(announcement.Image),
+		Priority:      announcement.Priority,
+		Pinned:        announcement.Pinned,
+		PublishedBy:   announcement.Admin.Name,
+		ViewCount:     announcement.ViewCount,
+		Version:       announcement.Version,
+		CreatedAt:     announcement.CreatedAt.Format("2006-01-02"),
+	}
+
+	response.Attachments = AnnouncementAttachmentsToResponses(announcement.Attachments)
+
+	if announcement.Category != nil {
+		response.Category = AnnouncementCategoryToResponse(announcement.Category)
+	}
+
+	if announcement.PinnedUntil != nil {
+		response.PinnedUntil = announcement.PinnedUntil.Format(time.RFC3339)
 	}
+
+	if announcement.ValidFrom != nil {
+		response.ValidFrom = announcement.ValidFrom.Format(time.RFC3339)
+	}
+
+	if announcement.ValidUntil != nil {
+		response.ValidUntil = announcement.ValidUntil.Format(time.RFC3339)
+	}
+
+	return response
 }
 
 func AnnouncementToResponses(announcements *[]entity.Announcement) *[]model.AnnouncementResponse {
@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func ChangeRequestToResponse(change *entity.ChangeRequest) *model.ChangeRequestResponse {
+	log.Println("log from change request to response")
+
+	response := &model.ChangeRequestResponse{
+		ID:            change.ID,
+		ItemType:      change.ItemType,
+		ItemID:        change.ItemID,
+		Action:        change.Action,
+		Title:         change.Title,
+		Status:        change.Status,
+		RequestedBy:   change.Requester.Name,
+		ReviewedBy:    change.Reviewer.Name,
+		ReviewComment: change.ReviewComment,
+		CreatedAt:     change.CreatedAt.Format(time.RFC3339),
+	}
+
+	if change.ReviewedAt != nil {
+		response.ReviewedAt = change.ReviewedAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+func ChangeRequestToResponses(changes *[]entity.ChangeRequest) *[]model.ChangeRequestResponse {
+	changeResponses := []model.ChangeRequestResponse{}
+
+	log.Println("log from change request to responses")
+
+	for _, change := range *changes {
+		changeResponses = append(changeResponses, *ChangeRequestToResponse(&change))
+	}
+
+	return &changeResponses
+}
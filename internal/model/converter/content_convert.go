@@ -2,24 +2,44 @@ package converter
 
 import (
 	"log"
+	"slices"
 
 	"github.com/Bangdams/web-profile-API/internal/entity"
 	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/util"
 )
 
 func ContentToResponse(content *entity.Content) *model.ContentResponse {
 	log.Println("log from content to response")
 
+	contentHTML := util.RenderContentHTML(content.ContentFormat, content.Content)
+
 	return &model.ContentResponse{
-		ID:          content.ID,
-		Title:       content.Title,
-		Content:     content.Content,
-		Image:       content.Image,
-		Address:     content.Address,
-		ContactInfo: content.ContactInfo,
-		Category:    content.Category,
-		CreatedBy:   content.Admin.Name,
-		CreatedAt:   content.CreatedAt.Format("2006-01-02"),
+		ID:            content.ID,
+		Title:         content.Title,
+		Content:       content.Content,
+		ContentFormat: content.ContentFormat,
+		ContentHTML:   contentHTML,
+		Excerpt:       util.GenerateExcerpt(contentHTML),
+		Locale:        localeOrDefault(content.Locale),
+		Image:         content.Image,
+		Srcset:        util.ImageSrcset(content.Image),
+		Address:       content.Address,
+		ContactInfo:   content.ContactInfo,
+		Contacts:      ContentToContactResponses(content),
+		Category:      content.Category,
+		Tags:          ContentToTags(content),
+		Latitude:      content.Latitude,
+		Longitude:     content.Longitude,
+		Pricing:       *ContentToPricingResponse(content),
+		RatingAverage: content.RatingAverage,
+		ReviewCount:   content.ReviewCount,
+		IsFeatured:    content.IsFeatured,
+		Position:      content.Position,
+		ViewCount:     content.ViewCount,
+		Version:       content.Version,
+		CreatedBy:     content.Admin.Name,
+		CreatedAt:     content.CreatedAt.Format("2006-01-02"),
 	}
 }
 
@@ -34,3 +54,79 @@ func ContentToResponses(contents *[]entity.Content) *[]model.ContentResponse {
 
 	return &contentResponses
 }
+
+func ContentToPricingResponse(content *entity.Content) *model.ContentPricingResponse {
+	prices := []model.ContentPriceResponse{}
+
+	for _, price := range content.Prices {
+		prices = append(prices, model.ContentPriceResponse{
+			Tier:   price.Tier,
+			Label:  price.Label,
+			Amount: price.Amount,
+		})
+	}
+
+	return &model.ContentPricingResponse{
+		IsFree:     content.IsFree,
+		Currency:   content.Currency,
+		PriceLevel: content.PriceLevel,
+		MinPrice:   content.MinPrice,
+		MaxPrice:   content.MaxPrice,
+		Prices:     prices,
+	}
+}
+
+func ContentToTags(content *entity.Content) []string {
+	tags := []string{}
+
+	for _, tag := range content.Tags {
+		tags = append(tags, tag.Tag)
+	}
+
+	return tags
+}
+
+func ContentToContactResponses(content *entity.Content) []model.ContentContactResponse {
+	contacts := []model.ContentContactResponse{}
+
+	for _, contact := range content.Contacts {
+		contacts = append(contacts, model.ContentContactResponse{
+			Type:  contact.Type,
+			Value: contact.Value,
+			Label: contact.Label,
+			Link:  util.ContactLink(contact.Type, contact.Value),
+		})
+	}
+
+	return contacts
+}
+
+// ContentToVCard builds the business card offered for download on a listing.
+func ContentToVCard(content *entity.Content) *util.VCard {
+	card := &util.VCard{
+		Name:      content.Title,
+		Address:   content.Address,
+		Latitude:  content.Latitude,
+		Longitude: content.Longitude,
+	}
+
+	for _, contact := range content.Contacts {
+		switch contact.Type {
+		case util.ContactPhone, util.ContactWhatsApp:
+			// a number is often listed both as phone and as WhatsApp
+			if !slices.Contains(card.Phones, contact.Value) {
+				card.Phones = append(card.Phones, contact.Value)
+			}
+		case util.ContactEmail:
+			card.Emails = append(card.Emails, contact.Value)
+		default:
+			card.URLs = append(card.URLs, util.ContactLink(contact.Type, contact.Value))
+		}
+	}
+
+	if len(card.Phones) == 0 && content.ContactInfo != "" {
+		card.Phones = append(card.Phones, content.ContactInfo)
+	}
+
+	return card
+}
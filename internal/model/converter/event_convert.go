@@ -0,0 +1,57 @@
+package converter
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func EventToResponse(event *entity.Event) *model.EventResponse {
+	log.Println("log from event to response")
+
+	response := &model.EventResponse{
+		ID:          event.ID,
+		Title:       event.Title,
+		Description: event.Description,
+		Location:    event.Location,
+		ContentID:   event.ContentID,
+		StartsAt:    formatEventTime(event.StartsAt, event.AllDay),
+		EndsAt:      formatEventTime(event.EndsAt, event.AllDay),
+		AllDay:      event.AllDay,
+		RRule:       event.RRule,
+		CreatedBy:   event.Admin.Name,
+		Version:     event.Version,
+		CreatedAt:   event.CreatedAt.Format("2006-01-02"),
+	}
+
+	if event.Content != nil {
+		response.ContentTitle = event.Content.Title
+		if response.Location == "" {
+			response.Location = event.Content.Address
+		}
+	}
+
+	return response
+}
+
+func EventToResponses(events *[]entity.Event) *[]model.EventResponse {
+	var eventResponses []model.EventResponse
+
+	log.Println("log from event to responses")
+
+	for _, event := range *events {
+		eventResponses = append(eventResponses, *EventToResponse(&event))
+	}
+
+	return &eventResponses
+}
+
+func formatEventTime(t time.Time, allDay bool) string {
+	if allDay {
+		return t.Format("2006-01-02")
+	}
+
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func ItemEditorToResponse(editor *entity.ItemEditor) *model.ItemEditorResponse {
+	log.Println("log from item editor to response")
+
+	return &model.ItemEditorResponse{
+		AdminID:    editor.AdminID,
+		Name:       editor.Admin.Name,
+		Username:   editor.Admin.Username,
+		AssignedAt: editor.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ItemEditorToResponses(editors *[]entity.ItemEditor) *[]model.ItemEditorResponse {
+	editorResponses := []model.ItemEditorResponse{}
+
+	log.Println("log from item editor to responses")
+
+	for _, editor := range *editors {
+		editorResponses = append(editorResponses, *ItemEditorToResponse(&editor))
+	}
+
+	return &editorResponses
+}
@@ -0,0 +1,103 @@
+package converter
+
+import (
+	"log"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func MessageToResponse(message *entity.Message) *model.MessageResponse {
+	log.Println("log from message to response")
+
+	response := &model.MessageResponse{
+		ID:         message.ID,
+		Name:       message.Name,
+		Email:      message.Email,
+		Phone:      message.Phone,
+		Subject:    message.Subject,
+		Body:       message.Body,
+		IsRead:     message.ReadAt != nil,
+		IsArchived: message.ArchivedAt != nil,
+		IsSpam:     message.IsSpam,
+		AssignedTo: message.AssignedTo,
+		Notes:      MessageNotesToThreads(message.Notes),
+		CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+	}
+
+	if message.Assignee != nil {
+		response.AssigneeName = message.Assignee.Name
+	}
+
+	for _, reply := range message.Replies {
+		response.Replies = append(response.Replies, *MessageReplyToResponse(&reply))
+	}
+
+	return response
+}
+
+func MessageToResponses(messages *[]entity.Message) *[]model.MessageResponse {
+	var messageResponses []model.MessageResponse
+
+	log.Println("log from message to responses")
+
+	for _, message := range *messages {
+		messageResponses = append(messageResponses, *MessageToResponse(&message))
+	}
+
+	return &messageResponses
+}
+
+func MessageNoteToResponse(note *entity.MessageNote) *model.MessageNoteResponse {
+	return &model.MessageNoteResponse{
+		ID:        note.ID,
+		ParentID:  note.ParentID,
+		Author:    note.Admin.Name,
+		Body:      note.Body,
+		CreatedAt: note.CreatedAt.Format(time.RFC3339),
+		Notes:     []model.MessageNoteResponse{},
+	}
+}
+
+// MessageNotesToThreads nests every note below the one it answers. Notes
+// are expected oldest first and stay in that order within a thread.
+func MessageNotesToThreads(notes []entity.MessageNote) []model.MessageNoteResponse {
+	children := map[uint][]entity.MessageNote{}
+	var roots []entity.MessageNote
+
+	for _, note := range notes {
+		if note.ParentID == nil {
+			roots = append(roots, note)
+		} else {
+			children[*note.ParentID] = append(children[*note.ParentID], note)
+		}
+	}
+
+	var build func(notes []entity.MessageNote) []model.MessageNoteResponse
+	build = func(notes []entity.MessageNote) []model.MessageNoteResponse {
+		responses := []model.MessageNoteResponse{}
+		for _, note := range notes {
+			response := MessageNoteToResponse(&note)
+			response.Notes = build(children[note.ID])
+			responses = append(responses, *response)
+		}
+		return responses
+	}
+
+	if len(roots) == 0 {
+		return nil
+	}
+
+	return build(roots)
+}
+
+func MessageReplyToResponse(reply *entity.MessageReply) *model.MessageReplyResponse {
+	return &model.MessageReplyResponse{
+		ID:      reply.ID,
+		Author:  reply.Admin.Name,
+		Subject: reply.Subject,
+		Body:    reply.Body,
+		SentAt:  reply.SentAt.Format(time.RFC3339),
+	}
+}
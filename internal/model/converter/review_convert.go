@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func ReviewToResponse(review *entity.Review) *model.ReviewResponse {
+	log.Println("log from review to response")
+
+	return &model.ReviewResponse{
+		ID:              review.ID,
+		ContentID:       review.ContentID,
+		ContentTitle:    review.Content.Title,
+		Rating:          review.Rating,
+		Body:            review.Body,
+		ReviewerName:    review.ReviewerName,
+		ReviewerContact: review.ReviewerContact,
+		Status:          review.Status,
+		CreatedAt:       review.CreatedAt.Format("2006-01-02"),
+	}
+}
+
+func ReviewToResponses(reviews *[]entity.Review) *[]model.ReviewResponse {
+	var reviewResponses []model.ReviewResponse
+
+	log.Println("log from review to responses")
+
+	for _, review := range *reviews {
+		reviewResponses = append(reviewResponses, *ReviewToResponse(&review))
+	}
+
+	return &reviewResponses
+}
+
+// ReviewToPublicResponse leaves out the reviewer contact and moderation
+// state, which only admins may see.
+func ReviewToPublicResponse(review *entity.Review) *model.ReviewResponse {
+	log.Println("log from review to public response")
+
+	return &model.ReviewResponse{
+		ID:           review.ID,
+		ContentID:    review.ContentID,
+		Rating:       review.Rating,
+		Body:         review.Body,
+		ReviewerName: review.ReviewerName,
+		CreatedAt:    review.CreatedAt.Format("2006-01-02"),
+	}
+}
+
+func ReviewToPublicResponses(reviews *[]entity.Review) *[]model.ReviewResponse {
+	var reviewResponses []model.ReviewResponse
+
+	log.Println("log from review to public responses")
+
+	for _, review := range *reviews {
+		reviewResponses = append(reviewResponses, *ReviewToPublicResponse(&review))
+	}
+
+	return &reviewResponses
+}
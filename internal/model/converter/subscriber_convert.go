@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func SubscriberToResponse(subscriber *entity.Subscriber) *model.SubscriberResponse {
+	log.Println("log from subscriber to response")
+
+	response := &model.SubscriberResponse{
+		ID:        subscriber.ID,
+		Email:     subscriber.Email,
+		Name:      subscriber.Name,
+		Topics:    []string{},
+		Confirmed: subscriber.ConfirmedAt != nil,
+		CreatedAt: subscriber.CreatedAt.Format(time.RFC3339),
+	}
+
+	if subscriber.Topics != "" {
+		response.Topics = strings.Split(subscriber.Topics, ",")
+	}
+
+	if subscriber.ConfirmedAt != nil {
+		response.ConfirmedAt = subscriber.ConfirmedAt.Format(time.RFC3339)
+	}
+
+	if subscriber.LastSentAt != nil {
+		response.LastSentAt = subscriber.LastSentAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+func SubscriberToResponses(subscribers *[]entity.Subscriber) *[]model.SubscriberResponse {
+	var responses []model.SubscriberResponse
+	for _, subscriber := range *subscribers {
+		responses = append(responses, *SubscriberToResponse(&subscriber))
+	}
+
+	return &responses
+}
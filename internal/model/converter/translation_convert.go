@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"log"
+	"slices"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/util"
+)
+
+func ContentTranslationToResponse(translation *entity.ContentTranslation) *model.TranslationResponse {
+	log.Println("log from content translation to response")
+
+	return &model.TranslationResponse{
+		Locale:        translation.Locale,
+		Title:         translation.Title,
+		Content:       translation.Content,
+		ContentFormat: translation.ContentFormat,
+		ContentHTML:   util.RenderContentHTML(translation.ContentFormat, translation.Content),
+		UpdatedAt:     translation.UpdatedAt.Format("2006-01-02"),
+	}
+}
+
+func ContentTranslationsToResponse(translations *[]entity.ContentTranslation) *model.TranslationListResponse {
+	response := &model.TranslationListResponse{Translations: []model.TranslationResponse{}}
+	var locales []string
+
+	log.Println("log from content translations to response")
+
+	for _, translation := range *translations {
+		response.Translations = append(response.Translations, *ContentTranslationToResponse(&translation))
+		locales = append(locales, translation.Locale)
+	}
+
+	response.MissingLocales = missingLocales(locales)
+	return response
+}
+
+func AnnouncementTranslationToResponse(translation *entity.AnnouncementTranslation) *model.TranslationResponse {
+	log.Println("log from announcement translation to response")
+
+	return &model.TranslationResponse{
+		Locale:        translation.Locale,
+		Title:         translation.Title,
+		Content:       translation.Content,
+		ContentFormat: translation.ContentFormat,
+		ContentHTML:   util.RenderContentHTML(translation.ContentFormat, translation.Content),
+		UpdatedAt:     translation.UpdatedAt.Format("2006-01-02"),
+	}
+}
+
+func AnnouncementTranslationsToResponse(translations *[]entity.AnnouncementTranslation) *model.TranslationListResponse {
+	response := &model.TranslationListResponse{Translations: []model.TranslationResponse{}}
+	var locales []string
+
+	log.Println("log from announcement translations to response")
+
+	for _, translation := range *translations {
+		response.Translations = append(response.Translations, *AnnouncementTranslationToResponse(&translation))
+		locales = append(locales, translation.Locale)
+	}
+
+	response.MissingLocales = missingLocales(locales)
+	return response
+}
+
+// missingLocales reports the supported locales without a translation. The
+// default locale lives on the item itself and is never missing.
+func missingLocales(translated []string) []string {
+	missing := []string{}
+
+	for _, locale := range util.SupportedLocales {
+		if locale != util.DefaultLocale && !slices.Contains(translated, locale) {
+			missing = append(missing, locale)
+		}
+	}
+
+	return missing
+}
+
+func localeOrDefault(locale string) string {
+	if locale == "" {
+		return util.DefaultLocale
+	}
+
+	return locale
+}
@@ -0,0 +1,30 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func ContentToTrashResponse(content *entity.Content, retention time.Duration) *model.TrashItemResponse {
+	return &model.TrashItemResponse{
+		Type:      "content",
+		ID:        content.ID,
+		Title:     content.Title,
+		Image:     content.Image,
+		DeletedAt: content.DeletedAt.Time.Format(time.RFC3339),
+		PurgeAt:   content.DeletedAt.Time.Add(retention).Format(time.RFC3339),
+	}
+}
+
+func AnnouncementToTrashResponse(announcement *entity.Announcement, retention time.Duration) *model.TrashItemResponse {
+	return &model.TrashItemResponse{
+		Type:      "announcement",
+		ID:        announcement.ID,
+		Title:     announcement.Title,
+		Image:     announcement.Image,
+		DeletedAt: announcement.DeletedAt.Time.Format(time.RFC3339),
+		PurgeAt:   announcement.DeletedAt.Time.Add(retention).Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,86 @@
+package converter
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+)
+
+func WebhookToResponse(webhook *entity.Webhook) *model.WebhookResponse {
+	log.Println("log from webhook to response")
+
+	response := &model.WebhookResponse{
+		ID:           webhook.ID,
+		URL:          webhook.URL,
+		Events:       []string{},
+		Description:  webhook.Description,
+		Active:       webhook.Active,
+		FailureCount: webhook.FailureCount,
+		CreatedBy:    webhook.Admin.Name,
+		CreatedAt:    webhook.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    webhook.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if webhook.Events != "" {
+		response.Events = strings.Split(webhook.Events, ",")
+	}
+
+	if webhook.DisabledAt != nil {
+		response.DisabledAt = webhook.DisabledAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+func WebhookToResponses(webhooks *[]entity.Webhook) *[]model.WebhookResponse {
+	var responses []model.WebhookResponse
+	for _, webhook := range *webhooks {
+		responses = append(responses, *WebhookToResponse(&webhook))
+	}
+
+	return &responses
+}
+
+// WebhookDeliveryToResponse includes the payload and the response body only
+// when detailed.
+func WebhookDeliveryToResponse(delivery *entity.WebhookDelivery, detailed bool) *model.WebhookDeliveryResponse {
+	response := &model.WebhookDeliveryResponse{
+		ID:           delivery.ID,
+		WebhookID:    delivery.WebhookID,
+		Event:        delivery.Event,
+		Status:       delivery.Status,
+		Attempts:     delivery.Attempts,
+		ResponseCode: delivery.ResponseCode,
+		Error:        delivery.Error,
+		RedeliveryOf: delivery.RedeliveryOf,
+		CreatedAt:    delivery.CreatedAt.Format(time.RFC3339),
+	}
+
+	if detailed {
+		response.Payload = json.RawMessage(delivery.Payload)
+		response.ResponseBody = delivery.ResponseBody
+	}
+
+	if delivery.NextAttemptAt != nil {
+		response.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	if delivery.DeliveredAt != nil {
+		response.DeliveredAt = delivery.DeliveredAt.Format(time.RFC3339)
+	}
+
+	return response
+}
+
+func WebhookDeliveryToResponses(deliveries *[]entity.WebhookDelivery) *[]model.WebhookDeliveryResponse {
+	var responses []model.WebhookDeliveryResponse
+	for _, delivery := range *deliveries {
+		responses = append(responses, *WebhookDeliveryToResponse(&delivery, false))
+	}
+
+	return &responses
+}
@@ -0,0 +1,54 @@
+package model
+
+// EventResponse describes an event. In a range query every occurrence of a
+// recurring event is listed on its own, with StartsAt and EndsAt of that
+// occurrence.
+type EventResponse struct {
+	ID           uint   `json:"id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Location     string `json:"location"`
+	ContentID    *uint  `json:"content_id"`
+	ContentTitle string `json:"content_title,omitempty"`
+	StartsAt     string `json:"starts_at"`
+	EndsAt       string `json:"ends_at"`
+	AllDay       bool   `json:"all_day"`
+	RRule        string `json:"rrule,omitempty"`
+	CreatedBy    string `json:"created_by"`
+	Version      uint   `json:"version"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// EventCreateRequest takes dates as YYYY-MM-DD for all-day events, where
+// EndsAt is the last day, and as RFC 3339 times otherwise.
+type EventCreateRequest struct {
+	Title       string `json:"title" validate:"required,max=150"`
+	Description string `json:"description" validate:"max=5000"`
+	Location    string `json:"location" validate:"max=255"`
+	ContentID   *uint  `json:"content_id" validate:"omitempty,min=1"`
+	StartsAt    string `json:"starts_at" validate:"required"`
+	EndsAt      string `json:"ends_at" validate:"required"`
+	AllDay      bool   `json:"all_day"`
+	RRule       string `json:"rrule" validate:"max=500"`
+	ActorID     uint   `json:"-"`
+}
+
+type EventUpdateRequest struct {
+	ID      uint `json:"-" validate:"required"`
+	Version uint `json:"-"`
+	EventCreateRequest
+}
+
+// EventRangeRequest takes dates as YYYY-MM-DD or RFC 3339 times. From
+// defaults to today and To to a month after From.
+type EventRangeRequest struct {
+	From string
+	To   string
+}
+
+type CalendarRequest struct {
+	EventID uint
+	// BaseURL is where the API serves the events, used to build the UIDs
+	// and links of the calendar.
+	BaseURL string
+}
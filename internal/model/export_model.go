@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+type ContentExportRequest struct {
+	Format  string               `json:"format" validate:"required,oneof=csv json xlsx"`
+	Columns []string             `json:"columns" validate:"dive,required"`
+	Filter  ContentFilterRequest `json:"filter"`
+}
+
+type AnnouncementExportRequest struct {
+	Format  string                    `json:"format" validate:"required,oneof=csv json xlsx"`
+	Columns []string                  `json:"columns" validate:"dive,required"`
+	Filter  AnnouncementFilterRequest `json:"filter"`
+}
+
+// ContentExportRow is one flat row of a contents export, scanned straight
+// from the database cursor.
+type ContentExportRow struct {
+	ID            uint
+	Title         string
+	Content       string
+	ContentFormat string
+	Image         string
+	Address       string
+	ContactInfo   string
+	Category      string
+	Tags          *string
+	Latitude      *float64
+	Longitude     *float64
+	IsFree        bool
+	Currency      string
+	PriceLevel    *uint8
+	MinPrice      *int64
+	MaxPrice      *int64
+	RatingAverage float64
+	ReviewCount   uint
+	IsFeatured    bool
+	Position      int
+	ViewCount     uint64
+	CreatedBy     uint
+	CreatedByName *string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// AnnouncementExportRow is one flat row of an announcements export.
+type AnnouncementExportRow struct {
+	ID              uint
+	Title           string
+	Content         string
+	ContentFormat   string
+	Image           string
+	Category        *string
+	Priority        string
+	ValidFrom       *time.Time
+	ValidUntil      *time.Time
+	Pinned          bool
+	PinnedUntil     *time.Time
+	ViewCount       uint64
+	PublishedBy     uint
+	PublishedByName *string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type FeedRequest struct {
+	Format   string `validate:"required,oneof=rss atom"`
+	Category string `validate:"omitempty,oneof=kuliner wisata kerajinan"`
+	Locale   string
+	// SelfURL is the address the feed was requested at, used as the id of
+	// an Atom feed. BaseURL is where the API serves the images.
+	SelfURL string
+	BaseURL string
+}
+
+type FeedResponse struct {
+	Body         string
+	ContentType  string
+	LastModified time.Time
+}
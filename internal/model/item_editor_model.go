@@ -0,0 +1,15 @@
+package model
+
+type ItemEditorResponse struct {
+	AdminID    uint   `json:"admin_id"`
+	Name       string `json:"name"`
+	Username   string `json:"username"`
+	AssignedAt string `json:"assigned_at"`
+}
+
+type ItemEditorRequest struct {
+	ItemType string `json:"-" validate:"required,oneof=content announcement"`
+	ItemID   uint   `json:"-" validate:"required"`
+	AdminID  uint   `json:"-" validate:"required"`
+	ActorID  uint   `json:"-" validate:"required"`
+}
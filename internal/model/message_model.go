@@ -0,0 +1,87 @@
+package model
+
+type MessageResponse struct {
+	ID           uint                   `json:"id"`
+	Name         string                 `json:"name"`
+	Email        string                 `json:"email"`
+	Phone        string                 `json:"phone"`
+	Subject      string                 `json:"subject"`
+	Body         string                 `json:"body"`
+	IsRead       bool                   `json:"is_read"`
+	IsArchived   bool                   `json:"is_archived"`
+	IsSpam       bool                   `json:"is_spam"`
+	AssignedTo   *uint                  `json:"assigned_to"`
+	AssigneeName string                 `json:"assignee_name,omitempty"`
+	Notes        []MessageNoteResponse  `json:"notes,omitempty"`
+	Replies      []MessageReplyResponse `json:"replies,omitempty"`
+	CreatedAt    string                 `json:"created_at"`
+}
+
+// MessageNoteResponse is a note with the notes answering it nested below.
+type MessageNoteResponse struct {
+	ID        uint                  `json:"id"`
+	ParentID  *uint                 `json:"parent_id"`
+	Author    string                `json:"author"`
+	Body      string                `json:"body"`
+	CreatedAt string                `json:"created_at"`
+	Notes     []MessageNoteResponse `json:"notes"`
+}
+
+type MessageReplyResponse struct {
+	ID      uint   `json:"id"`
+	Author  string `json:"author"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	SentAt  string `json:"sent_at"`
+}
+
+// MessageCreateRequest is the contact form. Website is a honeypot field
+// hidden from people; only bots fill it in.
+type MessageCreateRequest struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	Email     string `json:"email" validate:"required,email,max=255"`
+	Phone     string `json:"phone" validate:"omitempty,max=30"`
+	Subject   string `json:"subject" validate:"required,max=200"`
+	Body      string `json:"body" validate:"required,max=5000"`
+	Website   string `json:"website"`
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// MessageFilterRequest selects messages of the inbox. AssignedTo is "me",
+// "none" or the id of an admin.
+type MessageFilterRequest struct {
+	Folder     string `validate:"omitempty,oneof=inbox archived spam all"`
+	Status     string `validate:"omitempty,oneof=read unread"`
+	AssignedTo string
+	ActorID    uint
+}
+
+// MessageStateRequest changes only the states that are set.
+type MessageStateRequest struct {
+	ID       uint  `json:"-" validate:"required"`
+	Read     *bool `json:"read"`
+	Archived *bool `json:"archived"`
+	Spam     *bool `json:"spam"`
+}
+
+// MessageAssignRequest hands a message to an admin, or takes it back when
+// AdminID is null.
+type MessageAssignRequest struct {
+	ID      uint  `json:"-" validate:"required"`
+	AdminID *uint `json:"admin_id" validate:"omitempty,min=1"`
+}
+
+type MessageNoteRequest struct {
+	MessageID uint   `json:"-" validate:"required"`
+	ParentID  *uint  `json:"parent_id" validate:"omitempty,min=1"`
+	Body      string `json:"body" validate:"required,max=5000"`
+	ActorID   uint   `json:"-"`
+}
+
+type MessageReplyRequest struct {
+	MessageID uint   `json:"-" validate:"required"`
+	Subject   string `json:"subject" validate:"max=255"`
+	Body      string `json:"body" validate:"required,max=10000"`
+	ActorID   uint   `json:"-"`
+}
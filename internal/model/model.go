@@ -14,3 +14,11 @@ type ErrorResponse struct {
 	Message string   `json:"message"`
 	Details []string `json:"details"`
 }
+
+// PatchRequest carries a JSON Merge Patch (RFC 7386) for the item with ID.
+type PatchRequest struct {
+	ID      uint   `json:"-" validate:"required"`
+	Version uint   `json:"-"`
+	Patch   []byte `json:"-" validate:"required"`
+	ActorID uint   `json:"-"`
+}
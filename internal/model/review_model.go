@@ -0,0 +1,27 @@
+package model
+
+type ReviewResponse struct {
+	ID              uint   `json:"id"`
+	ContentID       uint   `json:"content_id"`
+	ContentTitle    string `json:"content_title,omitempty"`
+	Rating          uint8  `json:"rating"`
+	Body            string `json:"body"`
+	ReviewerName    string `json:"reviewer_name"`
+	ReviewerContact string `json:"reviewer_contact,omitempty"`
+	Status          string `json:"status,omitempty"`
+	CreatedAt       string `json:"created_at"`
+}
+
+type ReviewCreateRequest struct {
+	ContentID       uint   `json:"-" validate:"required"`
+	Rating          uint8  `json:"rating" validate:"required,min=1,max=5"`
+	Body            string `json:"body" validate:"max=2000"`
+	ReviewerName    string `json:"reviewer_name" validate:"required,max=80"`
+	ReviewerContact string `json:"reviewer_contact" validate:"omitempty,max=100"`
+}
+
+type ReviewModerateRequest struct {
+	ID          uint   `json:"-" validate:"required"`
+	Status      string `json:"-" validate:"required,oneof=approved rejected"`
+	ModeratedBy uint   `json:"-" validate:"required"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// SitemapEntry is one published item listed in the sitemap. Locales holds the
+// comma separated locales the item has been translated to.
+type SitemapEntry struct {
+	ID        uint
+	Category  string
+	UpdatedAt time.Time
+	Locales   *string
+}
+
+// SitemapState summarises a table so the sitemap can tell when it went stale
+// without loading every row.
+type SitemapState struct {
+	Total          int64
+	LastModified   *time.Time
+	Translations   int64
+	LastTranslated *time.Time
+}
@@ -0,0 +1,35 @@
+package model
+
+type SubscriberResponse struct {
+	ID          uint     `json:"id"`
+	Email       string   `json:"email"`
+	Name        string   `json:"name"`
+	Topics      []string `json:"topics"`
+	Confirmed   bool     `json:"confirmed"`
+	ConfirmedAt string   `json:"confirmed_at,omitempty"`
+	LastSentAt  string   `json:"last_sent_at,omitempty"`
+	CreatedAt   string   `json:"created_at"`
+}
+
+// SubscribeRequest signs an address up. Without topics it gets all of them.
+type SubscribeRequest struct {
+	Email  string   `json:"email" validate:"required,email,max=255"`
+	Name   string   `json:"name" validate:"max=100"`
+	Topics []string `json:"topics" validate:"dive,oneof=announcements kuliner wisata events"`
+}
+
+type SubscriberPreferenceRequest struct {
+	Token  string   `json:"-" validate:"required,len=64,hexadecimal"`
+	Name   *string  `json:"name" validate:"omitempty,max=100"`
+	Topics []string `json:"topics" validate:"required,min=1,dive,oneof=announcements kuliner wisata events"`
+}
+
+type SubscriberFilterRequest struct {
+	Status  string `validate:"omitempty,oneof=confirmed pending"`
+	ActorID uint
+}
+
+type DigestResponse struct {
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
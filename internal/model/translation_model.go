@@ -0,0 +1,26 @@
+package model
+
+type TranslationResponse struct {
+	Locale        string `json:"locale"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+	ContentHTML   string `json:"content_html"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type TranslationListResponse struct {
+	Translations   []TranslationResponse `json:"translations"`
+	MissingLocales []string              `json:"missing_locales"`
+}
+
+// TranslationRequest takes ItemID and Locale from the route. They are part
+// of its JSON so a change request queued for review can be replayed.
+type TranslationRequest struct {
+	ItemID        uint   `json:"item_id" validate:"required"`
+	Locale        string `json:"locale" validate:"required"`
+	Title         string `json:"title" validate:"required,max=150"`
+	Content       string `json:"content" validate:"required"`
+	ContentFormat string `json:"content_format" validate:"omitempty,oneof=markdown html"`
+	ActorID       uint   `json:"-"`
+}
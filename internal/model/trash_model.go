@@ -0,0 +1,10 @@
+package model
+
+type TrashItemResponse struct {
+	Type      string `json:"type"`
+	ID        uint   `json:"id"`
+	Title     string `json:"title"`
+	Image     string `json:"image"`
+	DeletedAt string `json:"deleted_at"`
+	PurgeAt   string `json:"purge_at"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import "encoding/json"
+
+// WebhookResponse shows the secret only right after the webhook is created.
+type WebhookResponse struct {
+	ID           uint     `json:"id"`
+	URL          string   `json:"url"`
+	Events       []string `json:"events"`
+	Description  string   `json:"description"`
+	Active       bool     `json:"active"`
+	FailureCount uint     `json:"failure_count"`
+	DisabledAt   string   `json:"disabled_at,omitempty"`
+	Secret       string   `json:"secret,omitempty"`
+	CreatedBy    string   `json:"created_by"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse leaves out the payload and the response body in
+// lists.
+type WebhookDeliveryResponse struct {
+	ID            uint            `json:"id"`
+	WebhookID     uint            `json:"webhook_id"`
+	Event         string          `json:"event"`
+	Status        string          `json:"status"`
+	Attempts      uint            `json:"attempts"`
+	ResponseCode  *int            `json:"response_code"`
+	ResponseBody  string          `json:"response_body,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+	NextAttemptAt string          `json:"next_attempt_at,omitempty"`
+	DeliveredAt   string          `json:"delivered_at,omitempty"`
+	RedeliveryOf  *uint           `json:"redelivery_of"`
+	CreatedAt     string          `json:"created_at"`
+}
+
+// WebhookCreateRequest registers a webhook. A secret is generated when none
+// is given.
+type WebhookCreateRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=500"`
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=content.created content.updated content.deleted announcement.published"`
+	Description string   `json:"description" validate:"max=255"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=64"`
+	ActorID     uint     `json:"-"`
+}
+
+// WebhookUpdateRequest changes a webhook. Setting Active again re-enables a
+// webhook that was disabled after failing.
+type WebhookUpdateRequest struct {
+	ID          uint     `json:"-" validate:"required"`
+	URL         string   `json:"url" validate:"required,url,max=500"`
+	Events      []string `json:"events" validate:"required,min=1,dive,oneof=content.created content.updated content.deleted announcement.published"`
+	Description string   `json:"description" validate:"max=255"`
+	Active      *bool    `json:"active" validate:"required"`
+	ActorID     uint     `json:"-"`
+}
+
+type WebhookDeliveryFilterRequest struct {
+	WebhookID uint   `validate:"required"`
+	Status    string `validate:"omitempty,oneof=pending succeeded failed"`
+	ActorID   uint
+}
+
+type WebhookDeliveryRequest struct {
+	WebhookID  uint `validate:"required"`
+	DeliveryID uint `validate:"required"`
+	ActorID    uint
+}
@@ -8,6 +8,8 @@ import (
 type AdminRepository interface {
 	Create(tx *gorm.DB, admin *entity.Admin) error
 	Update(tx *gorm.DB, admin *entity.Admin) error
+	Patch(tx *gorm.DB, admin *entity.Admin, columns []string) error
+	LockVersion(tx *gorm.DB, id uint) (uint, error)
 	Delete(tx *gorm.DB, admin *entity.Admin) error
 	FindAll(tx *gorm.DB, adminId uint, admins *[]entity.Admin) error
 	FindById(tx *gorm.DB, admin *entity.Admin) error
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type AnnouncementAttachmentRepository interface {
+	Create(tx *gorm.DB, attachment *entity.AnnouncementAttachment) error
+	Update(tx *gorm.DB, attachment *entity.AnnouncementAttachment) error
+	Delete(tx *gorm.DB, attachment *entity.AnnouncementAttachment) error
+	FindById(tx *gorm.DB, attachment *entity.AnnouncementAttachment) error
+	FindPublished(tx *gorm.DB, now time.Time, attachment *entity.AnnouncementAttachment) error
+	FindByAnnouncement(tx *gorm.DB, announcementId uint, attachments *[]entity.AnnouncementAttachment) error
+	CountByAnnouncement(tx *gorm.DB, announcementId uint) (int64, error)
+	IncrementDownloadCount(tx *gorm.DB, attachmentId uint) error
+}
+
+type AnnouncementAttachmentRepositoryImpl struct {
+	Repository[entity.AnnouncementAttachment]
+}
+
+func NewAnnouncementAttachmentRepository() AnnouncementAttachmentRepository {
+	return &AnnouncementAttachmentRepositoryImpl{}
+}
+
+// FindById implements AnnouncementAttachmentRepository.
+func (repository *AnnouncementAttachmentRepositoryImpl) FindById(tx *gorm.DB, attachment *entity.AnnouncementAttachment) error {
+	return tx.First(attachment, "id = ? AND announcement_id = ?", attachment.ID, attachment.AnnouncementID).Error
+}
+
+// FindPublished implements AnnouncementAttachmentRepository. Attachments of
+// announcements in the trash or outside their validity window are not found.
+func (repository *AnnouncementAttachmentRepositoryImpl) FindPublished(tx *gorm.DB, now time.Time, attachment *entity.AnnouncementAttachment) error {
+	return validAnnouncements(tx.Joins("JOIN announcements ON announcements.id = announcement_attachments.announcement_id AND announcements.deleted_at IS NULL"), now).
+		Where("announcement_attachments.id = ? AND announcement_attachments.announcement_id = ?", attachment.ID, attachment.AnnouncementID).
+		First(attachment).Error
+}
+
+// FindByAnnouncement implements AnnouncementAttachmentRepository.
+func (repository *AnnouncementAttachmentRepositoryImpl) FindByAnnouncement(tx *gorm.DB, announcementId uint, attachments *[]entity.AnnouncementAttachment) error {
+	return tx.Where("announcement_id = ?", announcementId).Order("id ASC").Find(attachments).Error
+}
+
+// CountByAnnouncement implements AnnouncementAttachmentRepository.
+func (repository *AnnouncementAttachmentRepositoryImpl) CountByAnnouncement(tx *gorm.DB, announcementId uint) (int64, error) {
+	var total int64
+	err := tx.Model(&entity.AnnouncementAttachment{}).Where("announcement_id = ?", announcementId).Count(&total).Error
+	return total, err
+}
+
+// IncrementDownloadCount implements AnnouncementAttachmentRepository.
+func (repository *AnnouncementAttachmentRepositoryImpl) IncrementDownloadCount(tx *gorm.DB, attachmentId uint) error {
+	return tx.Model(&entity.AnnouncementAttachment{}).
+		Where("id = ?", attachmentId).
+		UpdateColumn("download_count", gorm.Expr("download_count + ?", 1)).Error
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type AnnouncementCategoryRepository interface {
+	Create(tx *gorm.DB, category *entity.AnnouncementCategory) error
+	Update(tx *gorm.DB, category *entity.AnnouncementCategory) error
+	Delete(tx *gorm.DB, category *entity.AnnouncementCategory) error
+	FindById(tx *gorm.DB, category *entity.AnnouncementCategory) error
+	FindAll(tx *gorm.DB, categories *[]entity.AnnouncementCategory) error
+	CountByNameOrSlug(tx *gorm.DB, category *entity.AnnouncementCategory) (int64, error)
+}
+
+type AnnouncementCategoryRepositoryImpl struct {
+	Repository[entity.AnnouncementCategory]
+}
+
+func NewAnnouncementCategoryRepository() AnnouncementCategoryRepository {
+	return &AnnouncementCategoryRepositoryImpl{}
+}
+
+// FindById implements AnnouncementCategoryRepository.
+func (repository *AnnouncementCategoryRepositoryImpl) FindById(tx *gorm.DB, category *entity.AnnouncementCategory) error {
+	return tx.First(category).Error
+}
+
+// FindAll implements AnnouncementCategoryRepository.
+func (repository *AnnouncementCategoryRepositoryImpl) FindAll(tx *gorm.DB, categories *[]entity.AnnouncementCategory) error {
+	return tx.Order("name ASC").Find(categories).Error
+}
+
+// CountByNameOrSlug implements AnnouncementCategoryRepository. The category
+// itself is left out, so that it can keep its own name.
+func (repository *AnnouncementCategoryRepositoryImpl) CountByNameOrSlug(tx *gorm.DB, category *entity.AnnouncementCategory) (int64, error) {
+	var total int64
+	err := tx.Model(&entity.AnnouncementCategory{}).
+		Where("(name = ? OR slug = ?) AND id <> ?", category.Name, category.Slug, category.ID).
+		Count(&total).Error
+	return total, err
+}
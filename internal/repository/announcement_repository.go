@@ -1,17 +1,36 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AnnouncementRepository interface {
 	Create(tx *gorm.DB, announcement *entity.Announcement) error
 	Update(tx *gorm.DB, announcement *entity.Announcement) error
+	Patch(tx *gorm.DB, announcement *entity.Announcement, columns []string) error
+	LockVersion(tx *gorm.DB, id uint) (uint, error)
+	FindDeleted(tx *gorm.DB, before *time.Time, announcements *[]entity.Announcement) error
+	FindDeletedById(tx *gorm.DB, announcement *entity.Announcement) error
+	Restore(tx *gorm.DB, announcement *entity.Announcement) error
+	Purge(tx *gorm.DB, announcement *entity.Announcement) error
+	CountByImage(tx *gorm.DB, image string) (int64, error)
 	Delete(tx *gorm.DB, announcement *entity.Announcement) error
-	FindAll(tx *gorm.DB, order string, announcements *[]entity.Announcement) error
+	FindAll(tx *gorm.DB, request *model.AnnouncementFilterRequest, now time.Time, announcements *[]entity.Announcement) error
 	FindById(tx *gorm.DB, announcement *entity.Announcement) error
-	GetFirst(tx *gorm.DB, announcement *entity.Announcement) error
+	GetFirst(tx *gorm.DB, now time.Time, announcement *entity.Announcement) error
+	IncrementViewCount(tx *gorm.DB, announcementId uint, views uint64) error
+	StreamExport(tx *gorm.DB, request *model.AnnouncementFilterRequest, fn func(row *model.AnnouncementExportRow) error) error
+	FindSitemapEntries(tx *gorm.DB, now time.Time, entries *[]model.SitemapEntry) error
+	FindLatest(tx *gorm.DB, now time.Time, limit int, announcements *[]entity.Announcement) error
+	FindCreatedSince(tx *gorm.DB, since time.Time, now time.Time, limit int, announcements *[]entity.Announcement) error
+	SitemapState(tx *gorm.DB, now time.Time, state *model.SitemapState) error
+	FindUnpublishedIds(tx *gorm.DB, now time.Time, limit int, ids *[]uint) error
+	MarkPublished(tx *gorm.DB, id uint, now time.Time) (bool, error)
 }
 
 type AnnouncementRepositoryImpl struct {
@@ -23,23 +42,185 @@ func NewAnnouncementRepository() AnnouncementRepository {
 }
 
 // FindAll implements AnnouncementRepository.
-func (repository *AnnouncementRepositoryImpl) FindAll(tx *gorm.DB, order string, announcements *[]entity.Announcement) error {
-	if order == "ASC" {
-		return tx.Joins("Admin").
-			Order("announcements.created_at ASC").
-			Find(announcements).Limit(4).Error
+func (repository *AnnouncementRepositoryImpl) FindAll(tx *gorm.DB, request *model.AnnouncementFilterRequest, now time.Time, announcements *[]entity.Announcement) error {
+	direction := "DESC"
+	if request.Order == "ASC" {
+		direction = "ASC"
 	}
-	return tx.Joins("Admin").
-		Order("announcements.created_at DESC").
-		Find(announcements).Limit(4).Error
+
+	query := validAnnouncements(tx.Joins("Admin").Joins("Category").Preload("Translations").Preload("Attachments", orderAttachments), now)
+
+	if request.Priority != "" {
+		query = query.Where("announcements.priority = ?", request.Priority)
+	}
+
+	if request.Category != "" {
+		query = query.Where("Category.slug = ?", request.Category)
+	}
+
+	return query.
+		Order(announcementRanking(now, direction)).
+		Find(announcements).Error
 }
 
 // FindById implements AnnouncementRepository.
 func (repository *AnnouncementRepositoryImpl) FindById(tx *gorm.DB, announcement *entity.Announcement) error {
-	return tx.Joins("Admin").First(announcement).Error
+	return tx.Joins("Admin").Joins("Category").Preload("Translations").Preload("Attachments", orderAttachments).First(announcement).Error
+}
+
+// Update implements AnnouncementRepository. The view count and the time of
+// publishing are left out, only IncrementViewCount and MarkPublished keep
+// them.
+func (repository *AnnouncementRepositoryImpl) Update(tx *gorm.DB, announcement *entity.Announcement) error {
+	return tx.Omit("view_count", "published_at").Save(announcement).Error
 }
 
 // GetFirst implements AnnouncementRepository.
-func (repository *AnnouncementRepositoryImpl) GetFirst(tx *gorm.DB, announcement *entity.Announcement) error {
-	return tx.Joins("Admin").Order("announcements.created_at DESC").First(announcement).Error
+func (repository *AnnouncementRepositoryImpl) GetFirst(tx *gorm.DB, now time.Time, announcement *entity.Announcement) error {
+	// Take, as the primary key order First adds would replace the ranking
+	return validAnnouncements(tx.Joins("Admin").Joins("Category").Preload("Translations").Preload("Attachments", orderAttachments), now).
+		Order(announcementRanking(now, "DESC")).
+		Take(announcement).Error
+}
+
+// IncrementViewCount implements AnnouncementRepository.
+func (repository *AnnouncementRepositoryImpl) IncrementViewCount(tx *gorm.DB, announcementId uint, views uint64) error {
+	return tx.Model(&entity.Announcement{}).
+		Where("id = ?", announcementId).
+		UpdateColumn("view_count", gorm.Expr("view_count + ?", views)).Error
+}
+
+// StreamExport implements AnnouncementRepository. Unlike FindAll it takes
+// announcements outside their validity window along.
+func (repository *AnnouncementRepositoryImpl) StreamExport(tx *gorm.DB, request *model.AnnouncementFilterRequest, fn func(row *model.AnnouncementExportRow) error) error {
+	direction := "DESC"
+	if request.Order == "ASC" {
+		direction = "ASC"
+	}
+
+	query := tx.Model(&entity.Announcement{}).
+		Select("announcements.*, admins.name AS published_by_name, announcement_categories.slug AS category").
+		Joins("LEFT JOIN admins ON admins.id = announcements.published_by").
+		Joins("LEFT JOIN announcement_categories ON announcement_categories.id = announcements.category_id")
+
+	if request.Priority != "" {
+		query = query.Where("announcements.priority = ?", request.Priority)
+	}
+
+	if request.Category != "" {
+		query = query.Where("announcement_categories.slug = ?", request.Category)
+	}
+
+	rows, err := query.
+		Order("announcements.created_at " + direction).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := new(model.AnnouncementExportRow)
+		if err := tx.ScanRows(rows, row); err != nil {
+			return err
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// FindSitemapEntries implements AnnouncementRepository.
+func (repository *AnnouncementRepositoryImpl) FindSitemapEntries(tx *gorm.DB, now time.Time, entries *[]model.SitemapEntry) error {
+	return validAnnouncements(tx.Model(&entity.Announcement{}), now).
+		Select("announcements.id, announcements.updated_at, " +
+			"(SELECT GROUP_CONCAT(announcement_translations.locale ORDER BY announcement_translations.locale SEPARATOR ',') FROM announcement_translations WHERE announcement_translations.announcement_id = announcements.id) AS locales").
+		Order("announcements.id ASC").
+		Scan(entries).Error
+}
+
+// FindLatest implements AnnouncementRepository.
+func (repository *AnnouncementRepositoryImpl) FindLatest(tx *gorm.DB, now time.Time, limit int, announcements *[]entity.Announcement) error {
+	return validAnnouncements(tx.Joins("Admin").Preload("Translations"), now).
+		Order("announcements.created_at DESC").
+		Order("announcements.id DESC").
+		Limit(limit).
+		Find(announcements).Error
+}
+
+// FindCreatedSince implements AnnouncementRepository. An announcement
+// scheduled ahead counts as created once its validity window opens.
+func (repository *AnnouncementRepositoryImpl) FindCreatedSince(tx *gorm.DB, since time.Time, now time.Time, limit int, announcements *[]entity.Announcement) error {
+	return validAnnouncements(tx.Joins("Admin").Preload("Translations"), now).
+		Where(announcementVisibleSince+" > ?", since).
+		Order(announcementVisibleSince + " ASC").
+		Order("announcements.id ASC").
+		Limit(limit).
+		Find(announcements).Error
+}
+
+// SitemapState implements AnnouncementRepository.
+func (repository *AnnouncementRepositoryImpl) SitemapState(tx *gorm.DB, now time.Time, state *model.SitemapState) error {
+	return validAnnouncements(tx.Model(&entity.Announcement{}), now).
+		Select("COUNT(*) AS total, MAX(announcements.updated_at) AS last_modified, " +
+			"(SELECT COUNT(*) FROM announcement_translations) AS translations, " +
+			"(SELECT MAX(announcement_translations.updated_at) FROM announcement_translations) AS last_translated").
+		Scan(state).Error
+}
+
+// FindUnpublishedIds implements AnnouncementRepository. It finds the
+// announcements whose validity window is open but which were not announced
+// yet, in the order they became visible.
+func (repository *AnnouncementRepositoryImpl) FindUnpublishedIds(tx *gorm.DB, now time.Time, limit int, ids *[]uint) error {
+	return validAnnouncements(tx.Model(&entity.Announcement{}), now).
+		Where("announcements.published_at IS NULL").
+		Order(announcementVisibleSince+" ASC").
+		Order("announcements.id ASC").
+		Limit(limit).
+		Pluck("announcements.id", ids).Error
+}
+
+// MarkPublished implements AnnouncementRepository. It only marks an
+// announcement whose validity window is open and reports false when it is
+// not, or when it was marked already.
+func (repository *AnnouncementRepositoryImpl) MarkPublished(tx *gorm.DB, id uint, now time.Time) (bool, error) {
+	// UpdateColumn keeps updated_at and version, publishing is no edit
+	result := validAnnouncements(tx.Model(&entity.Announcement{}), now).
+		Where("announcements.id = ? AND announcements.published_at IS NULL", id).
+		UpdateColumn("published_at", now)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// validAnnouncements narrows query to the announcements whose validity
+// window contains now. A missing bound leaves that side open.
+func validAnnouncements(query *gorm.DB, now time.Time) *gorm.DB {
+	return query.
+		Where("announcements.valid_from IS NULL OR announcements.valid_from <= ?", now).
+		Where("announcements.valid_until IS NULL OR announcements.valid_until > ?", now)
+}
+
+// announcementVisibleSince is when an announcement became visible: when it
+// was created or, if later, when its validity window opened.
+const announcementVisibleSince = "GREATEST(announcements.created_at, COALESCE(announcements.valid_from, announcements.created_at))"
+
+// announcementRanking puts announcements with a running pin first, then
+// urgent ones before important ones and those before plain information,
+// then orders by creation date in direction.
+func announcementRanking(now time.Time, direction string) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL: "(announcements.pinned = TRUE AND (announcements.pinned_until IS NULL OR announcements.pinned_until > ?)) DESC, " +
+			"FIELD(announcements.priority, 'urgent', 'important', 'info'), " +
+			"announcements.created_at " + direction,
+		Vars:               []any{now},
+		WithoutParentheses: true,
+	}}
+}
+
+// orderAttachments lists the attachments of an announcement in upload order.
+func orderAttachments(tx *gorm.DB) *gorm.DB {
+	return tx.Order("announcement_attachments.id ASC")
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type AnnouncementTranslationRepository interface {
+	Create(tx *gorm.DB, translation *entity.AnnouncementTranslation) error
+	Update(tx *gorm.DB, translation *entity.AnnouncementTranslation) error
+	Delete(tx *gorm.DB, translation *entity.AnnouncementTranslation) error
+	FindByLocale(tx *gorm.DB, translation *entity.AnnouncementTranslation) error
+	FindAllByAnnouncementId(tx *gorm.DB, announcementId uint, translations *[]entity.AnnouncementTranslation) error
+}
+
+type AnnouncementTranslationRepositoryImpl struct {
+	Repository[entity.AnnouncementTranslation]
+}
+
+func NewAnnouncementTranslationRepository() AnnouncementTranslationRepository {
+	return &AnnouncementTranslationRepositoryImpl{}
+}
+
+// FindByLocale implements AnnouncementTranslationRepository.
+func (repository *AnnouncementTranslationRepositoryImpl) FindByLocale(tx *gorm.DB, translation *entity.AnnouncementTranslation) error {
+	return tx.First(translation, "announcement_id = ? AND locale = ?", translation.AnnouncementID, translation.Locale).Error
+}
+
+// FindAllByAnnouncementId implements AnnouncementTranslationRepository.
+func (repository *AnnouncementTranslationRepositoryImpl) FindAllByAnnouncementId(tx *gorm.DB, announcementId uint, translations *[]entity.AnnouncementTranslation) error {
+	return tx.Where("announcement_id = ?", announcementId).Order("locale ASC").Find(translations).Error
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ChangeRequestRepository interface {
+	Create(tx *gorm.DB, change *entity.ChangeRequest) error
+	FindById(tx *gorm.DB, change *entity.ChangeRequest) error
+	FindPending(tx *gorm.DB, itemType string, changes *[]entity.ChangeRequest) error
+	FindByRequester(tx *gorm.DB, adminId uint, changes *[]entity.ChangeRequest) error
+	Review(tx *gorm.DB, change *entity.ChangeRequest) (bool, error)
+	SetItemId(tx *gorm.DB, change *entity.ChangeRequest) error
+}
+
+type ChangeRequestRepositoryImpl struct {
+	Repository[entity.ChangeRequest]
+}
+
+func NewChangeRequestRepository() ChangeRequestRepository {
+	return &ChangeRequestRepositoryImpl{}
+}
+
+// FindById implements ChangeRequestRepository.
+func (repository *ChangeRequestRepositoryImpl) FindById(tx *gorm.DB, change *entity.ChangeRequest) error {
+	return tx.Joins("Requester").Joins("Reviewer").First(change).Error
+}
+
+// FindPending implements ChangeRequestRepository.
+func (repository *ChangeRequestRepositoryImpl) FindPending(tx *gorm.DB, itemType string, changes *[]entity.ChangeRequest) error {
+	query := tx.Joins("Requester").Where("change_requests.status = ?", "pending")
+
+	if itemType != "" {
+		query = query.Where("change_requests.item_type = ?", itemType)
+	}
+
+	return query.Order("change_requests.created_at ASC").Find(changes).Error
+}
+
+// FindByRequester implements ChangeRequestRepository.
+func (repository *ChangeRequestRepositoryImpl) FindByRequester(tx *gorm.DB, adminId uint, changes *[]entity.ChangeRequest) error {
+	return tx.Joins("Requester").Joins("Reviewer").
+		Where("change_requests.requested_by = ?", adminId).
+		Order("change_requests.created_at DESC").
+		Find(changes).Error
+}
+
+// Review implements ChangeRequestRepository. It only moves a pending change
+// on and reports false when another reviewer got there first.
+func (repository *ChangeRequestRepositoryImpl) Review(tx *gorm.DB, change *entity.ChangeRequest) (bool, error) {
+	result := tx.Model(change).
+		Where("status = ?", "pending").
+		Select("status", "reviewed_by", "reviewed_at", "review_comment").
+		Updates(change)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// SetItemId implements ChangeRequestRepository.
+func (repository *ChangeRequestRepositoryImpl) SetItemId(tx *gorm.DB, change *entity.ChangeRequest) error {
+	return tx.Model(change).Update("item_id", change.ItemID).Error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ContentContactRepository interface {
+	DeleteByContentId(tx *gorm.DB, contentId uint) error
+}
+
+type ContentContactRepositoryImpl struct {
+	Repository[entity.ContentContact]
+}
+
+func NewContentContactRepository() ContentContactRepository {
+	return &ContentContactRepositoryImpl{}
+}
+
+// DeleteByContentId implements ContentContactRepository.
+func (repository *ContentContactRepositoryImpl) DeleteByContentId(tx *gorm.DB, contentId uint) error {
+	return tx.Where("content_id = ?", contentId).Delete(&entity.ContentContact{}).Error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ContentPriceRepository interface {
+	DeleteByContentId(tx *gorm.DB, contentId uint) error
+}
+
+type ContentPriceRepositoryImpl struct {
+	Repository[entity.ContentPrice]
+}
+
+func NewContentPriceRepository() ContentPriceRepository {
+	return &ContentPriceRepositoryImpl{}
+}
+
+// DeleteByContentId implements ContentPriceRepository.
+func (repository *ContentPriceRepositoryImpl) DeleteByContentId(tx *gorm.DB, contentId uint) error {
+	return tx.Where("content_id = ?", contentId).Delete(&entity.ContentPrice{}).Error
+}
@@ -1,17 +1,42 @@
 package repository
 
 import (
+	"math"
+	"time"
+
 	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ContentRepository interface {
 	Create(tx *gorm.DB, content *entity.Content) error
 	Update(tx *gorm.DB, content *entity.Content) error
+	Patch(tx *gorm.DB, content *entity.Content, columns []string) error
+	LockVersion(tx *gorm.DB, id uint) (uint, error)
+	FindDeleted(tx *gorm.DB, before *time.Time, contents *[]entity.Content) error
+	FindDeletedById(tx *gorm.DB, content *entity.Content) error
+	Restore(tx *gorm.DB, content *entity.Content) error
+	Purge(tx *gorm.DB, content *entity.Content) error
+	CountByImage(tx *gorm.DB, image string) (int64, error)
 	Delete(tx *gorm.DB, content *entity.Content) error
-	FindAll(tx *gorm.DB, order string, category string, contents *[]entity.Content) error
-	FindWithLimit(tx *gorm.DB, order string, category string, contents *[]entity.Content) error
+	FindAll(tx *gorm.DB, request *model.ContentFilterRequest, contents *[]entity.Content) error
+	FindWithLimit(tx *gorm.DB, request *model.ContentFilterRequest, contents *[]entity.Content) error
 	FindById(tx *gorm.DB, content *entity.Content) error
+	RefreshRating(tx *gorm.DB, contentId uint) error
+	FindFeatured(tx *gorm.DB, contents *[]entity.Content) error
+	CountByIds(tx *gorm.DB, ids []uint) (int64, error)
+	UpdatePositions(tx *gorm.DB, ids []uint) error
+	IncrementViewCount(tx *gorm.DB, contentId uint, views uint64) error
+	FindPopular(tx *gorm.DB, since *time.Time, category string, contents *[]entity.Content) error
+	FindRelatedCandidates(tx *gorm.DB, content *entity.Content, radiusKm float64, limit int, contents *[]entity.Content) error
+	FindByIds(tx *gorm.DB, ids []uint, contents *[]entity.Content) error
+	StreamExport(tx *gorm.DB, request *model.ContentFilterRequest, fn func(row *model.ContentExportRow) error) error
+	FindSitemapEntries(tx *gorm.DB, entries *[]model.SitemapEntry) error
+	FindLatest(tx *gorm.DB, category string, limit int, contents *[]entity.Content) error
+	FindCreatedSince(tx *gorm.DB, categories []string, since time.Time, limit int, contents *[]entity.Content) error
+	SitemapState(tx *gorm.DB, state *model.SitemapState) error
 }
 
 type ContentRepositoryImpl struct {
@@ -28,62 +53,294 @@ func (repository *ContentRepositoryImpl) FindByIdWithAdmin(tx *gorm.DB, content
 }
 
 // FindAll implements ContentRepository.
-func (repository *ContentRepositoryImpl) FindAll(tx *gorm.DB, order string, category string, contents *[]entity.Content) error {
-	if order == "ASC" {
-		if category != "" {
-			return tx.Joins("Admin").
-				Order("contents.created_at ASC").
-				Where("contents.category = ?", category).
-				Find(contents).Error
+func (repository *ContentRepositoryImpl) FindAll(tx *gorm.DB, request *model.ContentFilterRequest, contents *[]entity.Content) error {
+	return tx.Joins("Admin").
+		Preload("Prices").
+		Preload("Tags").
+		Preload("Contacts", orderContactsByPosition).
+		Preload("Translations").
+		Scopes(repository.filterContents(request)).
+		Find(contents).Error
+}
+
+// FindWithLimit implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindWithLimit(tx *gorm.DB, request *model.ContentFilterRequest, contents *[]entity.Content) error {
+	return tx.Joins("Admin").
+		Preload("Prices").
+		Preload("Tags").
+		Preload("Contacts", orderContactsByPosition).
+		Preload("Translations").
+		Scopes(repository.filterContents(request)).
+		Limit(8).
+		Find(contents).Error
+}
+
+// FindById implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindById(tx *gorm.DB, content *entity.Content) error {
+	return tx.Joins("Admin").
+		Preload("Prices").
+		Preload("Tags").
+		Preload("Contacts", orderContactsByPosition).
+		Preload("Translations").
+		First(content).Error
+}
+
+// Update implements ContentRepository. The rating columns and the view
+// count are left out: only RefreshRating and IncrementViewCount keep them.
+func (repository *ContentRepositoryImpl) Update(tx *gorm.DB, content *entity.Content) error {
+	return tx.Omit("rating_average", "review_count", "view_count").Save(content).Error
+}
+
+// RefreshRating implements ContentRepository.
+func (repository *ContentRepositoryImpl) RefreshRating(tx *gorm.DB, contentId uint) error {
+	return tx.Exec(`UPDATE contents SET
+		review_count = (SELECT COUNT(*) FROM reviews WHERE content_id = ? AND status = 'approved'),
+		rating_average = (SELECT COALESCE(AVG(rating), 0) FROM reviews WHERE content_id = ? AND status = 'approved'),
+		updated_at = updated_at
+		WHERE id = ?`, contentId, contentId, contentId).Error
+}
+
+// FindFeatured implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindFeatured(tx *gorm.DB, contents *[]entity.Content) error {
+	return tx.Joins("Admin").
+		Preload("Prices").
+		Preload("Tags").
+		Preload("Contacts", orderContactsByPosition).
+		Preload("Translations").
+		Where("contents.is_featured = ?", true).
+		Order("contents.category ASC").
+		Order("contents.position ASC").
+		Order("contents.created_at DESC").
+		Find(contents).Error
+}
+
+// CountByIds implements ContentRepository.
+func (repository *ContentRepositoryImpl) CountByIds(tx *gorm.DB, ids []uint) (int64, error) {
+	var total int64
+	err := tx.Model(&entity.Content{}).Where("id IN ?", ids).Count(&total).Error
+	return total, err
+}
+
+// UpdatePositions implements ContentRepository. A content that moves gets a
+// new version, so an edit made against its old position is turned down.
+func (repository *ContentRepositoryImpl) UpdatePositions(tx *gorm.DB, ids []uint) error {
+	for index, id := range ids {
+		// UpdateColumns keeps updated_at, reordering is not an edit of the listing
+		err := tx.Model(&entity.Content{}).
+			Where("id = ? AND position <> ?", id, index+1).
+			UpdateColumns(map[string]any{
+				"position": index + 1,
+				"version":  gorm.Expr("version + 1"),
+			}).Error
+		if err != nil {
+			return err
 		}
-		return tx.Joins("Admin").
-			Order("contents.created_at ASC").
-			Find(contents).Error
 	}
 
+	return nil
+}
+
+// IncrementViewCount implements ContentRepository.
+func (repository *ContentRepositoryImpl) IncrementViewCount(tx *gorm.DB, contentId uint, views uint64) error {
+	return tx.Model(&entity.Content{}).
+		Where("id = ?", contentId).
+		UpdateColumn("view_count", gorm.Expr("view_count + ?", views)).Error
+}
+
+// FindPopular implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindPopular(tx *gorm.DB, since *time.Time, category string, contents *[]entity.Content) error {
+	query := tx.Joins("Admin").
+		Preload("Prices").
+		Preload("Tags").
+		Preload("Contacts", orderContactsByPosition).
+		Preload("Translations").
+		Limit(10)
+
 	if category != "" {
-		return tx.Joins("Admin").
-			Order("contents.created_at DESC").
-			Where("contents.category = ?", category).
-			Find(contents).Error
+		query = query.Where("contents.category = ?", category)
+	}
+
+	if since == nil {
+		return query.Order("contents.view_count DESC").Find(contents).Error
+	}
+
+	periodViews := tx.Session(&gorm.Session{NewDB: true}).
+		Table("daily_views").
+		Select("item_id, SUM(views) AS period_views").
+		Where("item_type = ? AND view_date >= ?", "content", since.Format("2006-01-02")).
+		Group("item_id")
+
+	return query.
+		Joins("JOIN (?) AS popular ON popular.item_id = contents.id", periodViews).
+		Order("popular.period_views DESC").
+		Find(contents).Error
+}
+
+// FindRelatedCandidates implements ContentRepository. Only listings sharing
+// the category or a tag with content, or lying within about radiusKm of it,
+// can score, so no others are read. The candidates come with the columns
+// and tags the scoring needs, those sharing the most first.
+func (repository *ContentRepositoryImpl) FindRelatedCandidates(tx *gorm.DB, content *entity.Content, radiusKm float64, limit int, contents *[]entity.Content) error {
+	tags := make([]string, 0, len(content.Tags))
+	for _, tag := range content.Tags {
+		tags = append(tags, tag.Tag)
+	}
+
+	related := tx.Where("contents.category = ?", content.Category)
+	ranking := clause.Expr{SQL: "contents.category = ? DESC, contents.updated_at DESC", Vars: []any{content.Category}, WithoutParentheses: true}
+
+	if len(tags) > 0 {
+		related = related.Or("contents.id IN (?)", tx.Model(&entity.ContentTag{}).Select("content_id").Where("tag IN ?", tags))
+		ranking = clause.Expr{
+			SQL:                "(SELECT COUNT(*) FROM content_tags WHERE content_tags.content_id = contents.id AND content_tags.tag IN (?)) DESC, " + ranking.SQL,
+			Vars:               append([]any{tags}, ranking.Vars...),
+			WithoutParentheses: true,
+		}
+	}
+
+	if content.Latitude != nil && content.Longitude != nil {
+		// a box around the listing, a degree of latitude is about 111 km
+		latitude, longitude := *content.Latitude, *content.Longitude
+		deltaLatitude := radiusKm / 111
+		deltaLongitude := radiusKm / (111 * math.Max(math.Cos(latitude*math.Pi/180), 0.01))
+
+		related = related.Or("contents.latitude BETWEEN ? AND ? AND contents.longitude BETWEEN ? AND ?",
+			latitude-deltaLatitude, latitude+deltaLatitude, longitude-deltaLongitude, longitude+deltaLongitude)
 	}
 
+	return tx.Select("contents.id", "contents.title", "contents.content", "contents.content_format",
+		"contents.category", "contents.latitude", "contents.longitude", "contents.updated_at").
+		Preload("Tags").
+		Where("contents.id <> ?", content.ID).
+		Where(related).
+		Order(clause.OrderBy{Expression: ranking}).
+		Limit(limit).
+		Find(contents).Error
+}
+
+// FindByIds implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindByIds(tx *gorm.DB, ids []uint, contents *[]entity.Content) error {
 	return tx.Joins("Admin").
-		Order("contents.created_at DESC").
+		Preload("Prices").
+		Preload("Tags").
+		Preload("Contacts", orderContactsByPosition).
+		Preload("Translations").
+		Where("contents.id IN ?", ids).
 		Find(contents).Error
 }
 
-// FindWithLimit implements ContentRepository.
-func (repository *ContentRepositoryImpl) FindWithLimit(tx *gorm.DB, order string, category string, contents *[]entity.Content) error {
-	if order == "ASC" {
-		if category != "" {
-			return tx.Joins("Admin").
-				Order("contents.created_at ASC").
-				Where("contents.category = ?", category).
-				Find(contents).
-				Limit(8).Error
+// StreamExport implements ContentRepository.
+func (repository *ContentRepositoryImpl) StreamExport(tx *gorm.DB, request *model.ContentFilterRequest, fn func(row *model.ContentExportRow) error) error {
+	rows, err := tx.Model(&entity.Content{}).
+		Select("contents.*, admins.name AS created_by_name, " +
+			"(SELECT GROUP_CONCAT(content_tags.tag ORDER BY content_tags.tag SEPARATOR ',') FROM content_tags WHERE content_tags.content_id = contents.id) AS tags").
+		Joins("LEFT JOIN admins ON admins.id = contents.created_by").
+		Scopes(repository.filterContents(request)).
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := new(model.ContentExportRow)
+		if err := tx.ScanRows(rows, row); err != nil {
+			return err
+		}
+
+		if err := fn(row); err != nil {
+			return err
 		}
-		return tx.Joins("Admin").
-			Order("contents.created_at ASC").
-			Find(contents).
-			Limit(8).Error
 	}
 
+	return rows.Err()
+}
+
+// FindSitemapEntries implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindSitemapEntries(tx *gorm.DB, entries *[]model.SitemapEntry) error {
+	return tx.Model(&entity.Content{}).
+		Select("contents.id, contents.category, contents.updated_at, " +
+			"(SELECT GROUP_CONCAT(content_translations.locale ORDER BY content_translations.locale SEPARATOR ',') FROM content_translations WHERE content_translations.content_id = contents.id) AS locales").
+		Order("contents.id ASC").
+		Scan(entries).Error
+}
+
+// FindLatest implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindLatest(tx *gorm.DB, category string, limit int, contents *[]entity.Content) error {
 	if category != "" {
-		return tx.Joins("Admin").
-			Order("contents.created_at DESC").
-			Where("contents.category = ?", category).
-			Find(contents).
-			Limit(8).Error
+		tx = tx.Where("contents.category = ?", category)
 	}
 
 	return tx.Joins("Admin").
+		Preload("Translations").
 		Order("contents.created_at DESC").
-		Find(contents).
-		Limit(8).Error
+		Order("contents.id DESC").
+		Limit(limit).
+		Find(contents).Error
 }
 
-// FindById implements ContentRepository.
-func (repository *ContentRepositoryImpl) FindById(tx *gorm.DB, content *entity.Content) error {
-	return tx.Joins("Admin").First(content).Error
+// FindCreatedSince implements ContentRepository.
+func (repository *ContentRepositoryImpl) FindCreatedSince(tx *gorm.DB, categories []string, since time.Time, limit int, contents *[]entity.Content) error {
+	return tx.Joins("Admin").
+		Preload("Translations").
+		Where("contents.category IN ?", categories).
+		Where("contents.created_at > ?", since).
+		Order("contents.created_at ASC").
+		Order("contents.id ASC").
+		Limit(limit).
+		Find(contents).Error
+}
+
+// SitemapState implements ContentRepository.
+func (repository *ContentRepositoryImpl) SitemapState(tx *gorm.DB, state *model.SitemapState) error {
+	return tx.Model(&entity.Content{}).
+		Select("COUNT(*) AS total, MAX(contents.updated_at) AS last_modified, " +
+			"(SELECT COUNT(*) FROM content_translations) AS translations, " +
+			"(SELECT MAX(content_translations.updated_at) FROM content_translations) AS last_translated").
+		Scan(state).Error
+}
+
+func (repository *ContentRepositoryImpl) filterContents(request *model.ContentFilterRequest) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if request.Category != "" {
+			tx = tx.Where("contents.category = ?", request.Category)
+		}
+
+		if request.MinPrice != nil {
+			tx = tx.Where("contents.max_price >= ?", *request.MinPrice)
+		}
+
+		if request.MaxPrice != nil {
+			tx = tx.Where("contents.min_price <= ?", *request.MaxPrice)
+		}
+
+		direction := "DESC"
+		if request.Order == "ASC" {
+			direction = "ASC"
+		}
+
+		switch request.Sort {
+		case "featured":
+			return tx.Order("contents.is_featured DESC").Order("contents.position ASC").Order("contents.created_at DESC")
+		case "position":
+			return tx.Order("contents.position ASC").Order("contents.created_at DESC")
+		case "newest":
+			return tx.Order("contents.created_at DESC")
+		case "oldest":
+			return tx.Order("contents.created_at ASC")
+		case "title":
+			return tx.Order("contents.title ASC")
+		case "rating":
+			return tx.Order("contents.rating_average " + direction).Order("contents.review_count " + direction)
+		case "price":
+			// contents without any price always go last
+			return tx.Order("contents.min_price IS NULL").Order("contents.min_price " + direction)
+		default:
+			return tx.Order("contents.created_at " + direction)
+		}
+	}
+}
+
+func orderContactsByPosition(tx *gorm.DB) *gorm.DB {
+	return tx.Order("content_contacts.position ASC")
 }
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ContentTagRepository interface {
+	DeleteByContentId(tx *gorm.DB, contentId uint) error
+}
+
+type ContentTagRepositoryImpl struct {
+	Repository[entity.ContentTag]
+}
+
+func NewContentTagRepository() ContentTagRepository {
+	return &ContentTagRepositoryImpl{}
+}
+
+// DeleteByContentId implements ContentTagRepository.
+func (repository *ContentTagRepositoryImpl) DeleteByContentId(tx *gorm.DB, contentId uint) error {
+	return tx.Where("content_id = ?", contentId).Delete(&entity.ContentTag{}).Error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ContentTranslationRepository interface {
+	Create(tx *gorm.DB, translation *entity.ContentTranslation) error
+	Update(tx *gorm.DB, translation *entity.ContentTranslation) error
+	Delete(tx *gorm.DB, translation *entity.ContentTranslation) error
+	FindByLocale(tx *gorm.DB, translation *entity.ContentTranslation) error
+	FindAllByContentId(tx *gorm.DB, contentId uint, translations *[]entity.ContentTranslation) error
+}
+
+type ContentTranslationRepositoryImpl struct {
+	Repository[entity.ContentTranslation]
+}
+
+func NewContentTranslationRepository() ContentTranslationRepository {
+	return &ContentTranslationRepositoryImpl{}
+}
+
+// FindByLocale implements ContentTranslationRepository.
+func (repository *ContentTranslationRepositoryImpl) FindByLocale(tx *gorm.DB, translation *entity.ContentTranslation) error {
+	return tx.First(translation, "content_id = ? AND locale = ?", translation.ContentID, translation.Locale).Error
+}
+
+// FindAllByContentId implements ContentTranslationRepository.
+func (repository *ContentTranslationRepositoryImpl) FindAllByContentId(tx *gorm.DB, contentId uint, translations *[]entity.ContentTranslation) error {
+	return tx.Where("content_id = ?", contentId).Order("locale ASC").Find(translations).Error
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type EventRepository interface {
+	Create(tx *gorm.DB, event *entity.Event) error
+	Update(tx *gorm.DB, event *entity.Event) error
+	Delete(tx *gorm.DB, event *entity.Event) error
+	LockVersion(tx *gorm.DB, id uint) (uint, error)
+	FindById(tx *gorm.DB, event *entity.Event) error
+	FindAll(tx *gorm.DB, events *[]entity.Event) error
+	FindBetween(tx *gorm.DB, from time.Time, to time.Time, events *[]entity.Event) error
+	FindCreatedSince(tx *gorm.DB, since time.Time, limit int, events *[]entity.Event) error
+}
+
+type EventRepositoryImpl struct {
+	Repository[entity.Event]
+}
+
+func NewEventRepository() EventRepository {
+	return &EventRepositoryImpl{}
+}
+
+// FindById implements EventRepository.
+func (repository *EventRepositoryImpl) FindById(tx *gorm.DB, event *entity.Event) error {
+	return tx.Joins("Admin").Joins("Content").First(event).Error
+}
+
+// FindAll implements EventRepository.
+func (repository *EventRepositoryImpl) FindAll(tx *gorm.DB, events *[]entity.Event) error {
+	return tx.Joins("Admin").
+		Joins("Content").
+		Order("events.starts_at DESC").
+		Find(events).Error
+}
+
+// FindBetween implements EventRepository. It loads every event with an
+// occurrence that may overlap [from, to); recurring ones still have to be
+// expanded to know which occurrences do.
+func (repository *EventRepositoryImpl) FindBetween(tx *gorm.DB, from time.Time, to time.Time, events *[]entity.Event) error {
+	return tx.Joins("Admin").
+		Joins("Content").
+		Where("events.starts_at < ?", to).
+		Where("events.series_ends_at IS NULL OR events.series_ends_at > ?", from).
+		Order("events.starts_at ASC").
+		Find(events).Error
+}
+
+// FindCreatedSince implements EventRepository.
+func (repository *EventRepositoryImpl) FindCreatedSince(tx *gorm.DB, since time.Time, limit int, events *[]entity.Event) error {
+	return tx.Joins("Admin").
+		Joins("Content").
+		Where("events.created_at > ?", since).
+		Order("events.created_at ASC").
+		Order("events.id ASC").
+		Limit(limit).
+		Find(events).Error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ItemEditorRepository interface {
+	Create(tx *gorm.DB, editor *entity.ItemEditor) error
+	FindByItem(tx *gorm.DB, itemType string, itemId uint, editors *[]entity.ItemEditor) error
+	IsEditor(tx *gorm.DB, itemType string, itemId uint, adminId uint) (bool, error)
+	DeleteEditor(tx *gorm.DB, itemType string, itemId uint, adminId uint) (bool, error)
+	DeleteByItem(tx *gorm.DB, itemType string, itemId uint) error
+}
+
+type ItemEditorRepositoryImpl struct {
+	Repository[entity.ItemEditor]
+}
+
+func NewItemEditorRepository() ItemEditorRepository {
+	return &ItemEditorRepositoryImpl{}
+}
+
+// FindByItem implements ItemEditorRepository.
+func (repository *ItemEditorRepositoryImpl) FindByItem(tx *gorm.DB, itemType string, itemId uint, editors *[]entity.ItemEditor) error {
+	return tx.Joins("Admin").
+		Where("item_editors.item_type = ? AND item_editors.item_id = ?", itemType, itemId).
+		Order("item_editors.created_at ASC").
+		Find(editors).Error
+}
+
+// IsEditor implements ItemEditorRepository.
+func (repository *ItemEditorRepositoryImpl) IsEditor(tx *gorm.DB, itemType string, itemId uint, adminId uint) (bool, error) {
+	var total int64
+	err := tx.Model(&entity.ItemEditor{}).
+		Where("item_type = ? AND item_id = ? AND admin_id = ?", itemType, itemId, adminId).
+		Count(&total).Error
+	return total > 0, err
+}
+
+// DeleteEditor implements ItemEditorRepository.
+func (repository *ItemEditorRepositoryImpl) DeleteEditor(tx *gorm.DB, itemType string, itemId uint, adminId uint) (bool, error) {
+	result := tx.Where("item_type = ? AND item_id = ? AND admin_id = ?", itemType, itemId, adminId).
+		Delete(&entity.ItemEditor{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByItem implements ItemEditorRepository.
+func (repository *ItemEditorRepositoryImpl) DeleteByItem(tx *gorm.DB, itemType string, itemId uint) error {
+	return tx.Where("item_type = ? AND item_id = ?", itemType, itemId).Delete(&entity.ItemEditor{}).Error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type MessageNoteRepository interface {
+	Create(tx *gorm.DB, note *entity.MessageNote) error
+	FindById(tx *gorm.DB, note *entity.MessageNote) error
+}
+
+type MessageNoteRepositoryImpl struct {
+	Repository[entity.MessageNote]
+}
+
+func NewMessageNoteRepository() MessageNoteRepository {
+	return &MessageNoteRepositoryImpl{}
+}
+
+// FindById implements MessageNoteRepository.
+func (repository *MessageNoteRepositoryImpl) FindById(tx *gorm.DB, note *entity.MessageNote) error {
+	return tx.Joins("Admin").First(note).Error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type MessageReplyRepository interface {
+	Create(tx *gorm.DB, reply *entity.MessageReply) error
+}
+
+type MessageReplyRepositoryImpl struct {
+	Repository[entity.MessageReply]
+}
+
+func NewMessageReplyRepository() MessageReplyRepository {
+	return &MessageReplyRepositoryImpl{}
+}
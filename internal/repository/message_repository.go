@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"gorm.io/gorm"
+)
+
+type MessageRepository interface {
+	Create(tx *gorm.DB, message *entity.Message) error
+	Delete(tx *gorm.DB, message *entity.Message) error
+	Patch(tx *gorm.DB, message *entity.Message, columns []string) error
+	FindById(tx *gorm.DB, message *entity.Message) error
+	FindAll(tx *gorm.DB, request *model.MessageFilterRequest, messages *[]entity.Message) error
+	CountSince(tx *gorm.DB, ipAddress string, since time.Time) (int64, error)
+}
+
+type MessageRepositoryImpl struct {
+	Repository[entity.Message]
+}
+
+func NewMessageRepository() MessageRepository {
+	return &MessageRepositoryImpl{}
+}
+
+// FindById implements MessageRepository.
+func (repository *MessageRepositoryImpl) FindById(tx *gorm.DB, message *entity.Message) error {
+	return tx.Joins("Assignee").
+		Preload("Notes", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("message_notes.created_at ASC").Order("message_notes.id ASC")
+		}).
+		Preload("Notes.Admin").
+		Preload("Replies", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("message_replies.sent_at ASC")
+		}).
+		Preload("Replies.Admin").
+		First(message).Error
+}
+
+// FindAll implements MessageRepository.
+func (repository *MessageRepositoryImpl) FindAll(tx *gorm.DB, request *model.MessageFilterRequest, messages *[]entity.Message) error {
+	switch request.Folder {
+	case "archived":
+		tx = tx.Where("messages.is_spam = ? AND messages.archived_at IS NOT NULL", false)
+	case "spam":
+		tx = tx.Where("messages.is_spam = ?", true)
+	case "all":
+	default:
+		tx = tx.Where("messages.is_spam = ? AND messages.archived_at IS NULL", false)
+	}
+
+	switch request.Status {
+	case "read":
+		tx = tx.Where("messages.read_at IS NOT NULL")
+	case "unread":
+		tx = tx.Where("messages.read_at IS NULL")
+	}
+
+	switch request.AssignedTo {
+	case "":
+	case "me":
+		tx = tx.Where("messages.assigned_to = ?", request.ActorID)
+	case "none":
+		tx = tx.Where("messages.assigned_to IS NULL")
+	default:
+		adminId, _ := strconv.ParseUint(request.AssignedTo, 10, 32)
+		tx = tx.Where("messages.assigned_to = ?", adminId)
+	}
+
+	return tx.Joins("Assignee").
+		Order("messages.created_at DESC").
+		Find(messages).Error
+}
+
+// CountSince implements MessageRepository.
+func (repository *MessageRepositoryImpl) CountSince(tx *gorm.DB, ipAddress string, since time.Time) (int64, error) {
+	var total int64
+	err := tx.Model(&entity.Message{}).
+		Where("ip_address = ? AND created_at >= ?", ipAddress, since).
+		Count(&total).Error
+	return total, err
+}
@@ -1,6 +1,11 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type Repository[T any] struct {
 	DB *gorm.DB
@@ -17,3 +22,63 @@ func (r Repository[T]) Update(db *gorm.DB, entity *T) error {
 func (r Repository[T]) Delete(db *gorm.DB, entity *T) error {
 	return db.Delete(entity).Error
 }
+
+// Patch writes only the given columns, zero values included.
+func (r Repository[T]) Patch(db *gorm.DB, entity *T, columns []string) error {
+	return db.Model(entity).Select(columns).Updates(entity).Error
+}
+
+// LockVersion locks the row until the transaction ends and returns its
+// version, or gorm.ErrRecordNotFound when the row does not exist.
+func (r Repository[T]) LockVersion(db *gorm.DB, id uint) (uint, error) {
+	var versions []uint
+
+	err := db.Model(new(T)).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Pluck("version", &versions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if len(versions) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+
+	return versions[0], nil
+}
+
+// FindDeleted loads the soft deleted rows, the most recently deleted first.
+// With before set only rows deleted earlier than it are returned.
+func (r Repository[T]) FindDeleted(db *gorm.DB, before *time.Time, entities *[]T) error {
+	query := db.Unscoped().Where("deleted_at IS NOT NULL")
+
+	if before != nil {
+		query = query.Where("deleted_at < ?", *before)
+	}
+
+	return query.Order("deleted_at DESC").Find(entities).Error
+}
+
+// FindDeletedById loads a soft deleted row by its primary key.
+func (r Repository[T]) FindDeletedById(db *gorm.DB, entity *T) error {
+	return db.Unscoped().Where("deleted_at IS NOT NULL").First(entity).Error
+}
+
+// Restore clears deleted_at, leaving updated_at as it was.
+func (r Repository[T]) Restore(db *gorm.DB, entity *T) error {
+	return db.Unscoped().Model(entity).UpdateColumn("deleted_at", nil).Error
+}
+
+// Purge removes the row for good, soft deleted or not.
+func (r Repository[T]) Purge(db *gorm.DB, entity *T) error {
+	return db.Unscoped().Delete(entity).Error
+}
+
+// CountByImage counts the rows, soft deleted ones included, that still use
+// an uploaded image.
+func (r Repository[T]) CountByImage(db *gorm.DB, image string) (int64, error) {
+	var total int64
+	err := db.Unscoped().Model(new(T)).Where("image = ?", image).Count(&total).Error
+	return total, err
+}
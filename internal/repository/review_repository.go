@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type ReviewRepository interface {
+	Create(tx *gorm.DB, review *entity.Review) error
+	Update(tx *gorm.DB, review *entity.Review) error
+	Delete(tx *gorm.DB, review *entity.Review) error
+	FindById(tx *gorm.DB, review *entity.Review) error
+	FindAll(tx *gorm.DB, status string, reviews *[]entity.Review) error
+	FindApprovedByContentId(tx *gorm.DB, contentId uint, reviews *[]entity.Review) error
+	UpdateStatus(tx *gorm.DB, review *entity.Review) error
+}
+
+type ReviewRepositoryImpl struct {
+	Repository[entity.Review]
+}
+
+func NewReviewRepository() ReviewRepository {
+	return &ReviewRepositoryImpl{}
+}
+
+// FindById implements ReviewRepository.
+func (repository *ReviewRepositoryImpl) FindById(tx *gorm.DB, review *entity.Review) error {
+	return tx.Joins("Content").First(review).Error
+}
+
+// FindAll implements ReviewRepository.
+func (repository *ReviewRepositoryImpl) FindAll(tx *gorm.DB, status string, reviews *[]entity.Review) error {
+	if status != "" {
+		return tx.Joins("Content").
+			Where("reviews.status = ?", status).
+			Order("reviews.created_at ASC").
+			Find(reviews).Error
+	}
+
+	return tx.Joins("Content").
+		Order("reviews.created_at DESC").
+		Find(reviews).Error
+}
+
+// FindApprovedByContentId implements ReviewRepository.
+func (repository *ReviewRepositoryImpl) FindApprovedByContentId(tx *gorm.DB, contentId uint, reviews *[]entity.Review) error {
+	return tx.Where("content_id = ? AND status = ?", contentId, "approved").
+		Order("created_at DESC").
+		Find(reviews).Error
+}
+
+// UpdateStatus implements ReviewRepository.
+func (repository *ReviewRepositoryImpl) UpdateStatus(tx *gorm.DB, review *entity.Review) error {
+	return tx.Model(review).Select("status", "moderated_by", "moderated_at").Updates(review).Error
+}
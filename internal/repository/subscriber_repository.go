@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type SubscriberRepository interface {
+	Create(tx *gorm.DB, subscriber *entity.Subscriber) error
+	Delete(tx *gorm.DB, subscriber *entity.Subscriber) error
+	Patch(tx *gorm.DB, subscriber *entity.Subscriber, columns []string) error
+	FindById(tx *gorm.DB, subscriber *entity.Subscriber) error
+	FindByEmail(tx *gorm.DB, subscriber *entity.Subscriber) error
+	FindByToken(tx *gorm.DB, subscriber *entity.Subscriber) error
+	FindByConfirmToken(tx *gorm.DB, subscriber *entity.Subscriber, now time.Time) error
+	FindAll(tx *gorm.DB, status string, subscribers *[]entity.Subscriber) error
+	FindConfirmed(tx *gorm.DB, subscribers *[]entity.Subscriber) error
+}
+
+type SubscriberRepositoryImpl struct {
+	Repository[entity.Subscriber]
+}
+
+func NewSubscriberRepository() SubscriberRepository {
+	return &SubscriberRepositoryImpl{}
+}
+
+// FindById implements SubscriberRepository.
+func (repository *SubscriberRepositoryImpl) FindById(tx *gorm.DB, subscriber *entity.Subscriber) error {
+	return tx.First(subscriber).Error
+}
+
+// FindByEmail implements SubscriberRepository.
+func (repository *SubscriberRepositoryImpl) FindByEmail(tx *gorm.DB, subscriber *entity.Subscriber) error {
+	return tx.Where("email = ?", subscriber.Email).First(subscriber).Error
+}
+
+// FindByToken implements SubscriberRepository.
+func (repository *SubscriberRepositoryImpl) FindByToken(tx *gorm.DB, subscriber *entity.Subscriber) error {
+	return tx.Where("token = ?", subscriber.Token).First(subscriber).Error
+}
+
+// FindByConfirmToken implements SubscriberRepository. Tokens past their
+// expiry are not found.
+func (repository *SubscriberRepositoryImpl) FindByConfirmToken(tx *gorm.DB, subscriber *entity.Subscriber, now time.Time) error {
+	return tx.Where("confirm_token = ? AND confirm_expires_at > ?", subscriber.ConfirmToken, now).
+		First(subscriber).Error
+}
+
+// FindAll implements SubscriberRepository.
+func (repository *SubscriberRepositoryImpl) FindAll(tx *gorm.DB, status string, subscribers *[]entity.Subscriber) error {
+	switch status {
+	case "confirmed":
+		tx = tx.Where("confirmed_at IS NOT NULL")
+	case "pending":
+		tx = tx.Where("confirmed_at IS NULL")
+	}
+
+	return tx.Order("created_at DESC").Find(subscribers).Error
+}
+
+// FindConfirmed implements SubscriberRepository.
+func (repository *SubscriberRepositoryImpl) FindConfirmed(tx *gorm.DB, subscribers *[]entity.Subscriber) error {
+	return tx.Where("confirmed_at IS NOT NULL").Order("id ASC").Find(subscribers).Error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ViewRepository interface {
+	AddDailyViews(tx *gorm.DB, views *[]entity.DailyView) error
+}
+
+type ViewRepositoryImpl struct {
+	Repository[entity.DailyView]
+}
+
+func NewViewRepository() ViewRepository {
+	return &ViewRepositoryImpl{}
+}
+
+// AddDailyViews implements ViewRepository.
+func (repository *ViewRepositoryImpl) AddDailyViews(tx *gorm.DB, views *[]entity.DailyView) error {
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{"views": gorm.Expr("views + VALUES(views)")}),
+	}).CreateInBatches(views, 500).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryRepository interface {
+	Create(tx *gorm.DB, delivery *entity.WebhookDelivery) error
+	Patch(tx *gorm.DB, delivery *entity.WebhookDelivery, columns []string) error
+	FindById(tx *gorm.DB, delivery *entity.WebhookDelivery) error
+	FindByWebhook(tx *gorm.DB, webhookId uint, status string, limit int, deliveries *[]entity.WebhookDelivery) error
+	FindDue(tx *gorm.DB, now time.Time, limit int, deliveries *[]entity.WebhookDelivery) error
+}
+
+type WebhookDeliveryRepositoryImpl struct {
+	Repository[entity.WebhookDelivery]
+}
+
+func NewWebhookDeliveryRepository() WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryImpl{}
+}
+
+// FindById implements WebhookDeliveryRepository.
+func (repository *WebhookDeliveryRepositoryImpl) FindById(tx *gorm.DB, delivery *entity.WebhookDelivery) error {
+	return tx.Joins("Webhook").First(delivery).Error
+}
+
+// FindByWebhook implements WebhookDeliveryRepository.
+func (repository *WebhookDeliveryRepositoryImpl) FindByWebhook(tx *gorm.DB, webhookId uint, status string, limit int, deliveries *[]entity.WebhookDelivery) error {
+	tx = tx.Where("webhook_id = ?", webhookId)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	return tx.Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(deliveries).Error
+}
+
+// FindDue implements WebhookDeliveryRepository. Deliveries of disabled
+// webhooks wait until the webhook is enabled again.
+func (repository *WebhookDeliveryRepositoryImpl) FindDue(tx *gorm.DB, now time.Time, limit int, deliveries *[]entity.WebhookDelivery) error {
+	return tx.Joins("Webhook").
+		Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", "pending", now).
+		Where("Webhook.active = ?", true).
+		Order("webhook_deliveries.next_attempt_at ASC").
+		Order("webhook_deliveries.id ASC").
+		Limit(limit).
+		Find(deliveries).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"gorm.io/gorm"
+)
+
+type WebhookRepository interface {
+	Create(tx *gorm.DB, webhook *entity.Webhook) error
+	Delete(tx *gorm.DB, webhook *entity.Webhook) error
+	Patch(tx *gorm.DB, webhook *entity.Webhook, columns []string) error
+	FindById(tx *gorm.DB, webhook *entity.Webhook) error
+	FindAll(tx *gorm.DB, webhooks *[]entity.Webhook) error
+	FindActiveByEvent(tx *gorm.DB, event string, webhooks *[]entity.Webhook) error
+	RecordSuccess(tx *gorm.DB, id uint) error
+	RecordFailure(tx *gorm.DB, id uint, disableAfter uint, now time.Time) error
+}
+
+type WebhookRepositoryImpl struct {
+	Repository[entity.Webhook]
+}
+
+func NewWebhookRepository() WebhookRepository {
+	return &WebhookRepositoryImpl{}
+}
+
+// FindById implements WebhookRepository.
+func (repository *WebhookRepositoryImpl) FindById(tx *gorm.DB, webhook *entity.Webhook) error {
+	return tx.Joins("Admin").First(webhook).Error
+}
+
+// FindAll implements WebhookRepository.
+func (repository *WebhookRepositoryImpl) FindAll(tx *gorm.DB, webhooks *[]entity.Webhook) error {
+	return tx.Joins("Admin").Order("webhooks.id ASC").Find(webhooks).Error
+}
+
+// FindActiveByEvent implements WebhookRepository.
+func (repository *WebhookRepositoryImpl) FindActiveByEvent(tx *gorm.DB, event string, webhooks *[]entity.Webhook) error {
+	return tx.Where("active = ? AND FIND_IN_SET(?, events) > 0", true, event).Find(webhooks).Error
+}
+
+// RecordSuccess implements WebhookRepository.
+func (repository *WebhookRepositoryImpl) RecordSuccess(tx *gorm.DB, id uint) error {
+	return tx.Model(&entity.Webhook{}).
+		Where("id = ? AND failure_count > 0", id).
+		UpdateColumn("failure_count", 0).Error
+}
+
+// RecordFailure implements WebhookRepository. It counts a failed delivery and
+// disables the webhook once disableAfter of them failed in a row. MySQL
+// assigns from left to right, so disabled_at and active are set from the
+// counts before the increment.
+func (repository *WebhookRepositoryImpl) RecordFailure(tx *gorm.DB, id uint, disableAfter uint, now time.Time) error {
+	return tx.Exec(`UPDATE webhooks SET
+		disabled_at = IF(active AND failure_count + 1 >= ?, ?, disabled_at),
+		active = IF(failure_count + 1 >= ?, FALSE, active),
+		failure_count = failure_count + 1
+		WHERE id = ?`, disableAfter, now, disableAfter, id).Error
+}
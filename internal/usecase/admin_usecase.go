@@ -26,7 +26,8 @@ import (
 type AdminUsecase interface {
 	Create(ctx context.Context, request *model.AdminCreateRequest) (*model.AdminResponse, error)
 	Update(ctx context.Context, request *model.AdminUpdateRequest) (*model.AdminResponse, error)
-	Delete(ctx context.Context, adminId uint) error
+	Patch(ctx context.Context, request *model.PatchRequest) (*model.AdminResponse, error)
+	Delete(ctx context.Context, adminId uint, version uint, actorId uint) error
 	FindAll(ctx context.Context, adminId uint) (*[]model.AdminResponse, error)
 	FindByUsername(ctx context.Context, usernameRequest string) (*model.AdminResponse, error)
 	Login(ctx context.Context, request *model.LoginRequest, requestRefreshToken string) (*model.LoginResponse, string, error)
@@ -216,10 +217,21 @@ func (adminUsecase *AdminUsecaseImpl) Create(ctx context.Context, request *model
 		return nil, fiber.ErrInternalServerError
 	}
 
+	if request.Role == "" {
+		request.Role = AdminRoleEditor
+	}
+
+	// new accounts of any role are handed out by admins only
+	if err := adminUsecase.checkRoleChange(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
 	admin := &entity.Admin{
 		Name:     request.Name,
 		Username: request.Username,
 		Password: string(password),
+		Role:     request.Role,
+		Version:  1,
 	}
 
 	if err := adminUsecase.AdminRepo.FindByUsername(tx, admin); err == nil {
@@ -248,7 +260,7 @@ func (adminUsecase *AdminUsecaseImpl) Create(ctx context.Context, request *model
 }
 
 // Delete implements AdminUsecase.
-func (adminUsecase *AdminUsecaseImpl) Delete(ctx context.Context, adminId uint) error {
+func (adminUsecase *AdminUsecaseImpl) Delete(ctx context.Context, adminId uint, version uint, actorId uint) error {
 	tx := adminUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -274,6 +286,21 @@ func (adminUsecase *AdminUsecaseImpl) Delete(ctx context.Context, adminId uint)
 		return fiber.ErrInternalServerError
 	}
 
+	if err := adminUsecase.checkAccountChange(tx, actorId, admin.ID); err != nil {
+		return err
+	}
+
+	currentVersion, err := adminUsecase.AdminRepo.LockVersion(tx, admin.ID)
+	if err != nil {
+		log.Println("error delete admin : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := checkVersion(currentVersion, version); err != nil {
+		log.Println("error delete admin : ", err)
+		return err
+	}
+
 	err = adminUsecase.AdminRepo.Delete(tx, admin)
 	if err != nil {
 		log.Println("failed when delete repo admin : ", err)
@@ -393,6 +420,23 @@ func (adminUsecase *AdminUsecaseImpl) Update(ctx context.Context, request *model
 		return nil, fiber.ErrInternalServerError
 	}
 
+	if err := adminUsecase.checkAccountChange(tx, request.ActorID, admin.ID); err != nil {
+		return nil, err
+	}
+
+	currentVersion, err := adminUsecase.AdminRepo.LockVersion(tx, admin.ID)
+	if err != nil {
+		log.Println("error update admin : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error update admin : ", err)
+		return nil, err
+	}
+
+	admin.Version = currentVersion + 1
+
 	if request.Password != "" {
 		password, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -403,6 +447,14 @@ func (adminUsecase *AdminUsecaseImpl) Update(ctx context.Context, request *model
 		admin.Password = string(password)
 	}
 
+	if request.Role != "" && request.Role != admin.Role {
+		if err := adminUsecase.checkRoleChange(tx, request.ActorID); err != nil {
+			return nil, err
+		}
+
+		admin.Role = request.Role
+	}
+
 	admin.Name = request.Name
 
 	err = adminUsecase.AdminRepo.Update(tx, admin)
@@ -436,3 +488,157 @@ func (adminUsecase *AdminUsecaseImpl) Update(ctx context.Context, request *model
 	log.Println("success update from usecase admin")
 	return converter.AdminToResponse(admin), nil
 }
+
+// Patch implements AdminUsecase.
+func (adminUsecase *AdminUsecaseImpl) Patch(ctx context.Context, request *model.PatchRequest) (*model.AdminResponse, error) {
+	tx := adminUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	errorResponse := &model.ErrorResponse{}
+
+	admin := &entity.Admin{
+		ID: request.ID,
+	}
+
+	currentVersion, err := adminUsecase.AdminRepo.LockVersion(tx, admin.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("error patch admin : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := adminUsecase.AdminRepo.FindById(tx, admin); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorResponse.Message = "Admin data was not found"
+			errorResponse.Details = []string{}
+
+			jsonString, _ := json.Marshal(errorResponse)
+			log.Println("Data not found")
+
+			return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+		}
+
+		log.Println("error find by id : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := adminUsecase.checkAccountChange(tx, request.ActorID, admin.ID); err != nil {
+		return nil, err
+	}
+
+	// the stored hash is never part of the document being patched
+	updateRequest := &model.AdminUpdateRequest{
+		ID: admin.ID,
+		AdminCreateRequest: model.AdminCreateRequest{
+			Name:     admin.Name,
+			Username: admin.Username,
+			Role:     admin.Role,
+		},
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error patch admin : ", err)
+		return nil, err
+	}
+
+	members, err := applyMergePatch(adminUsecase.Validate, updateRequest, request.Patch,
+		func(updateRequest *model.AdminUpdateRequest) {
+			// removing the role falls back to the default one
+			if updateRequest.Role == "" {
+				updateRequest.Role = AdminRoleEditor
+			}
+		},
+	)
+	if err != nil {
+		log.Println("error patch admin : ", err)
+		return nil, err
+	}
+
+	if updateRequest.Role != admin.Role {
+		if err := adminUsecase.checkRoleChange(tx, request.ActorID); err != nil {
+			return nil, err
+		}
+
+		admin.Role = updateRequest.Role
+	}
+
+	admin.Name = updateRequest.Name
+	admin.Username = updateRequest.Username
+
+	if patchesAny(members, "password") {
+		password, err := bcrypt.GenerateFromPassword([]byte(updateRequest.Password), bcrypt.DefaultCost)
+		if err != nil {
+			log.Println("failed to generate password")
+			return nil, fiber.ErrInternalServerError
+		}
+
+		admin.Password = string(password)
+	}
+
+	if len(members) > 0 {
+		admin.Version = currentVersion + 1
+
+		err = adminUsecase.AdminRepo.Patch(tx, admin, append(members, "version"))
+		if err != nil {
+			log.Println("failed when patch repo admin : ", err)
+
+			var mysqlErr *mysql.MySQLError
+			if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+				var errorField string
+				parts := strings.Split(mysqlErr.Message, "'")
+				if len(parts) > 2 {
+					errorField = parts[1]
+				}
+
+				errorResponse.Message = "Duplicate entry"
+				errorResponse.Details = []string{errorField + " already exists in the database."}
+
+				jsonString, _ := json.Marshal(errorResponse)
+
+				return nil, fiber.NewError(fiber.ErrConflict.Code, string(jsonString))
+			}
+
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success patch from usecase admin")
+	return converter.AdminToResponse(admin), nil
+}
+
+// checkRoleChange lets only admins hand out or change roles, so an editor can
+// not grant themselves publish rights.
+func (adminUsecase *AdminUsecaseImpl) checkRoleChange(tx *gorm.DB, actorId uint) error {
+	actor, err := findActor(tx, adminUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role != AdminRoleAdmin {
+		log.Println("error change admin role : actor is not an admin")
+		return forbiddenError("only admins can assign roles")
+	}
+
+	return nil
+}
+
+// checkAccountChange lets admins change or delete any account and everyone
+// else only their own, so an editor can not take over another account by
+// resetting its password or username.
+func (adminUsecase *AdminUsecaseImpl) checkAccountChange(tx *gorm.DB, actorId uint, adminId uint) error {
+	actor, err := findActor(tx, adminUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	if actor.ID != adminId && actor.Role != AdminRoleAdmin {
+		log.Printf("error change admin : admin %d is no admin and not admin %d", actor.ID, adminId)
+		return forbiddenError("only admins can change other accounts")
+	}
+
+	return nil
+}
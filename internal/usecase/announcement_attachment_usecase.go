@@ -0,0 +1,371 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// AttachmentDir is kept apart from the images, so that attachments are
+	// only handed out through the counted download.
+	AttachmentDir = UploadDir + "/attachments"
+	// AttachmentMaxSize bounds a single upload; scanned letters of a few
+	// pages stay well below it.
+	AttachmentMaxSize = 10 << 20
+	attachmentLimit   = 10
+)
+
+type AnnouncementAttachmentUsecase interface {
+	Create(ctx context.Context, request *model.AnnouncementAttachmentCreateRequest) (*model.AnnouncementAttachmentResponse, error)
+	Update(ctx context.Context, request *model.AnnouncementAttachmentUpdateRequest) (*model.AnnouncementAttachmentResponse, error)
+	Delete(ctx context.Context, announcementId uint, attachmentId uint, actorId uint) error
+	Download(ctx context.Context, announcementId uint, attachmentId uint) (*model.AnnouncementAttachmentFile, error)
+}
+
+// AnnouncementAttachmentUsecaseImpl keeps the files attached to
+// announcements. Only publishers may change them, as they go live at once.
+type AnnouncementAttachmentUsecaseImpl struct {
+	AttachmentRepo   repository.AnnouncementAttachmentRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	AdminRepo        repository.AdminRepository
+	DB               *gorm.DB
+	Validate         *validator.Validate
+	AttachmentDir    string
+}
+
+func NewAnnouncementAttachmentUsecase(attachmentRepo repository.AnnouncementAttachmentRepository, announcementRepo repository.AnnouncementRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) AnnouncementAttachmentUsecase {
+	return &AnnouncementAttachmentUsecaseImpl{
+		AttachmentRepo:   attachmentRepo,
+		AnnouncementRepo: announcementRepo,
+		AdminRepo:        adminRepo,
+		DB:               DB,
+		Validate:         validate,
+		AttachmentDir:    AttachmentDir,
+	}
+}
+
+// Create implements AnnouncementAttachmentUsecase.
+func (attachmentUsecase *AnnouncementAttachmentUsecaseImpl) Create(ctx context.Context, request *model.AnnouncementAttachmentCreateRequest) (*model.AnnouncementAttachmentResponse, error) {
+	if err := attachmentUsecase.Validate.Struct(request); err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag()))
+		}
+
+		log.Println("error create announcement attachment : ", err)
+		return nil, invalidAnnouncementError(validationErrors...)
+	}
+
+	if request.Size > AttachmentMaxSize {
+		log.Printf("error create announcement attachment : %d bytes is too large", request.Size)
+		return nil, attachmentTooLargeError()
+	}
+
+	mimeType, extension, err := util.SniffAttachment(request.File, request.Size)
+	if err != nil {
+		log.Println("error create announcement attachment : ", err)
+		if errors.Is(err, util.ErrUnsupportedAttachment) {
+			return nil, unsupportedAttachmentError(err)
+		}
+		return nil, fiber.ErrInternalServerError
+	}
+
+	tx := attachmentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := attachmentUsecase.findPublisher(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	if err := attachmentUsecase.AnnouncementRepo.FindById(tx, &entity.Announcement{ID: request.AnnouncementID}); err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	total, err := attachmentUsecase.AttachmentRepo.CountByAnnouncement(tx, request.AnnouncementID)
+	if err != nil {
+		log.Println("failed when count repo announcement attachment : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if total >= attachmentLimit {
+		return nil, invalidAnnouncementError(fmt.Sprintf("an announcement can have at most %d attachments", attachmentLimit))
+	}
+
+	filename := attachmentFilename(request.Filename, extension)
+
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		name = strings.TrimSuffix(filename, filepath.Ext(filename))
+	}
+
+	attachment := &entity.AnnouncementAttachment{
+		AnnouncementID: request.AnnouncementID,
+		Name:           name,
+		Filename:       filename,
+		StoredName:     util.GenerateRandomFilename(filename),
+		MimeType:       mimeType,
+		Size:           request.Size,
+	}
+
+	if err := attachmentUsecase.store(attachment.StoredName, request.File, request.Size); err != nil {
+		log.Println("failed to store announcement attachment : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := attachmentUsecase.AttachmentRepo.Create(tx, attachment); err != nil {
+		log.Println("failed when create repo announcement attachment : ", err)
+		removeFiles(attachmentUsecase.AttachmentDir, []string{attachment.StoredName})
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		removeFiles(attachmentUsecase.AttachmentDir, []string{attachment.StoredName})
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success create from usecase announcement attachment")
+	return converter.AnnouncementAttachmentToResponse(attachment), nil
+}
+
+// Update implements AnnouncementAttachmentUsecase.
+func (attachmentUsecase *AnnouncementAttachmentUsecaseImpl) Update(ctx context.Context, request *model.AnnouncementAttachmentUpdateRequest) (*model.AnnouncementAttachmentResponse, error) {
+	tx := attachmentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	request.Name = strings.TrimSpace(request.Name)
+
+	if err := attachmentUsecase.Validate.Struct(request); err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag()))
+		}
+
+		log.Println("error update announcement attachment : ", err)
+		return nil, invalidAnnouncementError(validationErrors...)
+	}
+
+	if _, err := attachmentUsecase.findPublisher(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	attachment := &entity.AnnouncementAttachment{
+		ID:             request.ID,
+		AnnouncementID: request.AnnouncementID,
+	}
+
+	if err := attachmentUsecase.AttachmentRepo.FindById(tx, attachment); err != nil {
+		return nil, attachmentNotFoundError(err)
+	}
+
+	attachment.Name = request.Name
+
+	if err := attachmentUsecase.AttachmentRepo.Update(tx, attachment); err != nil {
+		log.Println("failed when update repo announcement attachment : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success update from usecase announcement attachment")
+	return converter.AnnouncementAttachmentToResponse(attachment), nil
+}
+
+// Delete implements AnnouncementAttachmentUsecase.
+func (attachmentUsecase *AnnouncementAttachmentUsecaseImpl) Delete(ctx context.Context, announcementId uint, attachmentId uint, actorId uint) error {
+	tx := attachmentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := attachmentUsecase.findPublisher(tx, actorId); err != nil {
+		return err
+	}
+
+	attachment := &entity.AnnouncementAttachment{
+		ID:             attachmentId,
+		AnnouncementID: announcementId,
+	}
+
+	if err := attachmentUsecase.AttachmentRepo.FindById(tx, attachment); err != nil {
+		return attachmentNotFoundError(err)
+	}
+
+	if err := attachmentUsecase.AttachmentRepo.Delete(tx, attachment); err != nil {
+		log.Println("failed when delete repo announcement attachment : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	removeFiles(attachmentUsecase.AttachmentDir, []string{attachment.StoredName})
+
+	log.Println("success delete from usecase announcement attachment")
+	return nil
+}
+
+// Download implements AnnouncementAttachmentUsecase.
+func (attachmentUsecase *AnnouncementAttachmentUsecaseImpl) Download(ctx context.Context, announcementId uint, attachmentId uint) (*model.AnnouncementAttachmentFile, error) {
+	tx := attachmentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	attachment := &entity.AnnouncementAttachment{
+		ID:             attachmentId,
+		AnnouncementID: announcementId,
+	}
+
+	if err := attachmentUsecase.AttachmentRepo.FindPublished(tx, time.Now(), attachment); err != nil {
+		return nil, attachmentNotFoundError(err)
+	}
+
+	path := filepath.Join(attachmentUsecase.AttachmentDir, filepath.Base(attachment.StoredName))
+	if _, err := os.Stat(path); err != nil {
+		log.Println("error download announcement attachment : ", err)
+		return nil, attachmentNotFoundError(gorm.ErrRecordNotFound)
+	}
+
+	if err := attachmentUsecase.AttachmentRepo.IncrementDownloadCount(tx, attachment.ID); err != nil {
+		log.Println("failed when count download repo announcement attachment : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return &model.AnnouncementAttachmentFile{
+		Path:     path,
+		Filename: attachment.Filename,
+		MimeType: attachment.MimeType,
+		Size:     attachment.Size,
+	}, nil
+}
+
+// findPublisher loads the actor and makes sure they may change attachments.
+func (attachmentUsecase *AnnouncementAttachmentUsecaseImpl) findPublisher(tx *gorm.DB, actorId uint) (*entity.Admin, error) {
+	actor, err := findActor(tx, attachmentUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canPublish(actor.Role) {
+		log.Printf("error manage announcement attachment : admin %d can not publish", actor.ID)
+		return nil, forbiddenError("only publishers can manage attachments")
+	}
+
+	return actor, nil
+}
+
+// store copies an upload into the attachment directory, never replacing a
+// file that is already there.
+func (attachmentUsecase *AnnouncementAttachmentUsecaseImpl) store(storedName string, file io.ReaderAt, size int64) error {
+	if err := os.MkdirAll(attachmentUsecase.AttachmentDir, 0o755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(attachmentUsecase.AttachmentDir, filepath.Base(storedName))
+
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, io.NewSectionReader(file, 0, size)); err != nil {
+		out.Close()
+		os.Remove(path)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// attachmentFilename cleans the uploaded filename for the Content-Disposition
+// of downloads and makes its extension match the sniffed type.
+func attachmentFilename(filename string, extension string) string {
+	filename = filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename)
+	filename = strings.TrimSpace(filename)
+
+	if filename == "" || filename == "." || filename == "/" {
+		filename = "attachment"
+	}
+
+	if !strings.EqualFold(filepath.Ext(filename), extension) {
+		filename += extension
+	}
+
+	return filename
+}
+
+func attachmentTooLargeError() error {
+	errorResponse := model.ErrorResponse{
+		Message: "Request entity too large",
+		Details: []string{fmt.Sprintf("attachments must not be larger than %d MB", AttachmentMaxSize>>20)},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.StatusRequestEntityTooLarge, string(jsonString))
+}
+
+func unsupportedAttachmentError(err error) error {
+	errorResponse := model.ErrorResponse{
+		Message: "Unsupported media type",
+		Details: []string{err.Error()},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.StatusUnsupportedMediaType, string(jsonString))
+}
+
+func attachmentNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Attachment data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id announcement attachment usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id announcement attachment usecase:", err)
+	return fiber.ErrInternalServerError
+}
@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type AnnouncementCategoryUsecase interface {
+	Create(ctx context.Context, request *model.AnnouncementCategoryRequest) (*model.AnnouncementCategoryResponse, error)
+	Update(ctx context.Context, request *model.AnnouncementCategoryRequest) (*model.AnnouncementCategoryResponse, error)
+	Delete(ctx context.Context, id uint, actorId uint) error
+	FindAll(ctx context.Context) (*[]model.AnnouncementCategoryResponse, error)
+}
+
+// AnnouncementCategoryUsecaseImpl manages the categories announcements are
+// filed under. Deleting a category leaves its announcements uncategorized.
+type AnnouncementCategoryUsecaseImpl struct {
+	CategoryRepo repository.AnnouncementCategoryRepository
+	AdminRepo    repository.AdminRepository
+	DB           *gorm.DB
+	Validate     *validator.Validate
+}
+
+func NewAnnouncementCategoryUsecase(categoryRepo repository.AnnouncementCategoryRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) AnnouncementCategoryUsecase {
+	return &AnnouncementCategoryUsecaseImpl{
+		CategoryRepo: categoryRepo,
+		AdminRepo:    adminRepo,
+		DB:           DB,
+		Validate:     validate,
+	}
+}
+
+// Create implements AnnouncementCategoryUsecase.
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) Create(ctx context.Context, request *model.AnnouncementCategoryRequest) (*model.AnnouncementCategoryResponse, error) {
+	tx := categoryUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := categoryUsecase.validate(request); err != nil {
+		log.Println("error create announcement category : ", err)
+		return nil, err
+	}
+
+	if _, err := categoryUsecase.findAdmin(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	category := &entity.AnnouncementCategory{
+		Name:        request.Name,
+		Slug:        request.Slug,
+		Description: request.Description,
+	}
+
+	if err := categoryUsecase.checkDuplicate(tx, category); err != nil {
+		return nil, err
+	}
+
+	if err := categoryUsecase.CategoryRepo.Create(tx, category); err != nil {
+		log.Println("failed when create repo announcement category : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success create from usecase announcement category")
+	return converter.AnnouncementCategoryToResponse(category), nil
+}
+
+// Update implements AnnouncementCategoryUsecase.
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) Update(ctx context.Context, request *model.AnnouncementCategoryRequest) (*model.AnnouncementCategoryResponse, error) {
+	tx := categoryUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := categoryUsecase.validate(request); err != nil {
+		log.Println("error update announcement category : ", err)
+		return nil, err
+	}
+
+	if _, err := categoryUsecase.findAdmin(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	category := &entity.AnnouncementCategory{ID: request.ID}
+	if err := categoryUsecase.CategoryRepo.FindById(tx, category); err != nil {
+		return nil, categoryNotFoundError(err)
+	}
+
+	category.Name = request.Name
+	category.Slug = request.Slug
+	category.Description = request.Description
+
+	if err := categoryUsecase.checkDuplicate(tx, category); err != nil {
+		return nil, err
+	}
+
+	if err := categoryUsecase.CategoryRepo.Update(tx, category); err != nil {
+		log.Println("failed when update repo announcement category : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success update from usecase announcement category")
+	return converter.AnnouncementCategoryToResponse(category), nil
+}
+
+// Delete implements AnnouncementCategoryUsecase.
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) Delete(ctx context.Context, id uint, actorId uint) error {
+	tx := categoryUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := categoryUsecase.findAdmin(tx, actorId); err != nil {
+		return err
+	}
+
+	category := &entity.AnnouncementCategory{ID: id}
+	if err := categoryUsecase.CategoryRepo.FindById(tx, category); err != nil {
+		return categoryNotFoundError(err)
+	}
+
+	if err := categoryUsecase.CategoryRepo.Delete(tx, category); err != nil {
+		log.Println("failed when delete repo announcement category : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success delete from usecase announcement category")
+	return nil
+}
+
+// FindAll implements AnnouncementCategoryUsecase.
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) FindAll(ctx context.Context) (*[]model.AnnouncementCategoryResponse, error) {
+	tx := categoryUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	categories := &[]entity.AnnouncementCategory{}
+	if err := categoryUsecase.CategoryRepo.FindAll(tx, categories); err != nil {
+		log.Println("failed when find all repo announcement category : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return converter.AnnouncementCategoriesToResponses(categories), nil
+}
+
+// validate trims the request and fills in the slug from the name.
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) validate(request *model.AnnouncementCategoryRequest) error {
+	request.Name = strings.TrimSpace(request.Name)
+	request.Description = strings.TrimSpace(request.Description)
+
+	request.Slug = util.Slugify(request.Slug)
+	if request.Slug == "" {
+		request.Slug = util.Slugify(request.Name)
+	}
+
+	if err := categoryUsecase.Validate.Struct(request); err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			validationErrors = append(validationErrors, fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag()))
+		}
+
+		return invalidAnnouncementError(validationErrors...)
+	}
+
+	if request.Slug == "" {
+		return invalidAnnouncementError("Field 'Slug' must contain letters or digits")
+	}
+
+	return nil
+}
+
+// findAdmin loads the actor, who must be an admin: categories shape the
+// announcement lists of the whole site.
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) findAdmin(tx *gorm.DB, actorId uint) (*entity.Admin, error) {
+	actor, err := findActor(tx, categoryUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Role != AdminRoleAdmin {
+		return nil, forbiddenError("only admins can manage announcement categories")
+	}
+
+	return actor, nil
+}
+
+func (categoryUsecase *AnnouncementCategoryUsecaseImpl) checkDuplicate(tx *gorm.DB, category *entity.AnnouncementCategory) error {
+	total, err := categoryUsecase.CategoryRepo.CountByNameOrSlug(tx, category)
+	if err != nil {
+		log.Println("failed when count repo announcement category : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "Duplicate entry",
+		Details: []string{fmt.Sprintf("a category named '%s' or with the slug '%s' already exists.", category.Name, category.Slug)},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrConflict.Code, string(jsonString))
+}
+
+func categoryNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Category data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id announcement category usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id announcement category usecase:", err)
+	return fiber.ErrInternalServerError
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type AnnouncementTranslationUsecase interface {
+	Create(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error)
+	Update(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error)
+	Delete(ctx context.Context, announcementId uint, locale string, actorId uint) error
+	FindAll(ctx context.Context, announcementId uint) (*model.TranslationListResponse, error)
+}
+
+// AnnouncementTranslationUsecaseImpl manages the translations of an
+// announcement, which go through review like the announcement itself.
+type AnnouncementTranslationUsecaseImpl struct {
+	AnnouncementTranslationRepo repository.AnnouncementTranslationRepository
+	AnnouncementRepo            repository.AnnouncementRepository
+	ChangeRequestRepo           repository.ChangeRequestRepository
+	ItemEditorRepo              repository.ItemEditorRepository
+	AdminRepo                   repository.AdminRepository
+	DB                          *gorm.DB
+	Validate                    *validator.Validate
+}
+
+func NewAnnouncementTranslationUsecase(announcementTranslationRepo repository.AnnouncementTranslationRepository, announcementRepo repository.AnnouncementRepository, changeRequestRepo repository.ChangeRequestRepository, itemEditorRepo repository.ItemEditorRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) AnnouncementTranslationUsecase {
+	return &AnnouncementTranslationUsecaseImpl{
+		AnnouncementTranslationRepo: announcementTranslationRepo,
+		AnnouncementRepo:            announcementRepo,
+		ChangeRequestRepo:           changeRequestRepo,
+		ItemEditorRepo:              itemEditorRepo,
+		AdminRepo:                   adminRepo,
+		DB:                          DB,
+		Validate:                    validate,
+	}
+}
+
+// Create implements AnnouncementTranslationUsecase.
+func (announcementTranslationUsecase *AnnouncementTranslationUsecaseImpl) Create(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error) {
+	tx := begin(ctx, announcementTranslationUsecase.DB)
+	defer tx.Rollback()
+
+	if err := validateTranslationRequest(announcementTranslationUsecase.Validate, request); err != nil {
+		log.Println("error create announcement translation : ", err)
+		return nil, err
+	}
+
+	actor, err := announcementTranslationUsecase.findTranslator(tx, request.ItemID, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &entity.AnnouncementTranslation{
+		AnnouncementID: request.ItemID,
+		Locale:         request.Locale,
+	}
+
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.FindByLocale(tx, translation); err == nil {
+		errorResponse := model.ErrorResponse{
+			Message: "Duplicate entry",
+			Details: []string{fmt.Sprintf("translation '%s' already exists for this announcement.", request.Locale)},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrConflict.Code, string(jsonString))
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType: ChangeItemAnnouncementTranslation,
+			ItemID:   &request.ItemID,
+			Action:   ChangeActionCreate,
+			Title:    request.Title,
+		}
+
+		return nil, queueChange(tx, announcementTranslationUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
+	translation.Title = request.Title
+	translation.ContentFormat, translation.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.Create(tx, translation); err != nil {
+		log.Println("failed when create repo announcement translation : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success create from usecase announcement translation")
+	return converter.AnnouncementTranslationToResponse(translation), nil
+}
+
+// Update implements AnnouncementTranslationUsecase.
+func (announcementTranslationUsecase *AnnouncementTranslationUsecaseImpl) Update(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error) {
+	tx := begin(ctx, announcementTranslationUsecase.DB)
+	defer tx.Rollback()
+
+	if err := validateTranslationRequest(announcementTranslationUsecase.Validate, request); err != nil {
+		log.Println("error update announcement translation : ", err)
+		return nil, err
+	}
+
+	actor, err := announcementTranslationUsecase.findTranslator(tx, request.ItemID, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &entity.AnnouncementTranslation{
+		AnnouncementID: request.ItemID,
+		Locale:         request.Locale,
+	}
+
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.FindByLocale(tx, translation); err != nil {
+		return nil, translationNotFoundError(err)
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType: ChangeItemAnnouncementTranslation,
+			ItemID:   &request.ItemID,
+			Action:   ChangeActionUpdate,
+			Title:    request.Title,
+		}
+
+		return nil, queueChange(tx, announcementTranslationUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
+	translation.Title = request.Title
+	translation.ContentFormat, translation.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.Update(tx, translation); err != nil {
+		log.Println("failed when update repo announcement translation : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success update from usecase announcement translation")
+	return converter.AnnouncementTranslationToResponse(translation), nil
+}
+
+// Delete implements AnnouncementTranslationUsecase.
+func (announcementTranslationUsecase *AnnouncementTranslationUsecaseImpl) Delete(ctx context.Context, announcementId uint, locale string, actorId uint) error {
+	tx := announcementTranslationUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := announcementTranslationUsecase.findTranslator(tx, announcementId, actorId); err != nil {
+		return err
+	}
+
+	translation := &entity.AnnouncementTranslation{
+		AnnouncementID: announcementId,
+		Locale:         locale,
+	}
+
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.FindByLocale(tx, translation); err != nil {
+		return translationNotFoundError(err)
+	}
+
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.Delete(tx, translation); err != nil {
+		log.Println("failed when delete repo announcement translation : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success delete from usecase announcement translation")
+	return nil
+}
+
+// FindAll implements AnnouncementTranslationUsecase.
+func (announcementTranslationUsecase *AnnouncementTranslationUsecaseImpl) FindAll(ctx context.Context, announcementId uint) (*model.TranslationListResponse, error) {
+	tx := announcementTranslationUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := announcementTranslationUsecase.AnnouncementRepo.FindById(tx, &entity.Announcement{ID: announcementId}); err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	translations := &[]entity.AnnouncementTranslation{}
+	if err := announcementTranslationUsecase.AnnouncementTranslationRepo.FindAllByAnnouncementId(tx, announcementId, translations); err != nil {
+		log.Println("failed when find all repo announcement translation : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase announcement translation")
+	return converter.AnnouncementTranslationsToResponse(translations), nil
+}
+
+// findTranslator loads the actor, who has to own or co-edit the
+// announcement the translation belongs to.
+func (announcementTranslationUsecase *AnnouncementTranslationUsecaseImpl) findTranslator(tx *gorm.DB, announcementId uint, actorId uint) (*entity.Admin, error) {
+	announcement := &entity.Announcement{ID: announcementId}
+	if err := announcementTranslationUsecase.AnnouncementRepo.FindById(tx, announcement); err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	actor, err := findActor(tx, announcementTranslationUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOwnership(tx, announcementTranslationUsecase.ItemEditorRepo, actor, EditorItemAnnouncement, announcement.ID, announcement.PublishedBy); err != nil {
+		return nil, err
+	}
+
+	return actor, nil
+}
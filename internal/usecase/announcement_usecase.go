@@ -7,44 +7,72 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/Bangdams/web-profile-API/internal/entity"
 	"github.com/Bangdams/web-profile-API/internal/model"
 	"github.com/Bangdams/web-profile-API/internal/model/converter"
 	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
+// Priorities of an announcement, from the least to the most pressing.
+const (
+	AnnouncementPriorityInfo      = "info"
+	AnnouncementPriorityImportant = "important"
+	AnnouncementPriorityUrgent    = "urgent"
+)
+
+const (
+	// announcementPublishInterval is how often scheduled announcements are
+	// checked for a validity window that opened.
+	announcementPublishInterval = time.Minute
+	announcementPublishBatch    = 100
+)
+
 type AnnouncementUsecase interface {
 	Create(ctx context.Context, request *model.AnnouncementCreateRequest) (*model.AnnouncementResponse, error)
 	Update(ctx context.Context, request *model.AnnouncementUpdateRequest) (*model.AnnouncementResponse, error)
-	Delete(ctx context.Context, announcemenId uint) error
-	FindAll(ctx context.Context, order string) (*[]model.AnnouncementResponse, error)
-	FindById(ctx context.Context, announcementtId uint) (*model.AnnouncementResponse, error)
-	GetFirst(ctx context.Context) (*model.AnnouncementResponse, error)
+	Patch(ctx context.Context, request *model.PatchRequest) (*model.AnnouncementResponse, error)
+	Delete(ctx context.Context, announcemenId uint, version uint, actorId uint) error
+	FindAll(ctx context.Context, request *model.AnnouncementFilterRequest) (*[]model.AnnouncementResponse, error)
+	FindById(ctx context.Context, announcementtId uint, locale string) (*model.AnnouncementResponse, error)
+	GetFirst(ctx context.Context, locale string) (*model.AnnouncementResponse, error)
+	Run(ctx context.Context)
 }
 
 type AnnouncementUsecaseImpl struct {
-	AnnouncementRepo repository.AnnouncementRepository
-	AdminRepo        repository.AdminRepository
-	DB               *gorm.DB
-	Validate         *validator.Validate
+	AnnouncementRepo  repository.AnnouncementRepository
+	CategoryRepo      repository.AnnouncementCategoryRepository
+	AdminRepo         repository.AdminRepository
+	ChangeRequestRepo repository.ChangeRequestRepository
+	ItemEditorRepo    repository.ItemEditorRepository
+	NewsletterUsecase NewsletterUsecase
+	WebhookUsecase    WebhookUsecase
+	DB                *gorm.DB
+	Validate          *validator.Validate
 }
 
-func NewAnnouncementUsecase(announcementRepo repository.AnnouncementRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) AnnouncementUsecase {
+func NewAnnouncementUsecase(announcementRepo repository.AnnouncementRepository, categoryRepo repository.AnnouncementCategoryRepository, adminRepo repository.AdminRepository, changeRequestRepo repository.ChangeRequestRepository, itemEditorRepo repository.ItemEditorRepository, newsletterUsecase NewsletterUsecase, webhookUsecase WebhookUsecase, DB *gorm.DB, validate *validator.Validate) AnnouncementUsecase {
 	return &AnnouncementUsecaseImpl{
-		AnnouncementRepo: announcementRepo,
-		AdminRepo:        adminRepo,
-		DB:               DB,
-		Validate:         validate,
+		AnnouncementRepo:  announcementRepo,
+		CategoryRepo:      categoryRepo,
+		AdminRepo:         adminRepo,
+		ChangeRequestRepo: changeRequestRepo,
+		ItemEditorRepo:    itemEditorRepo,
+		NewsletterUsecase: newsletterUsecase,
+		WebhookUsecase:    webhookUsecase,
+		DB:                DB,
+		Validate:          validate,
 	}
 }
 
 // Create implements AnnouncementUsecase.
 func (announcementUsecase *AnnouncementUsecaseImpl) Create(ctx context.Context, request *model.AnnouncementCreateRequest) (*model.AnnouncementResponse, error) {
-	tx := announcementUsecase.DB.WithContext(ctx).Begin()
+	tx := begin(ctx, announcementUsecase.DB)
 	defer tx.Rollback()
 
 	errorResponse := &model.ErrorResponse{}
@@ -67,13 +95,53 @@ func (announcementUsecase *AnnouncementUsecaseImpl) Create(ctx context.Context,
 		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
 	}
 
+	dates, err := parseAnnouncementDates(request)
+	if err != nil {
+		log.Println("error create announcement : ", err)
+		return nil, err
+	}
+
+	category, err := announcementUsecase.findCategory(tx, request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := findActor(tx, announcementUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// editors create items in their own name only
+	if err := checkOwnerChange(actor, actor.ID, request.PublishedBy); err != nil {
+		return nil, err
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType: ChangeItemAnnouncement,
+			Action:   ChangeActionCreate,
+			Title:    request.Title,
+		}
+
+		return nil, queueChange(tx, announcementUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
 	announcement := entity.Announcement{
 		Title:       request.Title,
 		Content:     request.Content,
 		Image:       request.Image,
+		CategoryID:  request.CategoryID,
+		Priority:    announcementPriority(request.Priority),
+		ValidFrom:   dates.ValidFrom,
+		ValidUntil:  dates.ValidUntil,
+		Pinned:      request.Pinned,
+		PinnedUntil: dates.PinnedUntil,
 		PublishedBy: request.PublishedBy,
+		Version:     1,
 	}
 
+	announcement.ContentFormat, announcement.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+
 	if err := announcementUsecase.AnnouncementRepo.Create(tx, &announcement); err != nil {
 		log.Println("failed when create repo announcement : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -102,19 +170,30 @@ func (announcementUsecase *AnnouncementUsecaseImpl) Create(ctx context.Context,
 	}
 
 	announcement.Admin = *admin
+	announcement.Category = category
 	if err := tx.Commit().Error; err != nil {
 		log.Println("Failed commit transaction : ", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
+	response := converter.AnnouncementToResponse(&announcement)
+
+	// one scheduled ahead is not published yet, the digest and Run take it
+	// up once its validity window opens
+	afterCommit(ctx, func() {
+		if announcementUsecase.publish(ctx, announcement.ID) {
+			announcementUsecase.NewsletterUsecase.Notify()
+		}
+	})
+
 	log.Println("success create from usecase announcement")
-	return converter.AnnouncementToResponse(&announcement), nil
+	return response, nil
 
 }
 
 // Delete implements AnnouncementUsecase.
-func (announcementUsecase *AnnouncementUsecaseImpl) Delete(ctx context.Context, announcementId uint) error {
-	tx := announcementUsecase.DB.WithContext(ctx).Begin()
+func (announcementUsecase *AnnouncementUsecaseImpl) Delete(ctx context.Context, announcementId uint, version uint, actorId uint) error {
+	tx := begin(ctx, announcementUsecase.DB)
 	defer tx.Rollback()
 
 	announcement := &entity.Announcement{
@@ -139,6 +218,26 @@ func (announcementUsecase *AnnouncementUsecaseImpl) Delete(ctx context.Context,
 		return fiber.ErrInternalServerError
 	}
 
+	actor, err := findActor(tx, announcementUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	if err := checkOwnership(tx, announcementUsecase.ItemEditorRepo, actor, EditorItemAnnouncement, announcement.ID, announcement.PublishedBy); err != nil {
+		return err
+	}
+
+	currentVersion, err := announcementUsecase.AnnouncementRepo.LockVersion(tx, announcement.ID)
+	if err != nil {
+		log.Println("error delete announcement : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := checkVersion(currentVersion, version); err != nil {
+		log.Println("error delete announcement : ", err)
+		return err
+	}
+
 	err = announcementUsecase.AnnouncementRepo.Delete(tx, announcement)
 	if err != nil {
 		log.Println("failed when delete repo announcement : ", err)
@@ -156,12 +255,14 @@ func (announcementUsecase *AnnouncementUsecaseImpl) Delete(ctx context.Context,
 }
 
 // FindAll implements AnnouncementUsecase.
-func (announcementUsecase *AnnouncementUsecaseImpl) FindAll(ctx context.Context, order string) (*[]model.AnnouncementResponse, error) {
+func (announcementUsecase *AnnouncementUsecaseImpl) FindAll(ctx context.Context, request *model.AnnouncementFilterRequest) (*[]model.AnnouncementResponse, error) {
 	tx := announcementUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	var announcements = &[]entity.Announcement{}
-	err := announcementUsecase.AnnouncementRepo.FindAll(tx, strings.ToUpper(order), announcements)
+	normalizeAnnouncementFilter(request)
+
+	err := announcementUsecase.AnnouncementRepo.FindAll(tx, request, time.Now(), announcements)
 	if err != nil {
 		log.Println("failed when find all repo announcement : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -172,12 +273,16 @@ func (announcementUsecase *AnnouncementUsecaseImpl) FindAll(ctx context.Context,
 		return nil, fiber.ErrInternalServerError
 	}
 
+	for i := range *announcements {
+		localizeAnnouncement(&(*announcements)[i], request.Locale)
+	}
+
 	log.Println("success find all from usecase announcement")
 	return converter.AnnouncementToResponses(announcements), nil
 }
 
 // FindById implements AnnouncementUsecase.
-func (announcementUsecase *AnnouncementUsecaseImpl) FindById(ctx context.Context, announcementId uint) (*model.AnnouncementResponse, error) {
+func (announcementUsecase *AnnouncementUsecaseImpl) FindById(ctx context.Context, announcementId uint, locale string) (*model.AnnouncementResponse, error) {
 	tx := announcementUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -206,16 +311,18 @@ func (announcementUsecase *AnnouncementUsecaseImpl) FindById(ctx context.Context
 		log.Println("Failed commit transaction : ", err)
 		return nil, fiber.ErrInternalServerError
 	}
+
+	localizeAnnouncement(announcement, locale)
 	return converter.AnnouncementToResponse(announcement), nil
 }
 
 // GetFirst implements AnnouncementUsecase.
-func (announcementUsecase *AnnouncementUsecaseImpl) GetFirst(ctx context.Context) (*model.AnnouncementResponse, error) {
+func (announcementUsecase *AnnouncementUsecaseImpl) GetFirst(ctx context.Context, locale string) (*model.AnnouncementResponse, error) {
 	tx := announcementUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	announcement := new(entity.Announcement)
-	if err := announcementUsecase.AnnouncementRepo.GetFirst(tx, announcement); err != nil {
+	if err := announcementUsecase.AnnouncementRepo.GetFirst(tx, time.Now(), announcement); err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			errorResponse := model.ErrorResponse{
 				Message: "Announcement data was not found",
@@ -237,12 +344,14 @@ func (announcementUsecase *AnnouncementUsecaseImpl) GetFirst(ctx context.Context
 		log.Println("Failed commit transaction : ", err)
 		return nil, fiber.ErrInternalServerError
 	}
+
+	localizeAnnouncement(announcement, locale)
 	return converter.AnnouncementToResponse(announcement), nil
 }
 
 // Update implements AnnouncementUsecase.
 func (announcementUsecase *AnnouncementUsecaseImpl) Update(ctx context.Context, request *model.AnnouncementUpdateRequest) (*model.AnnouncementResponse, error) {
-	tx := announcementUsecase.DB.WithContext(ctx).Begin()
+	tx := begin(ctx, announcementUsecase.DB)
 	defer tx.Rollback()
 
 	errorResponse := &model.ErrorResponse{}
@@ -265,14 +374,78 @@ func (announcementUsecase *AnnouncementUsecaseImpl) Update(ctx context.Context,
 		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
 	}
 
+	dates, err := parseAnnouncementDates(&request.AnnouncementCreateRequest)
+	if err != nil {
+		log.Println("error update announcement : ", err)
+		return nil, err
+	}
+
+	currentVersion, err := announcementUsecase.AnnouncementRepo.LockVersion(tx, request.ID)
+	if err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error update announcement : ", err)
+		return nil, err
+	}
+
+	actor, err := findActor(tx, announcementUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &entity.Announcement{
+		ID: request.ID,
+	}
+
+	if err := announcementUsecase.AnnouncementRepo.FindById(tx, current); err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	if err := checkOwnership(tx, announcementUsecase.ItemEditorRepo, actor, EditorItemAnnouncement, current.ID, current.PublishedBy); err != nil {
+		return nil, err
+	}
+
+	if err := checkOwnerChange(actor, current.PublishedBy, request.PublishedBy); err != nil {
+		return nil, err
+	}
+
+	category, err := announcementUsecase.findCategory(tx, request.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType:    ChangeItemAnnouncement,
+			ItemID:      &request.ID,
+			Action:      ChangeActionUpdate,
+			BaseVersion: currentVersion,
+			Title:       request.Title,
+		}
+
+		return nil, queueChange(tx, announcementUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
 	announcement := entity.Announcement{
 		ID:          request.ID,
+		Version:     currentVersion + 1,
 		Title:       request.Title,
 		Content:     request.Content,
 		Image:       request.Image,
+		CategoryID:  request.CategoryID,
+		Priority:    announcementPriority(request.Priority),
+		ValidFrom:   dates.ValidFrom,
+		ValidUntil:  dates.ValidUntil,
+		Pinned:      request.Pinned,
+		PinnedUntil: dates.PinnedUntil,
 		PublishedBy: request.PublishedBy,
+		ViewCount:   current.ViewCount,
 	}
 
+	announcement.ContentFormat, announcement.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+
 	if err := announcementUsecase.AnnouncementRepo.Update(tx, &announcement); err != nil {
 		log.Println("failed when update repo announcement : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -301,13 +474,438 @@ func (announcementUsecase *AnnouncementUsecaseImpl) Update(ctx context.Context,
 	}
 
 	announcement.Admin = *admin
+	announcement.Category = category
+	announcement.Attachments = current.Attachments
 
 	if err := tx.Commit().Error; err != nil {
 		log.Println("Failed commit transaction : ", err)
 		return nil, fiber.ErrInternalServerError
 	}
 
+	// the update may have moved the validity window to now
+	afterCommit(ctx, func() {
+		announcementUsecase.publish(ctx, announcement.ID)
+	})
+
 	log.Println("success update from usecase announcement")
 	return converter.AnnouncementToResponse(&announcement), nil
 
 }
+
+// Patch implements AnnouncementUsecase.
+func (announcementUsecase *AnnouncementUsecaseImpl) Patch(ctx context.Context, request *model.PatchRequest) (*model.AnnouncementResponse, error) {
+	tx := begin(ctx, announcementUsecase.DB)
+	defer tx.Rollback()
+
+	currentVersion, err := announcementUsecase.AnnouncementRepo.LockVersion(tx, request.ID)
+	if err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error patch announcement : ", err)
+		return nil, err
+	}
+
+	announcement := &entity.Announcement{
+		ID: request.ID,
+	}
+
+	if err := announcementUsecase.AnnouncementRepo.FindById(tx, announcement); err != nil {
+		return nil, announcementNotFoundError(err)
+	}
+
+	actor, err := findActor(tx, announcementUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// checked on the live row first, so that editors learn about missing
+	// rights before any validation error
+	if err := checkOwnership(tx, announcementUsecase.ItemEditorRepo, actor, EditorItemAnnouncement, announcement.ID, announcement.PublishedBy); err != nil {
+		return nil, err
+	}
+
+	updateRequest := announcementToUpdateRequest(announcement)
+
+	members, err := applyMergePatch(announcementUsecase.Validate, updateRequest, request.Patch, nil)
+	if err != nil {
+		log.Println("error patch announcement : ", err)
+		return nil, err
+	}
+
+	dates, err := parseAnnouncementDates(&updateRequest.AnnouncementCreateRequest)
+	if err != nil {
+		log.Println("error patch announcement : ", err)
+		return nil, err
+	}
+
+	if err := checkOwnerChange(actor, announcement.PublishedBy, updateRequest.PublishedBy); err != nil {
+		return nil, err
+	}
+
+	if patchesAny(members, "category_id") {
+		if _, err := announcementUsecase.findCategory(tx, updateRequest.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	// a patch is reviewed as an update to the whole patched state
+	if !canPublish(actor.Role) && len(members) > 0 {
+		change := &entity.ChangeRequest{
+			ItemType:    ChangeItemAnnouncement,
+			ItemID:      &announcement.ID,
+			Action:      ChangeActionUpdate,
+			BaseVersion: currentVersion,
+			Title:       updateRequest.Title,
+		}
+
+		return nil, queueChange(tx, announcementUsecase.ChangeRequestRepo, change, updateRequest, actor)
+	}
+
+	patched := &entity.Announcement{
+		ID:          announcement.ID,
+		Version:     currentVersion + 1,
+		Title:       updateRequest.Title,
+		Image:       updateRequest.Image,
+		CategoryID:  updateRequest.CategoryID,
+		Priority:    announcementPriority(updateRequest.Priority),
+		ValidFrom:   dates.ValidFrom,
+		ValidUntil:  dates.ValidUntil,
+		Pinned:      updateRequest.Pinned,
+		PinnedUntil: dates.PinnedUntil,
+		PublishedBy: updateRequest.PublishedBy,
+	}
+
+	patched.ContentFormat, patched.Content = util.PrepareContentBody(updateRequest.ContentFormat, updateRequest.Content)
+
+	var columns []string
+	for _, member := range members {
+		switch member {
+		case "content", "content_format":
+			columns = append(columns, "content", "content_format")
+		case "pinned":
+			// unpinning drops the expiry of the pin as well
+			columns = append(columns, "pinned", "pinned_until")
+		default:
+			columns = append(columns, member)
+		}
+	}
+
+	if patchesAny(members, "published_by") {
+		admin := &entity.Admin{
+			ID: patched.PublishedBy,
+		}
+
+		if err := announcementUsecase.AdminRepo.FindById(tx, admin); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				errorResponse := model.ErrorResponse{
+					Message: "Admin data was not found",
+					Details: []string{},
+				}
+
+				jsonString, _ := json.Marshal(errorResponse)
+
+				log.Println("error patch announcement usecase : ", err)
+
+				return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+			}
+
+			log.Println("Error patch announcement usecase:", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if len(columns) > 0 {
+		columns = append(columns, "version")
+
+		if err := announcementUsecase.AnnouncementRepo.Patch(tx, patched, columns); err != nil {
+			log.Println("failed when patch repo announcement : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	announcement = &entity.Announcement{
+		ID: request.ID,
+	}
+
+	if err := announcementUsecase.AnnouncementRepo.FindById(tx, announcement); err != nil {
+		log.Println("failed when find by id repo announcement : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	afterCommit(ctx, func() {
+		announcementUsecase.publish(ctx, request.ID)
+	})
+
+	localizeAnnouncement(announcement, util.DefaultLocale)
+
+	log.Println("success patch from usecase announcement")
+	return converter.AnnouncementToResponse(announcement), nil
+}
+
+// Run implements AnnouncementUsecase. It publishes the announcements whose
+// validity window opened since they were saved.
+func (announcementUsecase *AnnouncementUsecaseImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(announcementPublishInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := announcementUsecase.publishDue(ctx); err != nil {
+			log.Println("failed to publish announcements : ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDue publishes every announcement that is visible but was not
+// announced yet.
+func (announcementUsecase *AnnouncementUsecaseImpl) publishDue(ctx context.Context) error {
+	for {
+		var ids []uint
+		err := announcementUsecase.AnnouncementRepo.FindUnpublishedIds(announcementUsecase.DB.WithContext(ctx), time.Now(), announcementPublishBatch, &ids)
+		if err != nil {
+			return err
+		}
+
+		published := 0
+		for _, id := range ids {
+			if announcementUsecase.publish(ctx, id) {
+				published++
+			}
+		}
+
+		// a batch left over by another instance is not retried forever
+		if len(ids) < announcementPublishBatch || published == 0 {
+			return nil
+		}
+	}
+}
+
+// publish sends the announcement.published event once the validity window
+// of the announcement is open. Marking it published first makes sure the
+// event goes out once, whoever gets there first. It reports whether the
+// announcement was published by this call.
+func (announcementUsecase *AnnouncementUsecaseImpl) publish(ctx context.Context, announcementId uint) bool {
+	tx := announcementUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	marked, err := announcementUsecase.AnnouncementRepo.MarkPublished(tx, announcementId, time.Now())
+	if err != nil {
+		log.Println("failed when mark published repo announcement : ", err)
+		return false
+	}
+
+	if !marked {
+		return false
+	}
+
+	announcement := &entity.Announcement{ID: announcementId}
+	if err := announcementUsecase.AnnouncementRepo.FindById(tx, announcement); err != nil {
+		log.Println("failed when find by id repo announcement : ", err)
+		return false
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return false
+	}
+
+	localizeAnnouncement(announcement, util.DefaultLocale)
+
+	announcementUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventAnnouncementPublished, converter.AnnouncementToResponse(announcement))
+	return true
+}
+
+func announcementToUpdateRequest(announcement *entity.Announcement) *model.AnnouncementUpdateRequest {
+	request := &model.AnnouncementUpdateRequest{
+		ID: announcement.ID,
+		AnnouncementCreateRequest: model.AnnouncementCreateRequest{
+			Title:         announcement.Title,
+			Content:       announcement.Content,
+			ContentFormat: announcement.ContentFormat,
+			Image:         announcement.Image,
+			CategoryID:    announcement.CategoryID,
+			Priority:      announcement.Priority,
+			Pinned:        announcement.Pinned,
+			PublishedBy:   announcement.PublishedBy,
+		},
+	}
+
+	if announcement.PinnedUntil != nil {
+		request.PinnedUntil = announcement.PinnedUntil.Format(time.RFC3339)
+	}
+
+	if announcement.ValidFrom != nil {
+		request.ValidFrom = announcement.ValidFrom.Format(time.RFC3339)
+	}
+
+	if announcement.ValidUntil != nil {
+		request.ValidUntil = announcement.ValidUntil.Format(time.RFC3339)
+	}
+
+	return request
+}
+
+// announcementDates are the parsed validity window and pin expiry of an
+// announcement request.
+type announcementDates struct {
+	ValidFrom   *time.Time
+	ValidUntil  *time.Time
+	PinnedUntil *time.Time
+}
+
+// parseAnnouncementDates reads the validity window and the pin expiry of
+// request. The expiry only counts for pinned announcements.
+func parseAnnouncementDates(request *model.AnnouncementCreateRequest) (*announcementDates, error) {
+	dates := new(announcementDates)
+
+	if value := strings.TrimSpace(request.ValidFrom); value != "" {
+		t, err := parseEventTime(value, !strings.Contains(value, "T"))
+		if err != nil {
+			return nil, invalidAnnouncementError(fmt.Sprintf("Field 'ValidFrom' %s", err))
+		}
+		dates.ValidFrom = &t
+	}
+
+	var err error
+	if dates.ValidUntil, err = parseAnnouncementEnd(request.ValidUntil, "ValidUntil"); err != nil {
+		return nil, err
+	}
+
+	if dates.ValidFrom != nil && dates.ValidUntil != nil && !dates.ValidUntil.After(*dates.ValidFrom) {
+		return nil, invalidAnnouncementError("Field 'ValidUntil' must be after 'ValidFrom'")
+	}
+
+	if request.Pinned {
+		if dates.PinnedUntil, err = parseAnnouncementEnd(request.PinnedUntil, "PinnedUntil"); err != nil {
+			return nil, err
+		}
+	}
+
+	return dates, nil
+}
+
+// parseAnnouncementEnd reads an optional end of a period. A date keeps the
+// period running until the end of that day.
+func parseAnnouncementEnd(value string, field string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	allDay := !strings.Contains(value, "T")
+	t, err := parseEventTime(value, allDay)
+	if err != nil {
+		return nil, invalidAnnouncementError(fmt.Sprintf("Field '%s' %s", field, err))
+	}
+
+	if allDay {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return &t, nil
+}
+
+// findCategory loads the category an announcement is filed under, or nil
+// when it has none.
+func (announcementUsecase *AnnouncementUsecaseImpl) findCategory(tx *gorm.DB, categoryId *uint) (*entity.AnnouncementCategory, error) {
+	if categoryId == nil {
+		return nil, nil
+	}
+
+	category := &entity.AnnouncementCategory{ID: *categoryId}
+	if err := announcementUsecase.CategoryRepo.FindById(tx, category); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("error find category announcement usecase : ", err)
+			return nil, invalidAnnouncementError(fmt.Sprintf("Field 'CategoryID' refers to no category %d", *categoryId))
+		}
+
+		log.Println("Error find category announcement usecase:", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return category, nil
+}
+
+// announcementPriority defaults an empty priority to plain information.
+func announcementPriority(priority string) string {
+	if priority == "" {
+		return AnnouncementPriorityInfo
+	}
+
+	return priority
+}
+
+func normalizeAnnouncementFilter(request *model.AnnouncementFilterRequest) {
+	request.Order = strings.ToUpper(request.Order)
+	request.Priority = strings.ToLower(request.Priority)
+	request.Category = strings.ToLower(strings.TrimSpace(request.Category))
+
+	switch request.Priority {
+	case AnnouncementPriorityInfo, AnnouncementPriorityImportant, AnnouncementPriorityUrgent:
+	default:
+		request.Priority = ""
+	}
+}
+
+// localizeAnnouncement swaps the title and body for the first translation
+// found along the fallback chain of the requested locale.
+func localizeAnnouncement(announcement *entity.Announcement, locale string) {
+	announcement.Locale = util.DefaultLocale
+
+	for _, candidate := range util.LocaleFallbacks(locale) {
+		if candidate == util.DefaultLocale {
+			return
+		}
+
+		for _, translation := range announcement.Translations {
+			if translation.Locale == candidate {
+				announcement.Title = translation.Title
+				announcement.Content = translation.Content
+				announcement.ContentFormat = translation.ContentFormat
+				announcement.Locale = candidate
+				return
+			}
+		}
+	}
+}
+
+func invalidAnnouncementError(details ...string) error {
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: details,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+func announcementNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Announcement data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id announcement usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id announcement usecase:", err)
+	return fiber.ErrInternalServerError
+}
@@ -0,0 +1,555 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	ChangeItemContent                 = "content"
+	ChangeItemAnnouncement            = "announcement"
+	ChangeItemContentTranslation      = "content_translation"
+	ChangeItemAnnouncementTranslation = "announcement_translation"
+
+	ChangeActionCreate = "create"
+	ChangeActionUpdate = "update"
+)
+
+// ChangePendingError is returned by the create and update operations of
+// contents, announcements and their translations when the actor has no publish rights and the
+// change was queued for review instead of being applied.
+type ChangePendingError struct {
+	Change *model.ChangeRequestResponse
+}
+
+func (err *ChangePendingError) Error() string {
+	return fmt.Sprintf("change request %d is pending review", err.Change.ID)
+}
+
+type ChangeRequestUsecase interface {
+	FindPending(ctx context.Context, itemType string, reviewerId uint) (*[]model.ChangeRequestResponse, error)
+	FindSubmitted(ctx context.Context, adminId uint) (*[]model.ChangeRequestResponse, error)
+	FindById(ctx context.Context, changeId uint, adminId uint) (*model.ChangeRequestResponse, error)
+	Review(ctx context.Context, request *model.ChangeReviewRequest) (*model.ChangeRequestResponse, error)
+}
+
+// ChangeRequestUsecaseImpl runs the review queue. An approved change is
+// applied through the content and announcement usecases on behalf of the
+// reviewer, so it is validated and version checked like a direct edit, and
+// inside the transaction that records the approval.
+type ChangeRequestUsecaseImpl struct {
+	ChangeRequestRepo              repository.ChangeRequestRepository
+	ContentRepo                    repository.ContentRepository
+	AnnouncementRepo               repository.AnnouncementRepository
+	ContentTranslationRepo         repository.ContentTranslationRepository
+	AnnouncementTranslationRepo    repository.AnnouncementTranslationRepository
+	AdminRepo                      repository.AdminRepository
+	ContentUsecase                 ContentUsecase
+	AnnouncementUsecase            AnnouncementUsecase
+	ContentTranslationUsecase      ContentTranslationUsecase
+	AnnouncementTranslationUsecase AnnouncementTranslationUsecase
+	DB                             *gorm.DB
+	Validate                       *validator.Validate
+	UploadDir                      string
+}
+
+func NewChangeRequestUsecase(changeRequestRepo repository.ChangeRequestRepository, contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, contentTranslationRepo repository.ContentTranslationRepository, announcementTranslationRepo repository.AnnouncementTranslationRepository, adminRepo repository.AdminRepository, contentUsecase ContentUsecase, announcementUsecase AnnouncementUsecase, contentTranslationUsecase ContentTranslationUsecase, announcementTranslationUsecase AnnouncementTranslationUsecase, DB *gorm.DB, validate *validator.Validate) ChangeRequestUsecase {
+	return &ChangeRequestUsecaseImpl{
+		ChangeRequestRepo:              changeRequestRepo,
+		ContentRepo:                    contentRepo,
+		AnnouncementRepo:               announcementRepo,
+		ContentTranslationRepo:         contentTranslationRepo,
+		AnnouncementTranslationRepo:    announcementTranslationRepo,
+		AdminRepo:                      adminRepo,
+		ContentUsecase:                 contentUsecase,
+		AnnouncementUsecase:            announcementUsecase,
+		ContentTranslationUsecase:      contentTranslationUsecase,
+		AnnouncementTranslationUsecase: announcementTranslationUsecase,
+		DB:                             DB,
+		Validate:                       validate,
+		UploadDir:                      UploadDir,
+	}
+}
+
+// FindPending implements ChangeRequestUsecase.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) FindPending(ctx context.Context, itemType string, reviewerId uint) (*[]model.ChangeRequestResponse, error) {
+	switch itemType {
+	case "", ChangeItemContent, ChangeItemAnnouncement, ChangeItemContentTranslation, ChangeItemAnnouncementTranslation:
+	default:
+		return nil, changeTypeError()
+	}
+
+	tx := changeRequestUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	reviewer, err := findActor(tx, changeRequestUsecase.AdminRepo, reviewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canPublish(reviewer.Role) {
+		return nil, forbiddenError("only publishers can review changes")
+	}
+
+	var changes = &[]entity.ChangeRequest{}
+	if err := changeRequestUsecase.ChangeRequestRepo.FindPending(tx, itemType, changes); err != nil {
+		log.Println("failed when find pending repo change request : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find pending from usecase change request")
+	return converter.ChangeRequestToResponses(changes), nil
+}
+
+// FindSubmitted implements ChangeRequestUsecase.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) FindSubmitted(ctx context.Context, adminId uint) (*[]model.ChangeRequestResponse, error) {
+	tx := changeRequestUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var changes = &[]entity.ChangeRequest{}
+	if err := changeRequestUsecase.ChangeRequestRepo.FindByRequester(tx, adminId, changes); err != nil {
+		log.Println("failed when find by requester repo change request : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find submitted from usecase change request")
+	return converter.ChangeRequestToResponses(changes), nil
+}
+
+// FindById implements ChangeRequestUsecase.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) FindById(ctx context.Context, changeId uint, adminId uint) (*model.ChangeRequestResponse, error) {
+	tx := changeRequestUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	actor, err := findActor(tx, changeRequestUsecase.AdminRepo, adminId)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &entity.ChangeRequest{ID: changeId}
+	if err := changeRequestUsecase.ChangeRequestRepo.FindById(tx, change); err != nil {
+		return nil, changeNotFoundError(err)
+	}
+
+	if change.RequestedBy != actor.ID && !canPublish(actor.Role) {
+		return nil, forbiddenError("only publishers can review changes")
+	}
+
+	changes, stale, err := changeRequestUsecase.diff(tx, change)
+	if err != nil {
+		log.Println("failed to diff change request : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := converter.ChangeRequestToResponse(change)
+	response.Changes = changes
+	response.Stale = stale && change.Status == "pending"
+
+	log.Println("success find by id from usecase change request")
+	return response, nil
+}
+
+// Review implements ChangeRequestUsecase.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) Review(ctx context.Context, request *model.ChangeReviewRequest) (*model.ChangeRequestResponse, error) {
+	if err := changeRequestUsecase.Validate.Struct(request); err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+
+		errorResponse := model.ErrorResponse{
+			Message: "invalid request parameter",
+			Details: validationErrors,
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error review change request : ", err)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	tx := changeRequestUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	reviewer, err := findActor(tx, changeRequestUsecase.AdminRepo, request.ReviewedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canPublish(reviewer.Role) {
+		return nil, forbiddenError("only publishers can review changes")
+	}
+
+	change := &entity.ChangeRequest{ID: request.ID}
+	if err := changeRequestUsecase.ChangeRequestRepo.FindById(tx, change); err != nil {
+		return nil, changeNotFoundError(err)
+	}
+
+	now := time.Now()
+	change.Status = request.Status
+	change.ReviewedBy = &reviewer.ID
+	change.ReviewedAt = &now
+	change.ReviewComment = request.Comment
+
+	// the change is claimed before it is applied so that two reviewers
+	// can not apply it twice
+	claimed, err := changeRequestUsecase.ChangeRequestRepo.Review(tx, change)
+	if err != nil {
+		log.Println("failed when review repo change request : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if !claimed {
+		errorResponse := model.ErrorResponse{
+			Message: "Conflict",
+			Details: []string{"the change request was already reviewed"},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrConflict.Code, string(jsonString))
+	}
+
+	var rejectedImages []string
+	if change.Status == "rejected" {
+		if rejectedImages, err = changeRequestUsecase.unusedPayloadImages(tx, change); err != nil {
+			log.Println("failed when count image usage : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	// the change is applied in the same transaction as the approval, a
+	// change that fails to apply, e.g. because the live item was edited in
+	// the meantime, stays pending for the reviewer to decide again
+	applyCtx, applied := withTx(ctx, tx)
+	if change.Status == "approved" {
+		if err := changeRequestUsecase.apply(applyCtx, tx, change, reviewer.ID); err != nil {
+			log.Println("failed to apply change request : ", err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	applied()
+
+	removeImages(changeRequestUsecase.UploadDir, rejectedImages)
+
+	change.Reviewer = *reviewer
+
+	log.Println("success review from usecase change request")
+	return converter.ChangeRequestToResponse(change), nil
+}
+
+// apply replays the stored request against the live item. Updates carry the
+// version the change was based on, so a change made against an older state
+// fails with 412 instead of silently overwriting newer edits. ctx has to
+// come from withTx on tx.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) apply(ctx context.Context, tx *gorm.DB, change *entity.ChangeRequest, reviewerId uint) error {
+	var itemId uint
+
+	switch change.ItemType + "." + change.Action {
+	case ChangeItemContent + "." + ChangeActionCreate:
+		request := new(model.ContentCreateRequest)
+		if err := json.Unmarshal([]byte(change.Payload), request); err != nil {
+			return err
+		}
+		request.ActorID = reviewerId
+
+		response, err := changeRequestUsecase.ContentUsecase.Create(ctx, request)
+		if err != nil {
+			return err
+		}
+		itemId = response.ID
+	case ChangeItemContent + "." + ChangeActionUpdate:
+		request := new(model.ContentUpdateRequest)
+		if err := json.Unmarshal([]byte(change.Payload), request); err != nil {
+			return err
+		}
+		request.ActorID = reviewerId
+		request.Version = change.BaseVersion
+
+		if _, err := changeRequestUsecase.ContentUsecase.Update(ctx, request); err != nil {
+			return err
+		}
+	case ChangeItemAnnouncement + "." + ChangeActionCreate:
+		request := new(model.AnnouncementCreateRequest)
+		if err := json.Unmarshal([]byte(change.Payload), request); err != nil {
+			return err
+		}
+		request.ActorID = reviewerId
+
+		response, err := changeRequestUsecase.AnnouncementUsecase.Create(ctx, request)
+		if err != nil {
+			return err
+		}
+		itemId = response.ID
+	case ChangeItemAnnouncement + "." + ChangeActionUpdate:
+		request := new(model.AnnouncementUpdateRequest)
+		if err := json.Unmarshal([]byte(change.Payload), request); err != nil {
+			return err
+		}
+		request.ActorID = reviewerId
+		request.Version = change.BaseVersion
+
+		if _, err := changeRequestUsecase.AnnouncementUsecase.Update(ctx, request); err != nil {
+			return err
+		}
+	case ChangeItemContentTranslation + "." + ChangeActionCreate:
+		request, err := translationPayload(change, reviewerId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := changeRequestUsecase.ContentTranslationUsecase.Create(ctx, request); err != nil {
+			return err
+		}
+	case ChangeItemContentTranslation + "." + ChangeActionUpdate:
+		request, err := translationPayload(change, reviewerId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := changeRequestUsecase.ContentTranslationUsecase.Update(ctx, request); err != nil {
+			return err
+		}
+	case ChangeItemAnnouncementTranslation + "." + ChangeActionCreate:
+		request, err := translationPayload(change, reviewerId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := changeRequestUsecase.AnnouncementTranslationUsecase.Create(ctx, request); err != nil {
+			return err
+		}
+	case ChangeItemAnnouncementTranslation + "." + ChangeActionUpdate:
+		request, err := translationPayload(change, reviewerId)
+		if err != nil {
+			return err
+		}
+
+		if _, err := changeRequestUsecase.AnnouncementTranslationUsecase.Update(ctx, request); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown change %s %s", change.ItemType, change.Action)
+	}
+
+	// a translation belongs to an item that exists already
+	if change.Action == ChangeActionCreate && itemId != 0 {
+		change.ItemID = &itemId
+
+		if err := changeRequestUsecase.ChangeRequestRepo.SetItemId(tx, change); err != nil {
+			log.Println("failed when set item id repo change request : ", err)
+			return fiber.ErrInternalServerError
+		}
+	}
+
+	return nil
+}
+
+// diff compares the proposed request with the live item field by field. It
+// reports the change as stale when the live item moved past the version the
+// change was based on or no longer exists.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) diff(tx *gorm.DB, change *entity.ChangeRequest) ([]model.ChangeDiffResponse, bool, error) {
+	proposed := map[string]any{}
+	if err := json.Unmarshal([]byte(change.Payload), &proposed); err != nil {
+		return nil, false, err
+	}
+
+	live := map[string]any{}
+	stale := false
+
+	if change.Action == ChangeActionUpdate && change.ItemID != nil {
+		var current any
+		var version uint
+
+		switch change.ItemType {
+		case ChangeItemContent:
+			content := &entity.Content{ID: *change.ItemID}
+			if err := changeRequestUsecase.ContentRepo.FindById(tx, content); err == nil {
+				current, version = contentToUpdateRequest(content), content.Version
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, false, err
+			}
+		case ChangeItemAnnouncement:
+			announcement := &entity.Announcement{ID: *change.ItemID}
+			if err := changeRequestUsecase.AnnouncementRepo.FindById(tx, announcement); err == nil {
+				current, version = announcementToUpdateRequest(announcement), announcement.Version
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, false, err
+			}
+		case ChangeItemContentTranslation:
+			locale, _ := proposed["locale"].(string)
+			translation := &entity.ContentTranslation{ContentID: *change.ItemID, Locale: locale}
+			if err := changeRequestUsecase.ContentTranslationRepo.FindByLocale(tx, translation); err == nil {
+				current = &model.TranslationRequest{ItemID: translation.ContentID, Locale: translation.Locale, Title: translation.Title, Content: translation.Content, ContentFormat: translation.ContentFormat}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, false, err
+			}
+		case ChangeItemAnnouncementTranslation:
+			locale, _ := proposed["locale"].(string)
+			translation := &entity.AnnouncementTranslation{AnnouncementID: *change.ItemID, Locale: locale}
+			if err := changeRequestUsecase.AnnouncementTranslationRepo.FindByLocale(tx, translation); err == nil {
+				current = &model.TranslationRequest{ItemID: translation.AnnouncementID, Locale: translation.Locale, Title: translation.Title, Content: translation.Content, ContentFormat: translation.ContentFormat}
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, false, err
+			}
+		}
+
+		if current == nil {
+			stale = true
+		} else {
+			// both sides go through JSON so they compare alike
+			currentJSON, err := json.Marshal(current)
+			if err != nil {
+				return nil, false, err
+			}
+
+			if err := json.Unmarshal(currentJSON, &live); err != nil {
+				return nil, false, err
+			}
+
+			stale = version != change.BaseVersion
+		}
+	}
+
+	fields := make([]string, 0, len(proposed))
+	for field := range proposed {
+		if field != "id" {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := []model.ChangeDiffResponse{}
+	for _, field := range fields {
+		if reflect.DeepEqual(live[field], proposed[field]) {
+			continue
+		}
+
+		changes = append(changes, model.ChangeDiffResponse{
+			Field:    field,
+			Live:     live[field],
+			Proposed: proposed[field],
+		})
+	}
+
+	return changes, stale, nil
+}
+
+func translationPayload(change *entity.ChangeRequest, reviewerId uint) (*model.TranslationRequest, error) {
+	request := new(model.TranslationRequest)
+	if err := json.Unmarshal([]byte(change.Payload), request); err != nil {
+		return nil, err
+	}
+	request.ActorID = reviewerId
+
+	return request, nil
+}
+
+// unusedPayloadImages returns the image uploaded for a rejected change when
+// no live item uses it.
+func (changeRequestUsecase *ChangeRequestUsecaseImpl) unusedPayloadImages(tx *gorm.DB, change *entity.ChangeRequest) ([]string, error) {
+	var payload struct {
+		Image string `json:"image"`
+	}
+
+	if err := json.Unmarshal([]byte(change.Payload), &payload); err != nil {
+		return nil, err
+	}
+
+	return unusedImages(tx, changeRequestUsecase.ContentRepo, changeRequestUsecase.AnnouncementRepo, []string{payload.Image})
+}
+
+// queueChange stores request as a pending change and commits tx. It returns
+// the ChangePendingError to hand back to the caller.
+func queueChange(tx *gorm.DB, changeRequestRepo repository.ChangeRequestRepository, change *entity.ChangeRequest, request any, requester *entity.Admin) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		log.Println("failed to marshal change request : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	change.Payload = string(payload)
+	change.Status = "pending"
+	change.RequestedBy = requester.ID
+
+	if err := changeRequestRepo.Create(tx, change); err != nil {
+		log.Println("failed when create repo change request : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	change.Requester = *requester
+
+	log.Printf("success queue %s %s from usecase change request", change.ItemType, change.Action)
+	return &ChangePendingError{Change: converter.ChangeRequestToResponse(change)}
+}
+
+func changeTypeError() error {
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: []string{"type must be one of content, announcement, content_translation or announcement_translation"},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+func changeNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Change request was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id change request usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id change request usecase:", err)
+	return fiber.ErrInternalServerError
+}
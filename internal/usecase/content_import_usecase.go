@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// maxImportRows keeps a single import within one reasonably sized transaction.
+const maxImportRows = 5000
+
+type ContentImportUsecase interface {
+	Import(ctx context.Context, request *model.ContentImportRequest) (*model.ContentImportResponse, error)
+}
+
+type ContentImportUsecaseImpl struct {
+	ContentRepo           repository.ContentRepository
+	AdminRepo             repository.AdminRepository
+	RelatedContentUsecase RelatedContentUsecase
+	WebhookUsecase        WebhookUsecase
+	DB                    *gorm.DB
+	Validate              *validator.Validate
+}
+
+func NewContentImportUsecase(contentRepo repository.ContentRepository, adminRepo repository.AdminRepository, relatedContentUsecase RelatedContentUsecase, webhookUsecase WebhookUsecase, DB *gorm.DB, validate *validator.Validate) ContentImportUsecase {
+	return &ContentImportUsecaseImpl{
+		ContentRepo:           contentRepo,
+		AdminRepo:             adminRepo,
+		RelatedContentUsecase: relatedContentUsecase,
+		WebhookUsecase:        webhookUsecase,
+		DB:                    DB,
+		Validate:              validate,
+	}
+}
+
+// Import implements ContentImportUsecase.
+func (contentImportUsecase *ContentImportUsecaseImpl) Import(ctx context.Context, request *model.ContentImportRequest) (*model.ContentImportResponse, error) {
+	tx := contentImportUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	errorResponse := &model.ErrorResponse{}
+
+	if err := contentImportUsecase.Validate.Struct(request); err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = validationErrors
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error import content : ", err)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if len(request.Rows) < 2 {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = []string{"the file must contain a header row and at least one data row"}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error import content : empty file")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if len(request.Rows)-1 > maxImportRows {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = []string{fmt.Sprintf("the file must not contain more than %d data rows", maxImportRows)}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error import content : too many rows")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	admin := &entity.Admin{
+		ID: request.CreatedBy,
+	}
+
+	if err := contentImportUsecase.AdminRepo.FindById(tx, admin); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorResponse := model.ErrorResponse{
+				Message: "Admin data was not found",
+				Details: []string{},
+			}
+
+			jsonString, _ := json.Marshal(errorResponse)
+
+			log.Println("error import content usecase : ", err)
+
+			return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+		} else {
+			log.Println("Error import content usecase:", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	// imported rows go live at once, there is no review for them
+	if !canPublish(admin.Role) {
+		return nil, forbiddenError("only publishers can import contents")
+	}
+
+	columns := importColumns(request.Rows[0], request.Mapping)
+
+	response := &model.ContentImportResponse{
+		Mode:      request.Mode,
+		DryRun:    request.DryRun,
+		TotalRows: len(request.Rows) - 1,
+		Errors:    []model.ContentImportRowResult{},
+	}
+
+	var contents []*entity.Content
+	for index, row := range request.Rows[1:] {
+		// row numbers follow the spreadsheet, the header being row 1
+		rowNumber := index + 2
+
+		createRequest, details := importRowToRequest(row, columns, request.DefaultImage, request.CreatedBy)
+		details = append(details, contentImportUsecase.validateRow(createRequest)...)
+
+		if len(details) > 0 {
+			response.Errors = append(response.Errors, model.ContentImportRowResult{Row: rowNumber, Details: details})
+			continue
+		}
+
+		contents = append(contents, newContentFromCreateRequest(createRequest))
+	}
+
+	response.ValidRows = len(contents)
+	response.Skipped = response.TotalRows - response.ValidRows
+
+	if request.DryRun || len(contents) == 0 || (request.Mode == "atomic" && len(response.Errors) > 0) {
+		log.Println("success import content without insert from usecase content import")
+		return response, nil
+	}
+
+	for _, content := range contents {
+		if err := contentImportUsecase.ContentRepo.Create(tx, content); err != nil {
+			log.Println("failed when create repo content : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for _, content := range contents {
+		contentImportUsecase.RelatedContentUsecase.Invalidate(content.ID)
+		contentImportUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventContentCreated, converter.ContentToResponse(content))
+	}
+
+	response.Imported = len(contents)
+
+	log.Println("success import from usecase content import")
+
+	return response, nil
+}
+
+// validateRow applies the rules of POST /api/contents to one imported row.
+func (contentImportUsecase *ContentImportUsecaseImpl) validateRow(request *model.ContentCreateRequest) []string {
+	request.ContactInfo = normalizeContactInfo(request.ContactInfo)
+
+	details := normalizeContentContacts(request.Contacts)
+
+	if err := contentImportUsecase.Validate.Struct(request); err != nil {
+		for _, e := range err.(validator.ValidationErrors) {
+			details = append(details, fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag()))
+		}
+	}
+
+	if request.IsFree && len(request.Prices) > 0 {
+		details = append(details, "Field 'Prices' must be empty when 'IsFree' is set")
+	}
+
+	return details
+}
+
+// importColumns resolves the field of every header cell. The mapping goes
+// from the spreadsheet header to the field name, headers that are not mapped
+// are used as field names themselves.
+func importColumns(header []string, mapping map[string]string) []string {
+	normalized := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		normalized[normalizeImportHeader(column)] = normalizeImportHeader(field)
+	}
+
+	columns := make([]string, len(header))
+	for index, cell := range header {
+		column := normalizeImportHeader(cell)
+		if field, ok := normalized[column]; ok {
+			column = field
+		}
+		columns[index] = column
+	}
+
+	return columns
+}
+
+func normalizeImportHeader(value string) string {
+	value = strings.TrimPrefix(value, "\ufeff")
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// importRowToRequest builds the create request of one data row. Prices come
+// from "price_<tier>" columns, e.g. price_adult or price_foreign_tourist, and
+// contacts from columns named after their type, e.g. whatsapp or instagram.
+func importRowToRequest(row []string, columns []string, defaultImage string, createdBy uint) (*model.ContentCreateRequest, []string) {
+	request := &model.ContentCreateRequest{
+		Image:     defaultImage,
+		CreatedBy: createdBy,
+	}
+
+	var details []string
+	invalid := func(column string) {
+		details = append(details, fmt.Sprintf("Column '%s' has an invalid value", column))
+	}
+
+	for index, column := range columns {
+		if index >= len(row) {
+			break
+		}
+
+		value := strings.TrimSpace(row[index])
+		if value == "" {
+			continue
+		}
+
+		switch column {
+		case "title":
+			request.Title = value
+		case "description", "content":
+			request.Content = value
+		case "content_format":
+			request.ContentFormat = value
+		case "image":
+			request.Image = value
+		case "address":
+			request.Address = value
+		case "contact_info":
+			request.ContactInfo = value
+		case util.ContactPhone, util.ContactWhatsApp, util.ContactEmail, util.ContactInstagram, util.ContactFacebook, util.ContactWebsite:
+			request.Contacts = append(request.Contacts, model.ContentContactRequest{Type: column, Value: value})
+		case "category":
+			request.Category = strings.ToLower(value)
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					request.Tags = append(request.Tags, tag)
+				}
+			}
+		case "latitude":
+			lat, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.Latitude = &lat
+		case "longitude":
+			lng, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.Longitude = &lng
+		case "is_free":
+			isFree, err := strconv.ParseBool(value)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.IsFree = isFree
+		case "is_featured":
+			isFeatured, err := strconv.ParseBool(value)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.IsFeatured = isFeatured
+		case "position":
+			position, err := strconv.Atoi(value)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.Position = position
+		case "currency":
+			request.Currency = value
+		case "price_level":
+			level, err := strconv.ParseUint(value, 10, 8)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.PriceLevel = uint8(level)
+		default:
+			tier, ok := strings.CutPrefix(column, "price_")
+			if !ok {
+				continue
+			}
+
+			amount, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				invalid(column)
+				continue
+			}
+			request.Prices = append(request.Prices, model.ContentPriceRequest{Tier: tier, Amount: amount})
+		}
+	}
+
+	return request, details
+}
@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ContentTranslationUsecase interface {
+	Create(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error)
+	Update(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error)
+	Delete(ctx context.Context, contentId uint, locale string, actorId uint) error
+	FindAll(ctx context.Context, contentId uint) (*model.TranslationListResponse, error)
+}
+
+// ContentTranslationUsecaseImpl manages the translations of a content.
+// Translated text is published like the content itself: editors of the
+// content may propose it, publishers have to approve it.
+type ContentTranslationUsecaseImpl struct {
+	ContentTranslationRepo repository.ContentTranslationRepository
+	ContentRepo            repository.ContentRepository
+	ChangeRequestRepo      repository.ChangeRequestRepository
+	ItemEditorRepo         repository.ItemEditorRepository
+	AdminRepo              repository.AdminRepository
+	RelatedContentUsecase  RelatedContentUsecase
+	DB                     *gorm.DB
+	Validate               *validator.Validate
+}
+
+func NewContentTranslationUsecase(contentTranslationRepo repository.ContentTranslationRepository, contentRepo repository.ContentRepository, changeRequestRepo repository.ChangeRequestRepository, itemEditorRepo repository.ItemEditorRepository, adminRepo repository.AdminRepository, relatedContentUsecase RelatedContentUsecase, DB *gorm.DB, validate *validator.Validate) ContentTranslationUsecase {
+	return &ContentTranslationUsecaseImpl{
+		ContentTranslationRepo: contentTranslationRepo,
+		ContentRepo:            contentRepo,
+		ChangeRequestRepo:      changeRequestRepo,
+		ItemEditorRepo:         itemEditorRepo,
+		AdminRepo:              adminRepo,
+		RelatedContentUsecase:  relatedContentUsecase,
+		DB:                     DB,
+		Validate:               validate,
+	}
+}
+
+// Create implements ContentTranslationUsecase.
+func (contentTranslationUsecase *ContentTranslationUsecaseImpl) Create(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error) {
+	tx := begin(ctx, contentTranslationUsecase.DB)
+	defer tx.Rollback()
+
+	if err := validateTranslationRequest(contentTranslationUsecase.Validate, request); err != nil {
+		log.Println("error create content translation : ", err)
+		return nil, err
+	}
+
+	actor, err := contentTranslationUsecase.findTranslator(tx, request.ItemID, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &entity.ContentTranslation{
+		ContentID: request.ItemID,
+		Locale:    request.Locale,
+	}
+
+	if err := contentTranslationUsecase.ContentTranslationRepo.FindByLocale(tx, translation); err == nil {
+		errorResponse := model.ErrorResponse{
+			Message: "Duplicate entry",
+			Details: []string{fmt.Sprintf("translation '%s' already exists for this content.", request.Locale)},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrConflict.Code, string(jsonString))
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType: ChangeItemContentTranslation,
+			ItemID:   &request.ItemID,
+			Action:   ChangeActionCreate,
+			Title:    request.Title,
+		}
+
+		return nil, queueChange(tx, contentTranslationUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
+	translation.Title = request.Title
+	translation.ContentFormat, translation.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+
+	if err := contentTranslationUsecase.ContentTranslationRepo.Create(tx, translation); err != nil {
+		log.Println("failed when create repo content translation : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// related contents are cached per locale with the translated text
+	afterCommit(ctx, func() {
+		contentTranslationUsecase.RelatedContentUsecase.Invalidate(request.ItemID)
+	})
+
+	log.Println("success create from usecase content translation")
+	return converter.ContentTranslationToResponse(translation), nil
+}
+
+// Update implements ContentTranslationUsecase.
+func (contentTranslationUsecase *ContentTranslationUsecaseImpl) Update(ctx context.Context, request *model.TranslationRequest) (*model.TranslationResponse, error) {
+	tx := begin(ctx, contentTranslationUsecase.DB)
+	defer tx.Rollback()
+
+	if err := validateTranslationRequest(contentTranslationUsecase.Validate, request); err != nil {
+		log.Println("error update content translation : ", err)
+		return nil, err
+	}
+
+	actor, err := contentTranslationUsecase.findTranslator(tx, request.ItemID, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	translation := &entity.ContentTranslation{
+		ContentID: request.ItemID,
+		Locale:    request.Locale,
+	}
+
+	if err := contentTranslationUsecase.ContentTranslationRepo.FindByLocale(tx, translation); err != nil {
+		return nil, translationNotFoundError(err)
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType: ChangeItemContentTranslation,
+			ItemID:   &request.ItemID,
+			Action:   ChangeActionUpdate,
+			Title:    request.Title,
+		}
+
+		return nil, queueChange(tx, contentTranslationUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
+	translation.Title = request.Title
+	translation.ContentFormat, translation.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+
+	if err := contentTranslationUsecase.ContentTranslationRepo.Update(tx, translation); err != nil {
+		log.Println("failed when update repo content translation : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	afterCommit(ctx, func() {
+		contentTranslationUsecase.RelatedContentUsecase.Invalidate(request.ItemID)
+	})
+
+	log.Println("success update from usecase content translation")
+	return converter.ContentTranslationToResponse(translation), nil
+}
+
+// Delete implements ContentTranslationUsecase.
+func (contentTranslationUsecase *ContentTranslationUsecaseImpl) Delete(ctx context.Context, contentId uint, locale string, actorId uint) error {
+	tx := begin(ctx, contentTranslationUsecase.DB)
+	defer tx.Rollback()
+
+	if _, err := contentTranslationUsecase.findTranslator(tx, contentId, actorId); err != nil {
+		return err
+	}
+
+	translation := &entity.ContentTranslation{
+		ContentID: contentId,
+		Locale:    locale,
+	}
+
+	if err := contentTranslationUsecase.ContentTranslationRepo.FindByLocale(tx, translation); err != nil {
+		return translationNotFoundError(err)
+	}
+
+	if err := contentTranslationUsecase.ContentTranslationRepo.Delete(tx, translation); err != nil {
+		log.Println("failed when delete repo content translation : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	afterCommit(ctx, func() {
+		contentTranslationUsecase.RelatedContentUsecase.Invalidate(contentId)
+	})
+
+	log.Println("success delete from usecase content translation")
+	return nil
+}
+
+// FindAll implements ContentTranslationUsecase.
+func (contentTranslationUsecase *ContentTranslationUsecaseImpl) FindAll(ctx context.Context, contentId uint) (*model.TranslationListResponse, error) {
+	tx := contentTranslationUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := contentTranslationUsecase.ContentRepo.FindById(tx, &entity.Content{ID: contentId}); err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	translations := &[]entity.ContentTranslation{}
+	if err := contentTranslationUsecase.ContentTranslationRepo.FindAllByContentId(tx, contentId, translations); err != nil {
+		log.Println("failed when find all repo content translation : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase content translation")
+	return converter.ContentTranslationsToResponse(translations), nil
+}
+
+// findTranslator loads the actor, who has to own or co-edit the content the
+// translation belongs to.
+func (contentTranslationUsecase *ContentTranslationUsecaseImpl) findTranslator(tx *gorm.DB, contentId uint, actorId uint) (*entity.Admin, error) {
+	content := &entity.Content{ID: contentId}
+	if err := contentTranslationUsecase.ContentRepo.FindById(tx, content); err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	actor, err := findActor(tx, contentTranslationUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOwnership(tx, contentTranslationUsecase.ItemEditorRepo, actor, EditorItemContent, content.ID, content.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	return actor, nil
+}
+
+func translationNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Translation data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find translation usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find translation usecase:", err)
+	return fiber.ErrInternalServerError
+}
+
+// validateTranslationRequest checks the request fields and that the locale is
+// a supported one other than the default, which is stored on the item itself.
+func validateTranslationRequest(validate *validator.Validate, request *model.TranslationRequest) error {
+	var validationErrors []string
+
+	if err := validate.Struct(request); err != nil {
+		for _, e := range err.(validator.ValidationErrors) {
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+	}
+
+	if !util.IsSupportedLocale(request.Locale) || request.Locale == util.DefaultLocale {
+		validationErrors = append(validationErrors, fmt.Sprintf("Locale '%s' is not a translatable locale", request.Locale))
+	}
+
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: validationErrors,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
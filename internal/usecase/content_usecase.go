@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/Bangdams/web-profile-API/internal/entity"
 	"github.com/Bangdams/web-profile-API/internal/model"
 	"github.com/Bangdams/web-profile-API/internal/model/converter"
 	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -20,35 +22,58 @@ import (
 type ContentUsecase interface {
 	Create(ctx context.Context, request *model.ContentCreateRequest) (*model.ContentResponse, error)
 	Update(ctx context.Context, request *model.ContentUpdateRequest) (*model.ContentResponse, error)
-	Delete(ctx context.Context, contentId uint) error
-	FindAll(ctx context.Context, order string, category string) (*[]model.ContentResponse, error)
-	FindWithLimit(ctx context.Context, order string, category string) (*[]model.ContentResponse, error)
-	FindById(ctx context.Context, contentId uint) (*model.ContentResponse, error)
+	Patch(ctx context.Context, request *model.PatchRequest) (*model.ContentResponse, error)
+	Delete(ctx context.Context, contentId uint, version uint, actorId uint) error
+	FindAll(ctx context.Context, request *model.ContentFilterRequest) (*[]model.ContentResponse, error)
+	FindWithLimit(ctx context.Context, request *model.ContentFilterRequest) (*[]model.ContentResponse, error)
+	FindById(ctx context.Context, contentId uint, locale string) (*model.ContentResponse, error)
+	FindVCard(ctx context.Context, contentId uint) (string, error)
+	FindFeatured(ctx context.Context, locale string) (map[string][]model.ContentResponse, error)
+	Reorder(ctx context.Context, request *model.ContentReorderRequest) error
+	FindPopular(ctx context.Context, period string, category string, locale string) (*[]model.ContentResponse, error)
 }
 
 type ContentUsecaseImpl struct {
-	ContentRepo repository.ContentRepository
-	AdminRepo   repository.AdminRepository
-	DB          *gorm.DB
-	Validate    *validator.Validate
+	ContentRepo           repository.ContentRepository
+	ContentPriceRepo      repository.ContentPriceRepository
+	ContentTagRepo        repository.ContentTagRepository
+	ContentContactRepo    repository.ContentContactRepository
+	ChangeRequestRepo     repository.ChangeRequestRepository
+	ItemEditorRepo        repository.ItemEditorRepository
+	AdminRepo             repository.AdminRepository
+	RelatedContentUsecase RelatedContentUsecase
+	NewsletterUsecase     NewsletterUsecase
+	WebhookUsecase        WebhookUsecase
+	DB                    *gorm.DB
+	Validate              *validator.Validate
 }
 
-func NewContentUsecase(contentRepo repository.ContentRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) ContentUsecase {
+func NewContentUsecase(contentRepo repository.ContentRepository, contentPriceRepo repository.ContentPriceRepository, contentTagRepo repository.ContentTagRepository, contentContactRepo repository.ContentContactRepository, changeRequestRepo repository.ChangeRequestRepository, itemEditorRepo repository.ItemEditorRepository, adminRepo repository.AdminRepository, relatedContentUsecase RelatedContentUsecase, newsletterUsecase NewsletterUsecase, webhookUsecase WebhookUsecase, DB *gorm.DB, validate *validator.Validate) ContentUsecase {
 	return &ContentUsecaseImpl{
-		ContentRepo: contentRepo,
-		AdminRepo:   adminRepo,
-		DB:          DB,
-		Validate:    validate,
+		ContentRepo:           contentRepo,
+		ContentPriceRepo:      contentPriceRepo,
+		ContentTagRepo:        contentTagRepo,
+		ContentContactRepo:    contentContactRepo,
+		ChangeRequestRepo:     changeRequestRepo,
+		ItemEditorRepo:        itemEditorRepo,
+		AdminRepo:             adminRepo,
+		RelatedContentUsecase: relatedContentUsecase,
+		NewsletterUsecase:     newsletterUsecase,
+		WebhookUsecase:        webhookUsecase,
+		DB:                    DB,
+		Validate:              validate,
 	}
 }
 
 // Create implements ContentUsecase.
 func (contentUsecase *ContentUsecaseImpl) Create(ctx context.Context, request *model.ContentCreateRequest) (*model.ContentResponse, error) {
-	tx := contentUsecase.DB.WithContext(ctx).Begin()
+	tx := begin(ctx, contentUsecase.DB)
 	defer tx.Rollback()
 
 	errorResponse := &model.ErrorResponse{}
 
+	request.ContactInfo = normalizeContactInfo(request.ContactInfo)
+
 	err := contentUsecase.Validate.Struct(request)
 	if err != nil {
 		var validationErrors []string
@@ -67,16 +92,50 @@ func (contentUsecase *ContentUsecaseImpl) Create(ctx context.Context, request *m
 		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
 	}
 
-	content := &entity.Content{
-		Title:       request.Title,
-		Content:     request.Content,
-		Image:       request.Image,
-		Address:     request.Address,
-		ContactInfo: request.ContactInfo,
-		Category:    request.Category,
-		CreatedBy:   request.CreatedBy,
+	if request.IsFree && len(request.Prices) > 0 {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = []string{"Field 'Prices' must be empty when 'IsFree' is set"}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error create content : free content with prices")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if details := normalizeContentContacts(request.Contacts); len(details) > 0 {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = details
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error create content : invalid contacts")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	actor, err := findActor(tx, contentUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// editors create items in their own name only
+	if err := checkOwnerChange(actor, actor.ID, request.CreatedBy); err != nil {
+		return nil, err
 	}
 
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType: ChangeItemContent,
+			Action:   ChangeActionCreate,
+			Title:    request.Title,
+		}
+
+		return nil, queueChange(tx, contentUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
+	content := newContentFromCreateRequest(request)
+
 	if err := contentUsecase.ContentRepo.Create(tx, content); err != nil {
 		log.Println("failed when create repo content : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -111,13 +170,21 @@ func (contentUsecase *ContentUsecaseImpl) Create(ctx context.Context, request *m
 		return nil, fiber.ErrInternalServerError
 	}
 
+	response := converter.ContentToResponse(content)
+
+	afterCommit(ctx, func() {
+		contentUsecase.RelatedContentUsecase.Invalidate(content.ID)
+		contentUsecase.NewsletterUsecase.Notify()
+		contentUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventContentCreated, response)
+	})
+
 	log.Println("success create from usecase content")
-	return converter.ContentToResponse(content), nil
+	return response, nil
 }
 
 // Delete implements ContentUsecase.
-func (contentUsecase *ContentUsecaseImpl) Delete(ctx context.Context, contentId uint) error {
-	tx := contentUsecase.DB.WithContext(ctx).Begin()
+func (contentUsecase *ContentUsecaseImpl) Delete(ctx context.Context, contentId uint, version uint, actorId uint) error {
+	tx := begin(ctx, contentUsecase.DB)
 	defer tx.Rollback()
 
 	content := &entity.Content{
@@ -142,6 +209,26 @@ func (contentUsecase *ContentUsecaseImpl) Delete(ctx context.Context, contentId
 		return fiber.ErrInternalServerError
 	}
 
+	actor, err := findActor(tx, contentUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	if err := checkOwnership(tx, contentUsecase.ItemEditorRepo, actor, EditorItemContent, content.ID, content.CreatedBy); err != nil {
+		return err
+	}
+
+	currentVersion, err := contentUsecase.ContentRepo.LockVersion(tx, content.ID)
+	if err != nil {
+		log.Println("error delete content : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := checkVersion(currentVersion, version); err != nil {
+		log.Println("error delete content : ", err)
+		return err
+	}
+
 	err = contentUsecase.ContentRepo.Delete(tx, content)
 	if err != nil {
 		log.Println("failed when delete repo content : ", err)
@@ -153,24 +240,28 @@ func (contentUsecase *ContentUsecaseImpl) Delete(ctx context.Context, contentId
 		return fiber.ErrInternalServerError
 	}
 
+	afterCommit(ctx, func() {
+		contentUsecase.RelatedContentUsecase.Invalidate(content.ID)
+		contentUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventContentDeleted, map[string]any{
+			"id":       content.ID,
+			"category": content.Category,
+		})
+	})
+
 	log.Println("success delete from usecase content")
 
 	return nil
 }
 
 // FindAll implements ContentUsecase.
-func (contentUsecase *ContentUsecaseImpl) FindAll(ctx context.Context, order string, category string) (*[]model.ContentResponse, error) {
+func (contentUsecase *ContentUsecaseImpl) FindAll(ctx context.Context, request *model.ContentFilterRequest) (*[]model.ContentResponse, error) {
 	tx := contentUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	var contents = &[]entity.Content{}
-	category = strings.ToLower(category)
-
-	if category != "wisata" && category != "kuliner" && category != "kerajinan" {
-		category = ""
-	}
+	normalizeContentFilter(request)
 
-	err := contentUsecase.ContentRepo.FindAll(tx, strings.ToUpper(order), category, contents)
+	err := contentUsecase.ContentRepo.FindAll(tx, request, contents)
 	if err != nil {
 		log.Println("failed when find all repo content : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -181,23 +272,23 @@ func (contentUsecase *ContentUsecaseImpl) FindAll(ctx context.Context, order str
 		return nil, fiber.ErrInternalServerError
 	}
 
+	for i := range *contents {
+		localizeContent(&(*contents)[i], request.Locale)
+	}
+
 	log.Println("success find all from usecase content")
 	return converter.ContentToResponses(contents), nil
 }
 
 // FindWithLimit implements ContentUsecase.
-func (contentUsecase *ContentUsecaseImpl) FindWithLimit(ctx context.Context, order string, category string) (*[]model.ContentResponse, error) {
+func (contentUsecase *ContentUsecaseImpl) FindWithLimit(ctx context.Context, request *model.ContentFilterRequest) (*[]model.ContentResponse, error) {
 	tx := contentUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
 	var contents = &[]entity.Content{}
-	category = strings.ToLower(category)
+	normalizeContentFilter(request)
 
-	if category != "wisata" && category != "kuliner" && category != "kerajinan" {
-		category = ""
-	}
-
-	err := contentUsecase.ContentRepo.FindWithLimit(tx, strings.ToUpper(order), category, contents)
+	err := contentUsecase.ContentRepo.FindWithLimit(tx, request, contents)
 	if err != nil {
 		log.Println("failed when FindWithLimit repo content : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -208,12 +299,16 @@ func (contentUsecase *ContentUsecaseImpl) FindWithLimit(ctx context.Context, ord
 		return nil, fiber.ErrInternalServerError
 	}
 
+	for i := range *contents {
+		localizeContent(&(*contents)[i], request.Locale)
+	}
+
 	log.Println("success find FindWithLimitall from usecase content")
 	return converter.ContentToResponses(contents), nil
 }
 
 // FindById implements ContentUsecase.
-func (contentUsecase *ContentUsecaseImpl) FindById(ctx context.Context, contentId uint) (*model.ContentResponse, error) {
+func (contentUsecase *ContentUsecaseImpl) FindById(ctx context.Context, contentId uint, locale string) (*model.ContentResponse, error) {
 	tx := contentUsecase.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -242,16 +337,187 @@ func (contentUsecase *ContentUsecaseImpl) FindById(ctx context.Context, contentI
 		log.Println("Failed commit transaction : ", err)
 		return nil, fiber.ErrInternalServerError
 	}
+
+	localizeContent(content, locale)
 	return converter.ContentToResponse(content), nil
 }
 
+// FindVCard implements ContentUsecase.
+func (contentUsecase *ContentUsecaseImpl) FindVCard(ctx context.Context, contentId uint) (string, error) {
+	tx := contentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	content := new(entity.Content)
+	content.ID = contentId
+
+	if err := contentUsecase.ContentRepo.FindById(tx, content); err != nil {
+		return "", contentNotFoundError(err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return "", fiber.ErrInternalServerError
+	}
+
+	log.Println("success find vcard from usecase content")
+	return converter.ContentToVCard(content).String(), nil
+}
+
+// FindFeatured implements ContentUsecase.
+func (contentUsecase *ContentUsecaseImpl) FindFeatured(ctx context.Context, locale string) (map[string][]model.ContentResponse, error) {
+	tx := contentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var contents = &[]entity.Content{}
+	if err := contentUsecase.ContentRepo.FindFeatured(tx, contents); err != nil {
+		log.Println("failed when find featured repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	responses := map[string][]model.ContentResponse{
+		"kuliner":   {},
+		"wisata":    {},
+		"kerajinan": {},
+	}
+
+	for i := range *contents {
+		content := &(*contents)[i]
+		localizeContent(content, locale)
+		responses[content.Category] = append(responses[content.Category], *converter.ContentToResponse(content))
+	}
+
+	log.Println("success find featured from usecase content")
+	return responses, nil
+}
+
+// FindPopular implements ContentUsecase.
+func (contentUsecase *ContentUsecaseImpl) FindPopular(ctx context.Context, period string, category string, locale string) (*[]model.ContentResponse, error) {
+	var since *time.Time
+
+	switch strings.ToLower(period) {
+	case "", "7d":
+		start := time.Now().AddDate(0, 0, -6)
+		since = &start
+	case "30d":
+		start := time.Now().AddDate(0, 0, -29)
+		since = &start
+	case "all":
+	default:
+		errorResponse := model.ErrorResponse{
+			Message: "invalid request parameter",
+			Details: []string{"period must be one of 7d, 30d or all"},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	tx := contentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	request := &model.ContentFilterRequest{Category: category}
+	normalizeContentFilter(request)
+
+	var contents = &[]entity.Content{}
+	if err := contentUsecase.ContentRepo.FindPopular(tx, since, request.Category, contents); err != nil {
+		log.Println("failed when find popular repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for i := range *contents {
+		localizeContent(&(*contents)[i], locale)
+	}
+
+	log.Println("success find popular from usecase content")
+	return converter.ContentToResponses(contents), nil
+}
+
+// Reorder implements ContentUsecase.
+func (contentUsecase *ContentUsecaseImpl) Reorder(ctx context.Context, request *model.ContentReorderRequest) error {
+	tx := contentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	errorResponse := &model.ErrorResponse{}
+
+	err := contentUsecase.Validate.Struct(request)
+	if err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = validationErrors
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error reorder content : ", err)
+
+		return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	// the order is the one of everyone's contents on the site
+	actor, err := findActor(tx, contentUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return err
+	}
+
+	if !canPublish(actor.Role) {
+		return forbiddenError("only publishers can reorder contents")
+	}
+
+	total, err := contentUsecase.ContentRepo.CountByIds(tx, request.IDs)
+	if err != nil {
+		log.Println("failed when count repo content : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if total != int64(len(request.IDs)) {
+		errorResponse.Message = "Content data was not found"
+		errorResponse.Details = []string{"one or more content ids do not exist"}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	if err := contentUsecase.ContentRepo.UpdatePositions(tx, request.IDs); err != nil {
+		log.Println("failed when update positions repo content : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	contentUsecase.RelatedContentUsecase.Invalidate(request.IDs...)
+
+	log.Println("success reorder from usecase content")
+	return nil
+}
+
 // Update implements ContentUsecase.
 func (contentUsecase *ContentUsecaseImpl) Update(ctx context.Context, request *model.ContentUpdateRequest) (*model.ContentResponse, error) {
-	tx := contentUsecase.DB.WithContext(ctx).Begin()
+	tx := begin(ctx, contentUsecase.DB)
 	defer tx.Rollback()
 
 	errorResponse := &model.ErrorResponse{}
 
+	request.ContactInfo = normalizeContactInfo(request.ContactInfo)
+
 	err := contentUsecase.Validate.Struct(request)
 	if err != nil {
 		var validationErrors []string
@@ -270,17 +536,113 @@ func (contentUsecase *ContentUsecaseImpl) Update(ctx context.Context, request *m
 		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
 	}
 
+	if request.IsFree && len(request.Prices) > 0 {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = []string{"Field 'Prices' must be empty when 'IsFree' is set"}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error update content : free content with prices")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if details := normalizeContentContacts(request.Contacts); len(details) > 0 {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = details
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error update content : invalid contacts")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	currentVersion, err := contentUsecase.ContentRepo.LockVersion(tx, request.ID)
+	if err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error update content : ", err)
+		return nil, err
+	}
+
+	actor, err := findActor(tx, contentUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &entity.Content{
+		ID: request.ID,
+	}
+
+	if err := contentUsecase.ContentRepo.FindById(tx, current); err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	if err := checkOwnership(tx, contentUsecase.ItemEditorRepo, actor, EditorItemContent, current.ID, current.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	if err := checkOwnerChange(actor, current.CreatedBy, request.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	if !canPublish(actor.Role) {
+		change := &entity.ChangeRequest{
+			ItemType:    ChangeItemContent,
+			ItemID:      &request.ID,
+			Action:      ChangeActionUpdate,
+			BaseVersion: currentVersion,
+			Title:       request.Title,
+		}
+
+		return nil, queueChange(tx, contentUsecase.ChangeRequestRepo, change, request, actor)
+	}
+
 	content := &entity.Content{
 		ID:          request.ID,
+		Version:     currentVersion + 1,
 		Title:       request.Title,
 		Content:     request.Content,
 		Image:       request.Image,
 		Address:     request.Address,
 		ContactInfo: request.ContactInfo,
+		Latitude:    request.Latitude,
+		Longitude:   request.Longitude,
 		Category:    request.Category,
 		CreatedBy:   request.CreatedBy,
 	}
 
+	// the rating comes from the reviews and the views from the visitors,
+	// not from the request
+	content.RatingAverage = current.RatingAverage
+	content.ReviewCount = current.ReviewCount
+	content.ViewCount = current.ViewCount
+	applyContentPlacement(content, current, request)
+
+	content.ContentFormat, content.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+	applyContentPricing(content, request.IsFree, request.Currency, request.PriceLevel, request.Prices)
+	applyContentTags(content, request.Tags)
+	applyContentContacts(content, request.Contacts)
+
+	// the price list is replaced as a whole on every update
+	if err := contentUsecase.ContentPriceRepo.DeleteByContentId(tx, content.ID); err != nil {
+		log.Println("failed when delete repo content price : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := contentUsecase.ContentTagRepo.DeleteByContentId(tx, content.ID); err != nil {
+		log.Println("failed when delete repo content tag : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := contentUsecase.ContentContactRepo.DeleteByContentId(tx, content.ID); err != nil {
+		log.Println("failed when delete repo content contact : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
 	if err := contentUsecase.ContentRepo.Update(tx, content); err != nil {
 		log.Println("failed when update repo content : ", err)
 		return nil, fiber.ErrInternalServerError
@@ -315,6 +677,459 @@ func (contentUsecase *ContentUsecaseImpl) Update(ctx context.Context, request *m
 		return nil, fiber.ErrInternalServerError
 	}
 
+	response := converter.ContentToResponse(content)
+
+	afterCommit(ctx, func() {
+		contentUsecase.RelatedContentUsecase.Invalidate(content.ID)
+		contentUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventContentUpdated, response)
+	})
+
 	log.Println("success update from usecase content")
-	return converter.ContentToResponse(content), nil
+	return response, nil
+}
+
+// Patch implements ContentUsecase.
+func (contentUsecase *ContentUsecaseImpl) Patch(ctx context.Context, request *model.PatchRequest) (*model.ContentResponse, error) {
+	tx := begin(ctx, contentUsecase.DB)
+	defer tx.Rollback()
+
+	currentVersion, err := contentUsecase.ContentRepo.LockVersion(tx, request.ID)
+	if err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error patch content : ", err)
+		return nil, err
+	}
+
+	content := &entity.Content{
+		ID: request.ID,
+	}
+
+	if err := contentUsecase.ContentRepo.FindById(tx, content); err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	actor, err := findActor(tx, contentUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// checked on the live row first, so that editors learn about missing
+	// rights before any validation error
+	if err := checkOwnership(tx, contentUsecase.ItemEditorRepo, actor, EditorItemContent, content.ID, content.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	updateRequest := contentToUpdateRequest(content)
+
+	members, err := applyMergePatch(contentUsecase.Validate, updateRequest, request.Patch,
+		func(updateRequest *model.ContentUpdateRequest) {
+			updateRequest.ContactInfo = normalizeContactInfo(updateRequest.ContactInfo)
+		},
+		[]string{"latitude", "longitude"},
+		[]string{"category", "price_level"},
+		[]string{"is_free", "prices"},
+		[]string{"contact_info", "contacts"},
+	)
+	if err != nil {
+		log.Println("error patch content : ", err)
+		return nil, err
+	}
+
+	if updateRequest.IsFree && len(updateRequest.Prices) > 0 {
+		errorResponse := model.ErrorResponse{
+			Message: "invalid request parameter",
+			Details: []string{"Field 'Prices' must be empty when 'IsFree' is set"},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error patch content : free content with prices")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if details := normalizeContentContacts(updateRequest.Contacts); len(details) > 0 {
+		errorResponse := model.ErrorResponse{
+			Message: "invalid request parameter",
+			Details: details,
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error patch content : invalid contacts")
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if err := checkOwnerChange(actor, content.CreatedBy, updateRequest.CreatedBy); err != nil {
+		return nil, err
+	}
+
+	// a patch is reviewed as an update to the whole patched state
+	if !canPublish(actor.Role) && len(members) > 0 {
+		change := &entity.ChangeRequest{
+			ItemType:    ChangeItemContent,
+			ItemID:      &content.ID,
+			Action:      ChangeActionUpdate,
+			BaseVersion: currentVersion,
+			Title:       updateRequest.Title,
+		}
+
+		return nil, queueChange(tx, contentUsecase.ChangeRequestRepo, change, updateRequest, actor)
+	}
+
+	patched := &entity.Content{
+		ID:          content.ID,
+		Version:     currentVersion + 1,
+		Title:       updateRequest.Title,
+		Image:       updateRequest.Image,
+		Address:     updateRequest.Address,
+		ContactInfo: updateRequest.ContactInfo,
+		Latitude:    updateRequest.Latitude,
+		Longitude:   updateRequest.Longitude,
+		Category:    updateRequest.Category,
+		CreatedBy:   updateRequest.CreatedBy,
+	}
+
+	applyContentPlacement(patched, content, updateRequest)
+
+	patched.ContentFormat, patched.Content = util.PrepareContentBody(updateRequest.ContentFormat, updateRequest.Content)
+	applyContentPricing(patched, updateRequest.IsFree, updateRequest.Currency, updateRequest.PriceLevel, updateRequest.Prices)
+	applyContentTags(patched, updateRequest.Tags)
+	applyContentContacts(patched, updateRequest.Contacts)
+
+	var columns []string
+	for _, member := range members {
+		switch member {
+		case "content", "content_format":
+			columns = append(columns, "content", "content_format")
+		case "is_free", "currency", "price_level", "prices":
+			columns = append(columns, "is_free", "currency", "price_level", "min_price", "max_price", "Prices")
+		case "tags":
+			columns = append(columns, "Tags")
+		case "contact_info", "contacts":
+			columns = append(columns, "contact_info", "Contacts")
+		default:
+			columns = append(columns, member)
+		}
+	}
+
+	if patchesAny(members, "created_by") {
+		admin := &entity.Admin{
+			ID: patched.CreatedBy,
+		}
+
+		if err := contentUsecase.AdminRepo.FindById(tx, admin); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				errorResponse := model.ErrorResponse{
+					Message: "Admin data was not found",
+					Details: []string{},
+				}
+
+				jsonString, _ := json.Marshal(errorResponse)
+
+				log.Println("error patch content usecase : ", err)
+
+				return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+			}
+
+			log.Println("Error patch content usecase:", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	// prices and tags are replaced as a whole, like on a full update
+	if patchesAny(members, "is_free", "currency", "price_level", "prices") {
+		if err := contentUsecase.ContentPriceRepo.DeleteByContentId(tx, content.ID); err != nil {
+			log.Println("failed when delete repo content price : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if patchesAny(members, "tags") {
+		if err := contentUsecase.ContentTagRepo.DeleteByContentId(tx, content.ID); err != nil {
+			log.Println("failed when delete repo content tag : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if patchesAny(members, "contact_info", "contacts") {
+		if err := contentUsecase.ContentContactRepo.DeleteByContentId(tx, content.ID); err != nil {
+			log.Println("failed when delete repo content contact : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if len(columns) > 0 {
+		columns = append(columns, "version")
+
+		if err := contentUsecase.ContentRepo.Patch(tx, patched, columns); err != nil {
+			log.Println("failed when patch repo content : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	content = &entity.Content{
+		ID: request.ID,
+	}
+
+	if err := contentUsecase.ContentRepo.FindById(tx, content); err != nil {
+		log.Println("failed when find by id repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	localizeContent(content, util.DefaultLocale)
+
+	response := converter.ContentToResponse(content)
+
+	afterCommit(ctx, func() {
+		contentUsecase.RelatedContentUsecase.Invalidate(content.ID)
+		contentUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventContentUpdated, response)
+	})
+
+	log.Println("success patch from usecase content")
+	return response, nil
+}
+
+// contentToUpdateRequest describes the stored content the way PUT would
+// receive it, as the document a merge patch applies to.
+func contentToUpdateRequest(content *entity.Content) *model.ContentUpdateRequest {
+	request := &model.ContentUpdateRequest{
+		ID:            content.ID,
+		Title:         content.Title,
+		Content:       content.Content,
+		ContentFormat: content.ContentFormat,
+		Image:         content.Image,
+		Address:       content.Address,
+		ContactInfo:   content.ContactInfo,
+		Category:      content.Category,
+		Tags:          converter.ContentToTags(content),
+		Contacts:      contentToContactRequests(content),
+		Latitude:      content.Latitude,
+		Longitude:     content.Longitude,
+		IsFree:        content.IsFree,
+		Currency:      content.Currency,
+		IsFeatured:    &content.IsFeatured,
+		Position:      &content.Position,
+		CreatedBy:     content.CreatedBy,
+	}
+
+	if content.PriceLevel != nil {
+		request.PriceLevel = *content.PriceLevel
+	}
+
+	for _, price := range content.Prices {
+		request.Prices = append(request.Prices, model.ContentPriceRequest{
+			Tier:   price.Tier,
+			Label:  price.Label,
+			Amount: price.Amount,
+		})
+	}
+
+	return request
+}
+
+func normalizeContentFilter(request *model.ContentFilterRequest) {
+	request.Order = strings.ToUpper(request.Order)
+	request.Sort = strings.ToLower(request.Sort)
+	request.Category = strings.ToLower(request.Category)
+
+	if request.Category != "wisata" && request.Category != "kuliner" && request.Category != "kerajinan" {
+		request.Category = ""
+	}
+}
+
+func newContentFromCreateRequest(request *model.ContentCreateRequest) *entity.Content {
+	content := &entity.Content{
+		Title:       request.Title,
+		Content:     request.Content,
+		Image:       request.Image,
+		Address:     request.Address,
+		ContactInfo: request.ContactInfo,
+		Latitude:    request.Latitude,
+		Longitude:   request.Longitude,
+		Category:    request.Category,
+		IsFeatured:  request.IsFeatured,
+		Position:    request.Position,
+		CreatedBy:   request.CreatedBy,
+		Version:     1,
+	}
+
+	content.ContentFormat, content.Content = util.PrepareContentBody(request.ContentFormat, request.Content)
+	applyContentPricing(content, request.IsFree, request.Currency, request.PriceLevel, request.Prices)
+	applyContentTags(content, request.Tags)
+	applyContentContacts(content, request.Contacts)
+
+	return content
+}
+
+// normalizeContactInfo converts the legacy single phone number to E.164 so
+// numbers written as 08... pass the e164 rule.
+func normalizeContactInfo(contactInfo string) string {
+	if contactInfo == "" {
+		return contactInfo
+	}
+
+	return util.NormalizePhoneNumber(contactInfo)
+}
+
+// normalizeContentContacts validates every contact channel against the rules
+// of its type and rewrites it in its stored form.
+func normalizeContentContacts(contacts []model.ContentContactRequest) []string {
+	var details []string
+
+	for i := range contacts {
+		value, err := util.NormalizeContact(contacts[i].Type, contacts[i].Value)
+		if err != nil {
+			details = append(details, fmt.Sprintf("Field 'Contacts[%d].Value' is not a valid %s contact", i, contacts[i].Type))
+			continue
+		}
+
+		contacts[i].Value = value
+	}
+
+	return details
+}
+
+// applyContentContacts keeps the contacts in the order they were sent. The
+// first phone or WhatsApp number also fills contact_info for older clients.
+func applyContentContacts(content *entity.Content, contacts []model.ContentContactRequest) {
+	for position, contact := range contacts {
+		content.Contacts = append(content.Contacts, entity.ContentContact{
+			Type:     contact.Type,
+			Value:    contact.Value,
+			Label:    contact.Label,
+			Position: position,
+		})
+
+		if content.ContactInfo == "" && (contact.Type == util.ContactPhone || contact.Type == util.ContactWhatsApp) {
+			content.ContactInfo = contact.Value
+		}
+	}
+}
+
+func contentToContactRequests(content *entity.Content) []model.ContentContactRequest {
+	var contacts []model.ContentContactRequest
+
+	for _, contact := range content.Contacts {
+		contacts = append(contacts, model.ContentContactRequest{
+			Type:  contact.Type,
+			Value: contact.Value,
+			Label: contact.Label,
+		})
+	}
+
+	return contacts
+}
+
+// applyContentPlacement takes over the featured flag and position of the
+// request, keeping those of current the request leaves out.
+func applyContentPlacement(content *entity.Content, current *entity.Content, request *model.ContentUpdateRequest) {
+	content.IsFeatured = current.IsFeatured
+	if request.IsFeatured != nil {
+		content.IsFeatured = *request.IsFeatured
+	}
+
+	content.Position = current.Position
+	if request.Position != nil {
+		content.Position = *request.Position
+	}
+}
+
+func applyContentTags(content *entity.Content, tags []string) {
+	seen := map[string]bool{}
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		content.Tags = append(content.Tags, entity.ContentTag{Tag: tag})
+	}
+}
+
+func applyContentPricing(content *entity.Content, isFree bool, currency string, priceLevel uint8, prices []model.ContentPriceRequest) {
+	content.IsFree = isFree
+	content.Currency = strings.ToUpper(currency)
+	if content.Currency == "" {
+		content.Currency = "IDR"
+	}
+
+	if priceLevel != 0 {
+		content.PriceLevel = &priceLevel
+	}
+
+	if isFree {
+		var free int64
+		content.MinPrice = &free
+		content.MaxPrice = &free
+		return
+	}
+
+	for _, price := range prices {
+		content.Prices = append(content.Prices, entity.ContentPrice{
+			Tier:   price.Tier,
+			Label:  price.Label,
+			Amount: price.Amount,
+		})
+
+		amount := price.Amount
+		if content.MinPrice == nil || amount < *content.MinPrice {
+			content.MinPrice = &amount
+		}
+
+		if content.MaxPrice == nil || amount > *content.MaxPrice {
+			content.MaxPrice = &amount
+		}
+	}
+}
+
+// localizeContent swaps the title and body for the first translation found
+// along the fallback chain of the requested locale.
+func localizeContent(content *entity.Content, locale string) {
+	content.Locale = util.DefaultLocale
+
+	for _, candidate := range util.LocaleFallbacks(locale) {
+		if candidate == util.DefaultLocale {
+			return
+		}
+
+		for _, translation := range content.Translations {
+			if translation.Locale == candidate {
+				content.Title = translation.Title
+				content.Content = translation.Content
+				content.ContentFormat = translation.ContentFormat
+				content.Locale = candidate
+				return
+			}
+		}
+	}
+}
+
+func contentNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Content data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id content usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id content usecase:", err)
+	return fiber.ErrInternalServerError
 }
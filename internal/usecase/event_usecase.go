@@ -0,0 +1,641 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/teambition/rrule-go"
+	"gorm.io/gorm"
+)
+
+const (
+	eventRangeDefault = 31 * 24 * time.Hour
+	eventRangeLimit   = 366 * 24 * time.Hour
+	// eventOccurrenceLimit caps how many occurrences a range query expands,
+	// so a daily event over a whole year cannot flood the response.
+	eventOccurrenceLimit = 1000
+)
+
+type EventUsecase interface {
+	Create(ctx context.Context, request *model.EventCreateRequest) (*model.EventResponse, error)
+	Update(ctx context.Context, request *model.EventUpdateRequest) (*model.EventResponse, error)
+	Delete(ctx context.Context, id uint, version uint, actorId uint) error
+	FindById(ctx context.Context, id uint) (*model.EventResponse, error)
+	FindAll(ctx context.Context) (*[]model.EventResponse, error)
+	FindRange(ctx context.Context, request *model.EventRangeRequest) (*[]model.EventResponse, error)
+	Calendar(ctx context.Context, request *model.CalendarRequest) (*model.FeedResponse, error)
+}
+
+// EventUsecaseImpl manages the village calendar. Events go live right away,
+// so only the roles that may publish can change them.
+type EventUsecaseImpl struct {
+	EventRepo   repository.EventRepository
+	ContentRepo repository.ContentRepository
+	AdminRepo   repository.AdminRepository
+	Newsletter  NewsletterUsecase
+	DB          *gorm.DB
+	Validate    *validator.Validate
+	SiteName    string
+}
+
+func NewEventUsecase(eventRepo repository.EventRepository, contentRepo repository.ContentRepository, adminRepo repository.AdminRepository, newsletterUsecase NewsletterUsecase, DB *gorm.DB, validate *validator.Validate, siteName string) EventUsecase {
+	if siteName == "" {
+		siteName = "Web Profile"
+	}
+
+	return &EventUsecaseImpl{
+		EventRepo:   eventRepo,
+		ContentRepo: contentRepo,
+		AdminRepo:   adminRepo,
+		Newsletter:  newsletterUsecase,
+		DB:          DB,
+		Validate:    validate,
+		SiteName:    siteName,
+	}
+}
+
+// Create implements EventUsecase.
+func (eventUsecase *EventUsecaseImpl) Create(ctx context.Context, request *model.EventCreateRequest) (*model.EventResponse, error) {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := eventUsecase.validate(request, "create"); err != nil {
+		return nil, err
+	}
+
+	actor, err := eventUsecase.findPublisher(tx, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &entity.Event{CreatedBy: actor.ID}
+	content, err := eventUsecase.applyRequest(tx, event, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := eventUsecase.EventRepo.Create(tx, event); err != nil {
+		log.Println("failed when create repo event : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	event.Admin = *actor
+	event.Content = content
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	eventUsecase.Newsletter.Notify()
+
+	log.Println("success create from usecase event")
+	return converter.EventToResponse(event), nil
+}
+
+// Update implements EventUsecase.
+func (eventUsecase *EventUsecaseImpl) Update(ctx context.Context, request *model.EventUpdateRequest) (*model.EventResponse, error) {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := eventUsecase.validate(request, "update"); err != nil {
+		return nil, err
+	}
+
+	currentVersion, err := eventUsecase.EventRepo.LockVersion(tx, request.ID)
+	if err != nil {
+		return nil, eventNotFoundError(err)
+	}
+
+	if err := checkVersion(currentVersion, request.Version); err != nil {
+		log.Println("error update event : ", err)
+		return nil, err
+	}
+
+	if _, err := eventUsecase.findPublisher(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	current := &entity.Event{ID: request.ID}
+	if err := eventUsecase.EventRepo.FindById(tx, current); err != nil {
+		return nil, eventNotFoundError(err)
+	}
+
+	event := &entity.Event{
+		ID:        current.ID,
+		CreatedBy: current.CreatedBy,
+		Version:   currentVersion + 1,
+		CreatedAt: current.CreatedAt,
+	}
+
+	content, err := eventUsecase.applyRequest(tx, event, &request.EventCreateRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := eventUsecase.EventRepo.Update(tx, event); err != nil {
+		log.Println("failed when update repo event : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	event.Admin = current.Admin
+	event.Content = content
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success update from usecase event")
+	return converter.EventToResponse(event), nil
+}
+
+// Delete implements EventUsecase.
+func (eventUsecase *EventUsecaseImpl) Delete(ctx context.Context, id uint, version uint, actorId uint) error {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	currentVersion, err := eventUsecase.EventRepo.LockVersion(tx, id)
+	if err != nil {
+		return eventNotFoundError(err)
+	}
+
+	if err := checkVersion(currentVersion, version); err != nil {
+		log.Println("error delete event : ", err)
+		return err
+	}
+
+	if _, err := eventUsecase.findPublisher(tx, actorId); err != nil {
+		return err
+	}
+
+	if err := eventUsecase.EventRepo.Delete(tx, &entity.Event{ID: id}); err != nil {
+		log.Println("failed when delete repo event : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success delete from usecase event")
+	return nil
+}
+
+// FindById implements EventUsecase.
+func (eventUsecase *EventUsecaseImpl) FindById(ctx context.Context, id uint) (*model.EventResponse, error) {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	event := &entity.Event{ID: id}
+	if err := eventUsecase.EventRepo.FindById(tx, event); err != nil {
+		return nil, eventNotFoundError(err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find by id from usecase event")
+	return converter.EventToResponse(event), nil
+}
+
+// FindAll implements EventUsecase.
+func (eventUsecase *EventUsecaseImpl) FindAll(ctx context.Context) (*[]model.EventResponse, error) {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var events = &[]entity.Event{}
+	if err := eventUsecase.EventRepo.FindAll(tx, events); err != nil {
+		log.Println("failed when find all repo event : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase event")
+	return converter.EventToResponses(events), nil
+}
+
+// FindRange implements EventUsecase.
+func (eventUsecase *EventUsecaseImpl) FindRange(ctx context.Context, request *model.EventRangeRequest) (*[]model.EventResponse, error) {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	from, to, err := parseEventRange(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var events = &[]entity.Event{}
+	if err := eventUsecase.EventRepo.FindBetween(tx, from, to, events); err != nil {
+		log.Println("failed when find between repo event : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	var occurrences []entity.Event
+	for _, event := range *events {
+		occurrences = append(occurrences, eventOccurrences(&event, from, to)...)
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartsAt.Before(occurrences[j].StartsAt)
+	})
+
+	if len(occurrences) > eventOccurrenceLimit {
+		occurrences = occurrences[:eventOccurrenceLimit]
+	}
+
+	log.Println("success find range from usecase event")
+	return converter.EventToResponses(&occurrences), nil
+}
+
+// Calendar implements EventUsecase. Recurring events are written with their
+// RRULE and left to the calendar app to expand.
+func (eventUsecase *EventUsecaseImpl) Calendar(ctx context.Context, request *model.CalendarRequest) (*model.FeedResponse, error) {
+	tx := eventUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var events = &[]entity.Event{}
+	name := eventUsecase.SiteName + " - Events"
+
+	if request.EventID != 0 {
+		event := entity.Event{ID: request.EventID}
+		if err := eventUsecase.EventRepo.FindById(tx, &event); err != nil {
+			return nil, eventNotFoundError(err)
+		}
+
+		*events = append(*events, event)
+		name = eventUsecase.SiteName + " - " + event.Title
+	} else if err := eventUsecase.EventRepo.FindAll(tx, events); err != nil {
+		log.Println("failed when find all repo event : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	host := "localhost"
+	if base, err := url.Parse(request.BaseURL); err == nil && base.Hostname() != "" {
+		host = base.Hostname()
+	}
+
+	response := &model.FeedResponse{ContentType: "text/calendar; charset=utf-8"}
+	calendarEvents := make([]util.ICalEvent, 0, len(*events))
+
+	for _, event := range *events {
+		location := event.Location
+		if location == "" && event.Content != nil {
+			location = event.Content.Address
+		}
+
+		calendarEvents = append(calendarEvents, util.ICalEvent{
+			UID:          fmt.Sprintf("event-%d@%s", event.ID, host),
+			Summary:      event.Title,
+			Description:  event.Description,
+			Location:     location,
+			URL:          fmt.Sprintf("%s/events/%d", strings.TrimRight(request.BaseURL, "/"), event.ID),
+			Start:        event.StartsAt,
+			End:          event.EndsAt,
+			AllDay:       event.AllDay,
+			RRule:        calendarRRule(&event),
+			Created:      event.CreatedAt,
+			LastModified: event.UpdatedAt,
+		})
+
+		if event.UpdatedAt.After(response.LastModified) {
+			response.LastModified = event.UpdatedAt
+		}
+	}
+
+	response.Body = util.ICalendar(name, calendarEvents)
+
+	log.Println("success calendar from usecase event")
+	return response, nil
+}
+
+func (eventUsecase *EventUsecaseImpl) validate(request any, action string) error {
+	err := eventUsecase.Validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors []string
+	for _, e := range err.(validator.ValidationErrors) {
+		msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+		validationErrors = append(validationErrors, msg)
+	}
+
+	log.Printf("error %s event : %v", action, err)
+
+	return invalidEventError(validationErrors...)
+}
+
+// findPublisher loads the actor and makes sure they may change events.
+func (eventUsecase *EventUsecaseImpl) findPublisher(tx *gorm.DB, actorId uint) (*entity.Admin, error) {
+	actor, err := findActor(tx, eventUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canPublish(actor.Role) {
+		log.Printf("error manage event : admin %d can not publish", actor.ID)
+		return nil, forbiddenError("only publishers can manage events")
+	}
+
+	return actor, nil
+}
+
+// applyRequest parses the dates and the recurrence of the request into
+// event and returns the linked content, which is kept off the event until it
+// is saved so the save does not write the content back.
+func (eventUsecase *EventUsecaseImpl) applyRequest(tx *gorm.DB, event *entity.Event, request *model.EventCreateRequest) (*entity.Content, error) {
+	startsAt, err := parseEventTime(request.StartsAt, request.AllDay)
+	if err != nil {
+		return nil, invalidEventError(fmt.Sprintf("Field 'StartsAt' %s", err))
+	}
+
+	endsAt, err := parseEventTime(request.EndsAt, request.AllDay)
+	if err != nil {
+		return nil, invalidEventError(fmt.Sprintf("Field 'EndsAt' %s", err))
+	}
+
+	if endsAt.Before(startsAt) || (!request.AllDay && endsAt.Equal(startsAt)) {
+		return nil, invalidEventError("Field 'EndsAt' must be after 'StartsAt'")
+	}
+
+	event.Title = strings.TrimSpace(request.Title)
+	event.Description = strings.TrimSpace(request.Description)
+	event.Location = strings.TrimSpace(request.Location)
+	event.StartsAt = startsAt
+	event.EndsAt = endsAt
+	event.AllDay = request.AllDay
+	event.ContentID = request.ContentID
+	event.RRule = ""
+
+	seriesEndsAt := eventEnd(event, startsAt)
+	if rule := strings.TrimSpace(request.RRule); rule != "" {
+		recurrence, err := parseEventRRule(rule, startsAt)
+		if err != nil {
+			return nil, invalidEventError(fmt.Sprintf("Field 'RRule' %s", err))
+		}
+
+		event.RRule = recurrence.OrigOptions.RRuleString()
+
+		// an UNTIL far away would take long to expand, so it only bounds
+		// the series; that is all the range queries need
+		switch option := recurrence.OrigOptions; {
+		case option.Count > 0:
+			if all := recurrence.All(); len(all) > 0 {
+				seriesEndsAt = eventEnd(event, all[len(all)-1])
+			}
+		case !option.Until.IsZero():
+			seriesEndsAt = eventEnd(event, option.Until)
+		default:
+			seriesEndsAt = time.Time{}
+		}
+	}
+
+	event.SeriesEndsAt = nil
+	if !seriesEndsAt.IsZero() {
+		event.SeriesEndsAt = &seriesEndsAt
+	}
+
+	if request.ContentID != nil {
+		content := &entity.Content{ID: *request.ContentID}
+		if err := eventUsecase.ContentRepo.FindById(tx, content); err != nil {
+			return nil, contentNotFoundError(err)
+		}
+
+		if content.Category != "wisata" {
+			return nil, invalidEventError("only wisata contents can be the location of an event")
+		}
+
+		return content, nil
+	}
+
+	return nil, nil
+}
+
+// parseEventTime reads a whole day for all-day events and a point in time
+// otherwise.
+func parseEventTime(value string, allDay bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if allDay {
+		if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+			return t, nil
+		}
+
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			t = t.In(time.Local)
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local), nil
+		}
+
+		return time.Time{}, errors.New("must be a date in the YYYY-MM-DD format")
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("must be a time in the RFC 3339 format")
+	}
+
+	return t.In(time.Local).Truncate(time.Second), nil
+}
+
+// parseEventRRule reads a recurrence rule, with or without the RRULE:
+// prefix, starting at start. Rules repeating more often than daily make no
+// sense on a village calendar and are refused.
+func parseEventRRule(rule string, start time.Time) (*rrule.RRule, error) {
+	if len(rule) > 6 && strings.EqualFold(rule[:6], "RRULE:") {
+		rule = rule[6:]
+	}
+
+	if strings.ContainsAny(rule, "\r\n") {
+		return nil, errors.New("must be a single RRULE without DTSTART")
+	}
+
+	option, err := rrule.StrToROptionInLocation(rule, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid recurrence rule: %v", err)
+	}
+
+	if option.Freq > rrule.DAILY {
+		return nil, errors.New("must not repeat more often than daily")
+	}
+
+	if option.Count > eventOccurrenceLimit {
+		return nil, fmt.Errorf("must not repeat more than %d times", eventOccurrenceLimit)
+	}
+
+	option.Dtstart = start
+
+	recurrence, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("is not a valid recurrence rule: %v", err)
+	}
+
+	if !option.Until.IsZero() && option.Until.Before(start) {
+		return nil, errors.New("must not end before the event starts")
+	}
+
+	return recurrence, nil
+}
+
+// parseEventRange reads the range of a query, defaulting to a month from
+// today, and refuses ranges longer than a year.
+func parseEventRange(request *model.EventRangeRequest) (time.Time, time.Time, error) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	if request.From != "" {
+		t, err := parseEventTime(request.From, !strings.Contains(request.From, "T"))
+		if err != nil {
+			return time.Time{}, time.Time{}, invalidEventError(fmt.Sprintf("Field 'From' %s", err))
+		}
+		from = t
+	}
+
+	to := from.Add(eventRangeDefault)
+	if request.To != "" {
+		allDay := !strings.Contains(request.To, "T")
+		t, err := parseEventTime(request.To, allDay)
+		if err != nil {
+			return time.Time{}, time.Time{}, invalidEventError(fmt.Sprintf("Field 'To' %s", err))
+		}
+
+		// a date includes the whole day
+		if allDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		to = t
+	}
+
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, invalidEventError("Field 'To' must be after 'From'")
+	}
+
+	if to.Sub(from) > eventRangeLimit {
+		return time.Time{}, time.Time{}, invalidEventError("the range must not be longer than a year")
+	}
+
+	return from, to, nil
+}
+
+// eventEnd is the exclusive end of the occurrence of event starting at start.
+func eventEnd(event *entity.Event, start time.Time) time.Time {
+	end := start.Add(event.EndsAt.Sub(event.StartsAt))
+	if event.AllDay {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return end
+}
+
+// eventOccurrences lists the occurrences of event overlapping [from, to),
+// each a copy of the event with its own start and end.
+func eventOccurrences(event *entity.Event, from time.Time, to time.Time) []entity.Event {
+	if event.RRule == "" {
+		if eventEnd(event, event.StartsAt).After(from) && event.StartsAt.Before(to) {
+			return []entity.Event{*event}
+		}
+
+		return nil
+	}
+
+	recurrence, err := parseEventRRule(event.RRule, event.StartsAt)
+	if err != nil {
+		log.Printf("error expand event %d : %v", event.ID, err)
+		return nil
+	}
+
+	duration := event.EndsAt.Sub(event.StartsAt)
+	lookBack := eventEnd(event, event.StartsAt).Sub(event.StartsAt)
+
+	var occurrences []entity.Event
+	for _, start := range recurrence.Between(from.Add(-lookBack), to, false) {
+		occurrence := *event
+		occurrence.StartsAt = start
+		occurrence.EndsAt = start.Add(duration)
+		occurrences = append(occurrences, occurrence)
+
+		if len(occurrences) >= eventOccurrenceLimit {
+			break
+		}
+	}
+
+	return occurrences
+}
+
+// calendarRRule writes the rule for iCalendar, where the UNTIL of an
+// all-day event has to be a date like its DTSTART.
+func calendarRRule(event *entity.Event) string {
+	if event.RRule == "" || !event.AllDay {
+		return event.RRule
+	}
+
+	option, err := rrule.StrToROptionInLocation(event.RRule, time.Local)
+	if err != nil || option.Until.IsZero() {
+		return event.RRule
+	}
+
+	until := option.Until.In(time.Local).Format("20060102")
+	option.Until = time.Time{}
+
+	return option.RRuleString() + ";UNTIL=" + until
+}
+
+func invalidEventError(details ...string) error {
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: details,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+func eventNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Event data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id event usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id event usecase:", err)
+	return fiber.ErrInternalServerError
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ExportFunc writes a whole export to the writer. It runs once the response
+// headers are sent, so it only reports errors to the log.
+type ExportFunc func(writer io.Writer) error
+
+type ExportUsecase interface {
+	ExportContents(ctx context.Context, request *model.ContentExportRequest) (ExportFunc, error)
+	ExportAnnouncements(ctx context.Context, request *model.AnnouncementExportRequest) (ExportFunc, error)
+}
+
+type ExportUsecaseImpl struct {
+	ContentRepo      repository.ContentRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	DB               *gorm.DB
+	Validate         *validator.Validate
+}
+
+func NewExportUsecase(contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, DB *gorm.DB, validate *validator.Validate) ExportUsecase {
+	return &ExportUsecaseImpl{
+		ContentRepo:      contentRepo,
+		AnnouncementRepo: announcementRepo,
+		DB:               DB,
+		Validate:         validate,
+	}
+}
+
+type exportColumn[T any] struct {
+	Name  string
+	Value func(row *T) any
+}
+
+var contentExportColumns = []exportColumn[model.ContentExportRow]{
+	{"id", func(row *model.ContentExportRow) any { return row.ID }},
+	{"title", func(row *model.ContentExportRow) any { return row.Title }},
+	{"content", func(row *model.ContentExportRow) any { return row.Content }},
+	{"content_format", func(row *model.ContentExportRow) any { return row.ContentFormat }},
+	{"image", func(row *model.ContentExportRow) any { return row.Image }},
+	{"address", func(row *model.ContentExportRow) any { return row.Address }},
+	{"contact_info", func(row *model.ContentExportRow) any { return row.ContactInfo }},
+	{"category", func(row *model.ContentExportRow) any { return row.Category }},
+	{"tags", func(row *model.ContentExportRow) any { return exportOptional(row.Tags) }},
+	{"latitude", func(row *model.ContentExportRow) any { return exportOptional(row.Latitude) }},
+	{"longitude", func(row *model.ContentExportRow) any { return exportOptional(row.Longitude) }},
+	{"is_free", func(row *model.ContentExportRow) any { return row.IsFree }},
+	{"currency", func(row *model.ContentExportRow) any { return row.Currency }},
+	{"price_level", func(row *model.ContentExportRow) any { return exportOptional(row.PriceLevel) }},
+	{"min_price", func(row *model.ContentExportRow) any { return exportOptional(row.MinPrice) }},
+	{"max_price", func(row *model.ContentExportRow) any { return exportOptional(row.MaxPrice) }},
+	{"rating_average", func(row *model.ContentExportRow) any { return row.RatingAverage }},
+	{"review_count", func(row *model.ContentExportRow) any { return row.ReviewCount }},
+	{"is_featured", func(row *model.ContentExportRow) any { return row.IsFeatured }},
+	{"position", func(row *model.ContentExportRow) any { return row.Position }},
+	{"view_count", func(row *model.ContentExportRow) any { return row.ViewCount }},
+	{"created_by", func(row *model.ContentExportRow) any { return exportOptional(row.CreatedByName) }},
+	{"created_at", func(row *model.ContentExportRow) any { return row.CreatedAt }},
+	{"updated_at", func(row *model.ContentExportRow) any { return row.UpdatedAt }},
+}
+
+var announcementExportColumns = []exportColumn[model.AnnouncementExportRow]{
+	{"id", func(row *model.AnnouncementExportRow) any { return row.ID }},
+	{"title", func(row *model.AnnouncementExportRow) any { return row.Title }},
+	{"content", func(row *model.AnnouncementExportRow) any { return row.Content }},
+	{"content_format", func(row *model.AnnouncementExportRow) any { return row.ContentFormat }},
+	{"image", func(row *model.AnnouncementExportRow) any { return row.Image }},
+	{"category", func(row *model.AnnouncementExportRow) any { return exportOptional(row.Category) }},
+	{"priority", func(row *model.AnnouncementExportRow) any { return row.Priority }},
+	{"valid_from", func(row *model.AnnouncementExportRow) any { return exportOptional(row.ValidFrom) }},
+	{"valid_until", func(row *model.AnnouncementExportRow) any { return exportOptional(row.ValidUntil) }},
+	{"pinned", func(row *model.AnnouncementExportRow) any { return row.Pinned }},
+	{"pinned_until", func(row *model.AnnouncementExportRow) any { return exportOptional(row.PinnedUntil) }},
+	{"view_count", func(row *model.AnnouncementExportRow) any { return row.ViewCount }},
+	{"published_by", func(row *model.AnnouncementExportRow) any { return exportOptional(row.PublishedByName) }},
+	{"created_at", func(row *model.AnnouncementExportRow) any { return row.CreatedAt }},
+	{"updated_at", func(row *model.AnnouncementExportRow) any { return row.UpdatedAt }},
+}
+
+// ExportContents implements ExportUsecase.
+func (exportUsecase *ExportUsecaseImpl) ExportContents(ctx context.Context, request *model.ContentExportRequest) (ExportFunc, error) {
+	if err := exportUsecase.validateRequest(request); err != nil {
+		log.Println("error export content : ", err)
+		return nil, err
+	}
+
+	columns, err := selectExportColumns(contentExportColumns, request.Columns)
+	if err != nil {
+		log.Println("error export content : ", err)
+		return nil, err
+	}
+
+	normalizeContentFilter(&request.Filter)
+
+	return func(writer io.Writer) error {
+		tableWriter, err := util.NewTableWriter(request.Format, writer, exportColumnNames(columns))
+		if err != nil {
+			return err
+		}
+
+		err = exportUsecase.ContentRepo.StreamExport(exportUsecase.DB.WithContext(ctx), &request.Filter, func(row *model.ContentExportRow) error {
+			return tableWriter.WriteRow(exportColumnValues(columns, row))
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success export from usecase content")
+
+		return tableWriter.Close()
+	}, nil
+}
+
+// ExportAnnouncements implements ExportUsecase.
+func (exportUsecase *ExportUsecaseImpl) ExportAnnouncements(ctx context.Context, request *model.AnnouncementExportRequest) (ExportFunc, error) {
+	if err := exportUsecase.validateRequest(request); err != nil {
+		log.Println("error export announcement : ", err)
+		return nil, err
+	}
+
+	columns, err := selectExportColumns(announcementExportColumns, request.Columns)
+	if err != nil {
+		log.Println("error export announcement : ", err)
+		return nil, err
+	}
+
+	normalizeAnnouncementFilter(&request.Filter)
+
+	return func(writer io.Writer) error {
+		tableWriter, err := util.NewTableWriter(request.Format, writer, exportColumnNames(columns))
+		if err != nil {
+			return err
+		}
+
+		err = exportUsecase.AnnouncementRepo.StreamExport(exportUsecase.DB.WithContext(ctx), &request.Filter, func(row *model.AnnouncementExportRow) error {
+			return tableWriter.WriteRow(exportColumnValues(columns, row))
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Println("success export from usecase announcement")
+
+		return tableWriter.Close()
+	}, nil
+}
+
+func (exportUsecase *ExportUsecaseImpl) validateRequest(request any) error {
+	err := exportUsecase.Validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors []string
+	for _, e := range err.(validator.ValidationErrors) {
+		msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+		validationErrors = append(validationErrors, msg)
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: validationErrors,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+// selectExportColumns keeps the requested columns in the requested order, or
+// every column when none were asked for.
+func selectExportColumns[T any](available []exportColumn[T], names []string) ([]exportColumn[T], error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	var columns []exportColumn[T]
+	var unknown []string
+	for _, name := range names {
+		found := false
+		for _, column := range available {
+			if column.Name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			unknown = append(unknown, fmt.Sprintf("Column '%s' is not available", name))
+		}
+	}
+
+	if len(unknown) > 0 {
+		errorResponse := model.ErrorResponse{
+			Message: "invalid request parameter",
+			Details: unknown,
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	return columns, nil
+}
+
+func exportColumnNames[T any](columns []exportColumn[T]) []string {
+	names := make([]string, len(columns))
+	for index, column := range columns {
+		names[index] = column.Name
+	}
+
+	return names
+}
+
+func exportColumnValues[T any](columns []exportColumn[T], row *T) []any {
+	values := make([]any, len(columns))
+	for index, column := range columns {
+		values[index] = column.Value(row)
+	}
+
+	return values
+}
+
+// exportOptional turns a nil pointer into an empty cell.
+func exportOptional[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+
+	return *value
+}
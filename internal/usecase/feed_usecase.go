@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gorilla/feeds"
+	"gorm.io/gorm"
+)
+
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+
+	feedLimit = 50
+)
+
+type FeedUsecase interface {
+	Announcements(ctx context.Context, request *model.FeedRequest) (*model.FeedResponse, error)
+	Contents(ctx context.Context, request *model.FeedRequest) (*model.FeedResponse, error)
+}
+
+// FeedUsecaseImpl publishes the latest announcements and listings as RSS
+// and Atom. Items link to the front-end pages and keep a tag URI as GUID, so
+// readers see an item once even when its title or link changes.
+type FeedUsecaseImpl struct {
+	ContentRepo      repository.ContentRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	DB               *gorm.DB
+	Validate         *validator.Validate
+	Links            SiteLinks
+	SiteName         string
+	UploadDir        string
+	// GUIDHost names the site in the GUIDs. It is fixed by configuration,
+	// as the host a feed was fetched from would give one item many GUIDs.
+	GUIDHost string
+}
+
+func NewFeedUsecase(contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, DB *gorm.DB, validate *validator.Validate, links SiteLinks, siteName string, guidHost string) FeedUsecase {
+	if siteName == "" {
+		siteName = "Web Profile"
+	}
+
+	if guidHost == "" {
+		if site, err := url.Parse(links.SiteURL); err == nil {
+			guidHost = site.Hostname()
+		}
+	}
+
+	if guidHost == "" {
+		log.Println("FEED_GUID_HOST and SITE_URL are not set, feed GUIDs use localhost")
+		guidHost = "localhost"
+	}
+
+	return &FeedUsecaseImpl{
+		ContentRepo:      contentRepo,
+		AnnouncementRepo: announcementRepo,
+		DB:               DB,
+		Validate:         validate,
+		Links:            links.withDefaults(),
+		SiteName:         siteName,
+		UploadDir:        UploadDir,
+		GUIDHost:         guidHost,
+	}
+}
+
+// Announcements implements FeedUsecase.
+func (feedUsecase *FeedUsecaseImpl) Announcements(ctx context.Context, request *model.FeedRequest) (*model.FeedResponse, error) {
+	tx := feedUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := feedUsecase.validate(request); err != nil {
+		return nil, err
+	}
+
+	var announcements = &[]entity.Announcement{}
+	if err := feedUsecase.AnnouncementRepo.FindLatest(tx, time.Now(), feedLimit, announcements); err != nil {
+		log.Println("failed when find latest repo announcement : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	feed := &feeds.Feed{
+		Title:       feedUsecase.SiteName + " - Announcements",
+		Link:        &feeds.Link{Href: feedUsecase.siteURL(request)},
+		Description: "The latest announcements of " + feedUsecase.SiteName,
+	}
+
+	for _, announcement := range *announcements {
+		localizeAnnouncement(&announcement, request.Locale)
+
+		contentHTML := util.RenderContentHTML(announcement.ContentFormat, announcement.Content)
+		feed.Add(&feeds.Item{
+			Title:       announcement.Title,
+			Link:        &feeds.Link{Href: feedUsecase.Links.Announcement(announcement.ID, request.Locale)},
+			Author:      &feeds.Author{Name: announcement.Admin.Name},
+			Description: util.GenerateExcerpt(contentHTML),
+			Content:     contentHTML,
+			Id:          feedUsecase.guid("announcement", announcement.ID, announcement.CreatedAt),
+			IsPermaLink: "false",
+			Created:     announcement.CreatedAt,
+			Updated:     announcement.UpdatedAt,
+			Enclosure:   feedUsecase.enclosure(request, announcement.Image),
+		})
+	}
+
+	log.Println("success announcements from usecase feed")
+	return feedUsecase.render(request, feed)
+}
+
+// Contents implements FeedUsecase.
+func (feedUsecase *FeedUsecaseImpl) Contents(ctx context.Context, request *model.FeedRequest) (*model.FeedResponse, error) {
+	tx := feedUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := feedUsecase.validate(request); err != nil {
+		return nil, err
+	}
+
+	var contents = &[]entity.Content{}
+	if err := feedUsecase.ContentRepo.FindLatest(tx, request.Category, feedLimit, contents); err != nil {
+		log.Println("failed when find latest repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	title := "Listings"
+	if request.Category != "" {
+		title = strings.ToUpper(request.Category[:1]) + request.Category[1:]
+	}
+
+	feed := &feeds.Feed{
+		Title:       feedUsecase.SiteName + " - " + title,
+		Link:        &feeds.Link{Href: feedUsecase.siteURL(request)},
+		Description: "The newest " + strings.ToLower(title) + " listings of " + feedUsecase.SiteName,
+	}
+
+	for _, content := range *contents {
+		localizeContent(&content, request.Locale)
+
+		contentHTML := util.RenderContentHTML(content.ContentFormat, content.Content)
+		feed.Add(&feeds.Item{
+			Title:       content.Title,
+			Link:        &feeds.Link{Href: feedUsecase.Links.Content(content.ID, content.Category, request.Locale)},
+			Author:      &feeds.Author{Name: content.Admin.Name},
+			Description: util.GenerateExcerpt(contentHTML),
+			Content:     contentHTML,
+			Id:          feedUsecase.guid("content", content.ID, content.CreatedAt),
+			IsPermaLink: "false",
+			Created:     content.CreatedAt,
+			Updated:     content.UpdatedAt,
+			Enclosure:   feedUsecase.enclosure(request, content.Image),
+		})
+	}
+
+	log.Println("success contents from usecase feed")
+	return feedUsecase.render(request, feed)
+}
+
+func (feedUsecase *FeedUsecaseImpl) validate(request *model.FeedRequest) error {
+	err := feedUsecase.Validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors []string
+	for _, e := range err.(validator.ValidationErrors) {
+		msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+		validationErrors = append(validationErrors, msg)
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: validationErrors,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	log.Println("error feed : ", err)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+// render writes the feed in the requested format. The feed is as new as its
+// most recently updated item, which is also what conditional requests are
+// checked against.
+func (feedUsecase *FeedUsecaseImpl) render(request *model.FeedRequest, feed *feeds.Feed) (*model.FeedResponse, error) {
+	for _, item := range feed.Items {
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+	}
+
+	response := &model.FeedResponse{LastModified: feed.Updated}
+	if feed.Updated.IsZero() {
+		feed.Updated = time.Now()
+	}
+
+	var err error
+	switch request.Format {
+	case FeedFormatAtom:
+		atom := (&feeds.Atom{Feed: feed}).AtomFeed()
+		atom.Id = request.SelfURL
+		for i, entry := range atom.Entries {
+			entry.Published = feed.Items[i].Created.Format(time.RFC3339)
+		}
+
+		response.ContentType = "application/atom+xml; charset=utf-8"
+		response.Body, err = feeds.ToXML(atom)
+	default:
+		response.ContentType = "application/rss+xml; charset=utf-8"
+		response.Body, err = feed.ToRss()
+	}
+
+	if err != nil {
+		log.Println("failed when render feed : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return response, nil
+}
+
+// guid identifies an item with a tag URI (RFC 4151) built from the site host
+// and the day the item was created, which never changes afterwards.
+func (feedUsecase *FeedUsecaseImpl) guid(itemType string, id uint, createdAt time.Time) string {
+	return fmt.Sprintf("tag:%s,%s:%s/%s", feedUsecase.GUIDHost, createdAt.Format("2006-01-02"), itemType, strconv.FormatUint(uint64(id), 10))
+}
+
+// enclosure points at the image of an item. RSS needs the size of the file,
+// so images missing from the upload directory are left out.
+func (feedUsecase *FeedUsecaseImpl) enclosure(request *model.FeedRequest, image string) *feeds.Enclosure {
+	if image == "" {
+		return nil
+	}
+
+	info, err := os.Stat(filepath.Join(feedUsecase.UploadDir, filepath.Base(image)))
+	if err != nil {
+		log.Println("failed when stat feed enclosure : ", err)
+		return nil
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(image))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &feeds.Enclosure{
+		Url:    strings.TrimRight(request.BaseURL, "/") + "/assets/image/" + url.PathEscape(image),
+		Length: strconv.FormatInt(info.Size(), 10),
+		Type:   contentType,
+	}
+}
+
+// siteURL is the front-end home page, falling back to the API itself when
+// no site URL is configured.
+func (feedUsecase *FeedUsecaseImpl) siteURL(request *model.FeedRequest) string {
+	if feedUsecase.Links.SiteURL != "" {
+		return feedUsecase.Links.SiteURL
+	}
+
+	return request.BaseURL
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-sql-driver/mysql"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	EditorItemContent      = "content"
+	EditorItemAnnouncement = "announcement"
+)
+
+type ItemEditorUsecase interface {
+	FindAll(ctx context.Context, itemType string, itemId uint, actorId uint) (*[]model.ItemEditorResponse, error)
+	Assign(ctx context.Context, request *model.ItemEditorRequest) (*model.ItemEditorResponse, error)
+	Remove(ctx context.Context, request *model.ItemEditorRequest) error
+}
+
+// ItemEditorUsecaseImpl manages the co-editors of contents and announcements.
+// Only the owner of an item and the roles that bypass ownership may change
+// who else can edit it.
+type ItemEditorUsecaseImpl struct {
+	ItemEditorRepo   repository.ItemEditorRepository
+	ContentRepo      repository.ContentRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	AdminRepo        repository.AdminRepository
+	DB               *gorm.DB
+	Validate         *validator.Validate
+}
+
+func NewItemEditorUsecase(itemEditorRepo repository.ItemEditorRepository, contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) ItemEditorUsecase {
+	return &ItemEditorUsecaseImpl{
+		ItemEditorRepo:   itemEditorRepo,
+		ContentRepo:      contentRepo,
+		AnnouncementRepo: announcementRepo,
+		AdminRepo:        adminRepo,
+		DB:               DB,
+		Validate:         validate,
+	}
+}
+
+// FindAll implements ItemEditorUsecase.
+func (itemEditorUsecase *ItemEditorUsecaseImpl) FindAll(ctx context.Context, itemType string, itemId uint, actorId uint) (*[]model.ItemEditorResponse, error) {
+	tx := itemEditorUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	actor, err := findActor(tx, itemEditorUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerId, err := itemEditorUsecase.findOwner(tx, itemType, itemId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkOwnership(tx, itemEditorUsecase.ItemEditorRepo, actor, itemType, itemId, ownerId); err != nil {
+		return nil, err
+	}
+
+	var editors = &[]entity.ItemEditor{}
+	if err := itemEditorUsecase.ItemEditorRepo.FindByItem(tx, itemType, itemId, editors); err != nil {
+		log.Println("failed when find by item repo item editor : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase item editor")
+	return converter.ItemEditorToResponses(editors), nil
+}
+
+// Assign implements ItemEditorUsecase.
+func (itemEditorUsecase *ItemEditorUsecaseImpl) Assign(ctx context.Context, request *model.ItemEditorRequest) (*model.ItemEditorResponse, error) {
+	tx := itemEditorUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := itemEditorUsecase.Validate.Struct(request); err != nil {
+		log.Println("error assign item editor : ", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	if err := itemEditorUsecase.checkManager(tx, request); err != nil {
+		return nil, err
+	}
+
+	admin := &entity.Admin{ID: request.AdminID}
+	if err := itemEditorUsecase.AdminRepo.FindById(tx, admin); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorResponse := model.ErrorResponse{
+				Message: "Admin data was not found",
+				Details: []string{},
+			}
+
+			jsonString, _ := json.Marshal(errorResponse)
+
+			log.Println("error assign item editor usecase : ", err)
+
+			return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+		}
+
+		log.Println("Error assign item editor usecase:", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	editor := &entity.ItemEditor{
+		ItemType:   request.ItemType,
+		ItemID:     request.ItemID,
+		AdminID:    request.AdminID,
+		AssignedBy: &request.ActorID,
+	}
+
+	if err := itemEditorUsecase.ItemEditorRepo.Create(tx, editor); err != nil {
+		log.Println("failed when create repo item editor : ", err)
+
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			errorResponse := model.ErrorResponse{
+				Message: "Duplicate entry",
+				Details: []string{"the admin is already an editor of this item"},
+			}
+
+			jsonString, _ := json.Marshal(errorResponse)
+
+			return nil, fiber.NewError(fiber.ErrConflict.Code, string(jsonString))
+		}
+
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	editor.Admin = *admin
+
+	log.Println("success assign from usecase item editor")
+	return converter.ItemEditorToResponse(editor), nil
+}
+
+// Remove implements ItemEditorUsecase.
+func (itemEditorUsecase *ItemEditorUsecaseImpl) Remove(ctx context.Context, request *model.ItemEditorRequest) error {
+	tx := itemEditorUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := itemEditorUsecase.Validate.Struct(request); err != nil {
+		log.Println("error remove item editor : ", err)
+		return fiber.ErrBadRequest
+	}
+
+	if err := itemEditorUsecase.checkManager(tx, request); err != nil {
+		return err
+	}
+
+	removed, err := itemEditorUsecase.ItemEditorRepo.DeleteEditor(tx, request.ItemType, request.ItemID, request.AdminID)
+	if err != nil {
+		log.Println("failed when delete repo item editor : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if !removed {
+		errorResponse := model.ErrorResponse{
+			Message: "Editor data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success remove from usecase item editor")
+	return nil
+}
+
+// checkManager makes sure the actor owns the item or has a role that
+// bypasses ownership. Co-editors may edit the item but not delegate it.
+func (itemEditorUsecase *ItemEditorUsecaseImpl) checkManager(tx *gorm.DB, request *model.ItemEditorRequest) error {
+	actor, err := findActor(tx, itemEditorUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return err
+	}
+
+	ownerId, err := itemEditorUsecase.findOwner(tx, request.ItemType, request.ItemID)
+	if err != nil {
+		return err
+	}
+
+	if actor.ID != ownerId && !bypassesOwnership(actor.Role) {
+		return forbiddenError("only the owner can manage the editors of this item")
+	}
+
+	return nil
+}
+
+// findOwner returns the admin who created the content or published the
+// announcement.
+func (itemEditorUsecase *ItemEditorUsecaseImpl) findOwner(tx *gorm.DB, itemType string, itemId uint) (uint, error) {
+	switch itemType {
+	case EditorItemContent:
+		content := &entity.Content{ID: itemId}
+		if err := itemEditorUsecase.ContentRepo.FindById(tx, content); err != nil {
+			return 0, contentNotFoundError(err)
+		}
+		return content.CreatedBy, nil
+	case EditorItemAnnouncement:
+		announcement := &entity.Announcement{ID: itemId}
+		if err := itemEditorUsecase.AnnouncementRepo.FindById(tx, announcement); err != nil {
+			return 0, announcementNotFoundError(err)
+		}
+		return announcement.PublishedBy, nil
+	default:
+		errorResponse := model.ErrorResponse{
+			Message: "invalid request parameter",
+			Details: []string{"type must be one of content or announcement"},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return 0, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+}
+
+// bypassesOwnership reports whether the role may change items of any owner.
+func bypassesOwnership(role string) bool {
+	return role == AdminRolePublisher || role == AdminRoleAdmin
+}
+
+// checkOwnership is the ownership policy of contents and announcements: an
+// editor may only change the items they own or were made co-editor of.
+func checkOwnership(tx *gorm.DB, itemEditorRepo repository.ItemEditorRepository, actor *entity.Admin, itemType string, itemId uint, ownerId uint) error {
+	if actor.ID == ownerId || bypassesOwnership(actor.Role) {
+		return nil
+	}
+
+	isEditor, err := itemEditorRepo.IsEditor(tx, itemType, itemId, actor.ID)
+	if err != nil {
+		log.Println("failed when check repo item editor : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if !isEditor {
+		log.Printf("error check ownership : admin %d is no editor of %s %d", actor.ID, itemType, itemId)
+		return forbiddenError("you can only change items you own or co-edit")
+	}
+
+	return nil
+}
+
+// checkOwnerChange keeps editors from handing an item over to another owner,
+// which would take it out of reach of the ownership policy.
+func checkOwnerChange(actor *entity.Admin, currentOwner uint, nextOwner uint) error {
+	if currentOwner == nextOwner || bypassesOwnership(actor.Role) {
+		return nil
+	}
+
+	log.Printf("error check ownership : admin %d can not change the owner", actor.ID)
+	return forbiddenError("only publishers can change the owner of an item")
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// applyMergePatch merges a JSON Merge Patch into request, which must hold the
+// current state of the item, and reports only the validation errors of the
+// members the patch names, elements of patched lists included. An optional
+// prepare function normalizes the merged request before the
+// validation. Members of a group whose rules depend on each other are
+// validated together as soon as one of them is patched. It returns the
+// patched members by their JSON name.
+func applyMergePatch[T any](validate *validator.Validate, request *T, patch []byte, prepare func(request *T), groups ...[]string) ([]string, error) {
+	errorResponse := &model.ErrorResponse{Message: "invalid request parameter"}
+
+	members, err := util.MergePatchFields(patch)
+	if err != nil {
+		errorResponse.Details = []string{err.Error()}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	namespaces := patchableFields(reflect.TypeOf(*request), "")
+
+	var fields []string
+	var unknown []string
+	for _, member := range members {
+		namespace, ok := namespaces[member]
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("Field '%s' can not be patched", member))
+			continue
+		}
+		fields = append(fields, namespace)
+	}
+
+	if len(unknown) > 0 {
+		errorResponse.Details = unknown
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	original, err := json.Marshal(request)
+	if err != nil {
+		log.Println("failed to marshal patch target : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	merged, err := util.MergePatch(original, patch)
+	if err != nil {
+		errorResponse.Details = []string{err.Error()}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	// members removed by the patch go back to their zero value
+	patched := new(T)
+	if err := json.Unmarshal(merged, patched); err != nil {
+		errorResponse.Details = []string{err.Error()}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	if prepare != nil {
+		prepare(patched)
+	}
+
+	for _, group := range groups {
+		if patchesAny(members, group...) {
+			for _, member := range group {
+				fields = append(fields, namespaces[member])
+			}
+		}
+	}
+
+	// the whole struct is validated, as StructPartial skips the rules of
+	// the structs in a patched list
+	var validationErrors []string
+	if err := validate.Struct(patched); err != nil {
+		for _, e := range err.(validator.ValidationErrors) {
+			if !withinFields(e.StructNamespace(), fields) {
+				continue
+			}
+
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		errorResponse.Details = validationErrors
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	*request = *patched
+
+	return members, nil
+}
+
+// patchableFields maps the JSON name of every field but the id to the
+// namespace the validator uses for it, walking into embedded structs.
+func patchableFields(structType reflect.Type, prefix string) map[string]string {
+	fields := map[string]string{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, namespace := range patchableFields(field.Type, prefix+field.Name+".") {
+				fields[name] = namespace
+			}
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || name == "id" {
+			continue
+		}
+
+		fields[name] = prefix + field.Name
+	}
+
+	return fields
+}
+
+// withinFields tells whether a validator namespace, e.g.
+// "ContentUpdateRequest.Prices[0].Tier", is one of fields or lies inside one.
+func withinFields(namespace string, fields []string) bool {
+	_, namespace, _ = strings.Cut(namespace, ".")
+
+	for _, field := range fields {
+		if rest, ok := strings.CutPrefix(namespace, field); ok && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+			return true
+		}
+	}
+
+	return false
+}
+
+func patchesAny(members []string, names ...string) bool {
+	for _, member := range members {
+		for _, name := range names {
+			if member == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		members []string
+		details []string
+		check   func(t *testing.T, request *model.ContentUpdateRequest)
+	}{
+		{
+			name:    "title",
+			patch:   `{"title": "Pantai Baru"}`,
+			members: []string{"title"},
+			check: func(t *testing.T, request *model.ContentUpdateRequest) {
+				if request.Title != "Pantai Baru" || request.Address != "Jl. Pantai 1" {
+					t.Errorf("request = %+v, want the title patched and the rest kept", request)
+				}
+			},
+		},
+		{
+			name:    "removed member",
+			patch:   `{"title": null}`,
+			details: []string{"Field 'Title' failed on 'required' rule"},
+		},
+		{
+			name:    "unknown member",
+			patch:   `{"rating": 5}`,
+			details: []string{"Field 'rating' can not be patched"},
+		},
+		{
+			name:    "id is not patchable",
+			patch:   `{"id": 2}`,
+			details: []string{"Field 'id' can not be patched"},
+		},
+		{
+			name:    "struct in a patched list",
+			patch:   `{"prices": [{"tier": "vip", "amount": 1000}]}`,
+			details: []string{"Field 'Tier' failed on 'oneof' rule"},
+		},
+		{
+			name:    "element of a patched list",
+			patch:   `{"tags": ["pantai", ""]}`,
+			details: []string{"Field 'Tags[1]' failed on 'required' rule"},
+		},
+		{
+			name:    "valid list",
+			patch:   `{"prices": [{"tier": "adult", "amount": 25000}]}`,
+			members: []string{"prices"},
+			check: func(t *testing.T, request *model.ContentUpdateRequest) {
+				want := []model.ContentPriceRequest{{Tier: "adult", Amount: 25000}}
+				if !reflect.DeepEqual(request.Prices, want) {
+					t.Errorf("Prices = %+v, want %+v", request.Prices, want)
+				}
+			},
+		},
+		{
+			name:    "group validated together",
+			patch:   `{"latitude": -8.4}`,
+			details: []string{"Field 'Longitude' failed on 'required_with' rule"},
+		},
+		{
+			name:    "invalid member left alone",
+			patch:   `{"address": "Jl. Pantai 2"}`,
+			members: []string{"address"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := &model.ContentUpdateRequest{
+				ID:        1,
+				Title:     "Pantai",
+				Content:   "<p>Pasir putih</p>",
+				Address:   "Jl. Pantai 1",
+				Category:  "wisata",
+				CreatedBy: 1,
+				// stored before the rule existed, not touched by the patches
+				Currency: "rupiah",
+			}
+			original := *request
+
+			members, err := applyMergePatch(validator.New(), request, []byte(test.patch), nil,
+				[]string{"latitude", "longitude"},
+			)
+
+			if test.details != nil {
+				if got := errorDetails(t, err); !reflect.DeepEqual(got, test.details) {
+					t.Errorf("details = %q, want %q", got, test.details)
+				}
+				if !reflect.DeepEqual(*request, original) {
+					t.Errorf("request = %+v, want it unchanged", request)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyMergePatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(members, test.members) {
+				t.Errorf("members = %q, want %q", members, test.members)
+			}
+			if test.check != nil {
+				test.check(t, request)
+			}
+		})
+	}
+}
+
+func TestWithinFields(t *testing.T) {
+	fields := []string{"Prices", "Title"}
+
+	tests := []struct {
+		namespace string
+		want      bool
+	}{
+		{"ContentUpdateRequest.Title", true},
+		{"ContentUpdateRequest.Prices[0].Tier", true},
+		{"ContentUpdateRequest.Prices", true},
+		{"ContentUpdateRequest.TitleSuffix", false},
+		{"ContentUpdateRequest.Address", false},
+	}
+
+	for _, test := range tests {
+		if got := withinFields(test.namespace, fields); got != test.want {
+			t.Errorf("withinFields(%q) = %v, want %v", test.namespace, got, test.want)
+		}
+	}
+}
+
+// errorDetails reads the details of a validation error the usecases return.
+func errorDetails(t *testing.T, err error) []string {
+	t.Helper()
+
+	fiberError, ok := err.(*fiber.Error)
+	if !ok || fiberError.Code != fiber.StatusBadRequest {
+		t.Fatalf("error = %v, want a bad request", err)
+	}
+
+	var response model.ErrorResponse
+	if err := json.Unmarshal([]byte(fiberError.Message), &response); err != nil {
+		t.Fatalf("error message %q is no error response: %v", fiberError.Message, err)
+	}
+
+	return response.Details
+}
@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	// messageRateLimit is how many messages one address may send within
+	// messageRateWindow.
+	messageRateLimit  = 5
+	messageRateWindow = time.Hour
+	// messageLinkLimit is the most links a message may hold before it is
+	// taken for spam.
+	messageLinkLimit = 2
+)
+
+type MessageUsecase interface {
+	Create(ctx context.Context, request *model.MessageCreateRequest) error
+	FindAll(ctx context.Context, request *model.MessageFilterRequest) (*[]model.MessageResponse, error)
+	FindById(ctx context.Context, id uint) (*model.MessageResponse, error)
+	UpdateState(ctx context.Context, request *model.MessageStateRequest) (*model.MessageResponse, error)
+	Assign(ctx context.Context, request *model.MessageAssignRequest) (*model.MessageResponse, error)
+	AddNote(ctx context.Context, request *model.MessageNoteRequest) (*model.MessageNoteResponse, error)
+	Reply(ctx context.Context, request *model.MessageReplyRequest) (*model.MessageReplyResponse, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// MessageUsecaseImpl receives the contact form and runs the inbox of the
+// admins. Replies are mailed through Mailer from MailFrom.
+type MessageUsecaseImpl struct {
+	MessageRepo      repository.MessageRepository
+	MessageNoteRepo  repository.MessageNoteRepository
+	MessageReplyRepo repository.MessageReplyRepository
+	AdminRepo        repository.AdminRepository
+	DB               *gorm.DB
+	Validate         *validator.Validate
+	Mailer           util.Mailer
+	MailFrom         string
+}
+
+func NewMessageUsecase(messageRepo repository.MessageRepository, messageNoteRepo repository.MessageNoteRepository, messageReplyRepo repository.MessageReplyRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate, mailer util.Mailer, mailFrom string) MessageUsecase {
+	if mailFrom == "" {
+		mailFrom = "no-reply@localhost"
+	}
+
+	return &MessageUsecaseImpl{
+		MessageRepo:      messageRepo,
+		MessageNoteRepo:  messageNoteRepo,
+		MessageReplyRepo: messageReplyRepo,
+		AdminRepo:        adminRepo,
+		DB:               DB,
+		Validate:         validate,
+		Mailer:           mailer,
+		MailFrom:         mailFrom,
+	}
+}
+
+// Create implements MessageUsecase. A filled in honeypot is answered like
+// any other message but nothing is stored, so bots learn nothing from it.
+func (messageUsecase *MessageUsecaseImpl) Create(ctx context.Context, request *model.MessageCreateRequest) error {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := validateMessageRequest(messageUsecase.Validate, request, "create"); err != nil {
+		return err
+	}
+
+	if request.Website != "" {
+		log.Println("error create message : honeypot filled in from ", request.IPAddress)
+		return nil
+	}
+
+	sent, err := messageUsecase.MessageRepo.CountSince(tx, request.IPAddress, time.Now().Add(-messageRateWindow))
+	if err != nil {
+		log.Println("failed when count since repo message : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if sent >= messageRateLimit {
+		errorResponse := model.ErrorResponse{
+			Message: "Too many requests",
+			Details: []string{"too many messages were sent from your address, please try again later"},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error create message : rate limit reached by ", request.IPAddress)
+
+		return fiber.NewError(fiber.StatusTooManyRequests, string(jsonString))
+	}
+
+	message := &entity.Message{
+		Name:      strings.TrimSpace(request.Name),
+		Email:     strings.TrimSpace(request.Email),
+		Phone:     strings.TrimSpace(request.Phone),
+		Subject:   strings.TrimSpace(request.Subject),
+		Body:      strings.TrimSpace(request.Body),
+		IPAddress: request.IPAddress,
+		UserAgent: truncate(request.UserAgent, 255),
+		IsSpam:    looksLikeSpam(request.Subject + "\n" + request.Body),
+	}
+
+	if err := messageUsecase.MessageRepo.Create(tx, message); err != nil {
+		log.Println("failed when create repo message : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success create from usecase message")
+	return nil
+}
+
+// FindAll implements MessageUsecase.
+func (messageUsecase *MessageUsecaseImpl) FindAll(ctx context.Context, request *model.MessageFilterRequest) (*[]model.MessageResponse, error) {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := validateMessageRequest(messageUsecase.Validate, request, "find all"); err != nil {
+		return nil, err
+	}
+
+	switch request.AssignedTo {
+	case "", "me", "none":
+	default:
+		if _, err := strconv.ParseUint(request.AssignedTo, 10, 32); err != nil {
+			return nil, invalidMessageError("Field 'AssignedTo' must be me, none or the id of an admin")
+		}
+	}
+
+	var messages = &[]entity.Message{}
+	if err := messageUsecase.MessageRepo.FindAll(tx, request, messages); err != nil {
+		log.Println("failed when find all repo message : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase message")
+	return converter.MessageToResponses(messages), nil
+}
+
+// FindById implements MessageUsecase.
+func (messageUsecase *MessageUsecaseImpl) FindById(ctx context.Context, id uint) (*model.MessageResponse, error) {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	message := &entity.Message{ID: id}
+	if err := messageUsecase.MessageRepo.FindById(tx, message); err != nil {
+		return nil, messageNotFoundError(err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find by id from usecase message")
+	return converter.MessageToResponse(message), nil
+}
+
+// UpdateState implements MessageUsecase.
+func (messageUsecase *MessageUsecaseImpl) UpdateState(ctx context.Context, request *model.MessageStateRequest) (*model.MessageResponse, error) {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := validateMessageRequest(messageUsecase.Validate, request, "update state"); err != nil {
+		return nil, err
+	}
+
+	message := &entity.Message{ID: request.ID}
+	if err := messageUsecase.MessageRepo.FindById(tx, message); err != nil {
+		return nil, messageNotFoundError(err)
+	}
+
+	now := time.Now()
+	var columns []string
+
+	if request.Read != nil && *request.Read != (message.ReadAt != nil) {
+		message.ReadAt = nil
+		if *request.Read {
+			message.ReadAt = &now
+		}
+		columns = append(columns, "read_at")
+	}
+
+	if request.Archived != nil && *request.Archived != (message.ArchivedAt != nil) {
+		message.ArchivedAt = nil
+		if *request.Archived {
+			message.ArchivedAt = &now
+		}
+		columns = append(columns, "archived_at")
+	}
+
+	if request.Spam != nil && *request.Spam != message.IsSpam {
+		message.IsSpam = *request.Spam
+		columns = append(columns, "is_spam")
+	}
+
+	if len(columns) > 0 {
+		if err := messageUsecase.MessageRepo.Patch(tx, &entity.Message{
+			ID:         message.ID,
+			ReadAt:     message.ReadAt,
+			ArchivedAt: message.ArchivedAt,
+			IsSpam:     message.IsSpam,
+		}, columns); err != nil {
+			log.Println("failed when patch repo message : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success update state from usecase message")
+	return converter.MessageToResponse(message), nil
+}
+
+// Assign implements MessageUsecase.
+func (messageUsecase *MessageUsecaseImpl) Assign(ctx context.Context, request *model.MessageAssignRequest) (*model.MessageResponse, error) {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := validateMessageRequest(messageUsecase.Validate, request, "assign"); err != nil {
+		return nil, err
+	}
+
+	message := &entity.Message{ID: request.ID}
+	if err := messageUsecase.MessageRepo.FindById(tx, message); err != nil {
+		return nil, messageNotFoundError(err)
+	}
+
+	message.AssignedTo = request.AdminID
+	message.Assignee = nil
+
+	if request.AdminID != nil {
+		admin := &entity.Admin{ID: *request.AdminID}
+		if err := messageUsecase.AdminRepo.FindById(tx, admin); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				errorResponse := model.ErrorResponse{
+					Message: "Admin data was not found",
+					Details: []string{},
+				}
+
+				jsonString, _ := json.Marshal(errorResponse)
+
+				log.Println("error assign message usecase : ", err)
+
+				return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+			}
+
+			log.Println("Error assign message usecase:", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		message.Assignee = admin
+	}
+
+	if err := messageUsecase.MessageRepo.Patch(tx, &entity.Message{ID: message.ID, AssignedTo: message.AssignedTo}, []string{"assigned_to"}); err != nil {
+		log.Println("failed when patch repo message : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success assign from usecase message")
+	return converter.MessageToResponse(message), nil
+}
+
+// AddNote implements MessageUsecase.
+func (messageUsecase *MessageUsecaseImpl) AddNote(ctx context.Context, request *model.MessageNoteRequest) (*model.MessageNoteResponse, error) {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := validateMessageRequest(messageUsecase.Validate, request, "add note"); err != nil {
+		return nil, err
+	}
+
+	actor, err := findActor(tx, messageUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &entity.Message{ID: request.MessageID}
+	if err := messageUsecase.MessageRepo.FindById(tx, message); err != nil {
+		return nil, messageNotFoundError(err)
+	}
+
+	if request.ParentID != nil {
+		parent := &entity.MessageNote{ID: *request.ParentID}
+		err := messageUsecase.MessageNoteRepo.FindById(tx, parent)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("failed when find by id repo message note : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		// a note can only answer a note on the same message
+		if err != nil || parent.MessageID != message.ID {
+			return nil, invalidMessageError("Field 'ParentID' is not a note of this message")
+		}
+	}
+
+	note := &entity.MessageNote{
+		MessageID: message.ID,
+		ParentID:  request.ParentID,
+		AdminID:   actor.ID,
+		Body:      strings.TrimSpace(request.Body),
+	}
+
+	if err := messageUsecase.MessageNoteRepo.Create(tx, note); err != nil {
+		log.Println("failed when create repo message note : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	note.Admin = *actor
+
+	log.Println("success add note from usecase message")
+	return converter.MessageNoteToResponse(note), nil
+}
+
+// Reply implements MessageUsecase. The mail goes out before the reply is
+// committed, so a reply is only recorded once it was handed to the mailer.
+func (messageUsecase *MessageUsecaseImpl) Reply(ctx context.Context, request *model.MessageReplyRequest) (*model.MessageReplyResponse, error) {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := validateMessageRequest(messageUsecase.Validate, request, "reply"); err != nil {
+		return nil, err
+	}
+
+	actor, err := findActor(tx, messageUsecase.AdminRepo, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &entity.Message{ID: request.MessageID}
+	if err := messageUsecase.MessageRepo.FindById(tx, message); err != nil {
+		return nil, messageNotFoundError(err)
+	}
+
+	subject := strings.TrimSpace(request.Subject)
+	if subject == "" {
+		subject = "Re: " + message.Subject
+	}
+
+	reply := &entity.MessageReply{
+		MessageID: message.ID,
+		AdminID:   actor.ID,
+		Subject:   subject,
+		Body:      strings.TrimSpace(request.Body),
+		SentAt:    time.Now(),
+	}
+
+	// the reply is only recorded once it left, a failed send must not show
+	// up in the thread as answered
+	to := (&mail.Address{Name: message.Name, Address: message.Email}).String()
+	if err := messageUsecase.Mailer.Send(ctx, &util.Mail{
+		From:    messageUsecase.MailFrom,
+		To:      []string{to},
+		Subject: reply.Subject,
+		Text:    replyText(reply.Body, message),
+	}); err != nil {
+		log.Println("failed when send reply mail : ", err)
+		return nil, mailFailedError("the reply was not sent, please try again later")
+	}
+
+	if err := messageUsecase.MessageReplyRepo.Create(tx, reply); err != nil {
+		log.Println("failed when create repo message reply : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if message.ReadAt == nil {
+		if err := messageUsecase.MessageRepo.Patch(tx, &entity.Message{ID: message.ID, ReadAt: &reply.SentAt}, []string{"read_at"}); err != nil {
+			log.Println("failed when patch repo message : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	reply.Admin = *actor
+
+	log.Println("success reply from usecase message")
+	return converter.MessageReplyToResponse(reply), nil
+}
+
+// Delete implements MessageUsecase.
+func (messageUsecase *MessageUsecaseImpl) Delete(ctx context.Context, id uint) error {
+	tx := messageUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	message := &entity.Message{ID: id}
+	if err := messageUsecase.MessageRepo.FindById(tx, message); err != nil {
+		return messageNotFoundError(err)
+	}
+
+	if err := messageUsecase.MessageRepo.Delete(tx, &entity.Message{ID: id}); err != nil {
+		log.Println("failed when delete repo message : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success delete from usecase message")
+	return nil
+}
+
+// looksLikeSpam flags messages stuffed with links or link markup, which the
+// people writing to the village office have no use for.
+func looksLikeSpam(text string) bool {
+	text = strings.ToLower(text)
+	if strings.Contains(text, "<a href") || strings.Contains(text, "[url") {
+		return true
+	}
+
+	links := strings.Count(text, "http://") + strings.Count(text, "https://") + strings.Count(text, "www.")
+	return links > messageLinkLimit
+}
+
+// replyText quotes the original message below the reply.
+func replyText(body string, message *entity.Message) string {
+	var builder strings.Builder
+	builder.WriteString(body)
+	builder.WriteString("\n\n")
+	builder.WriteString(fmt.Sprintf("On %s, %s wrote:\n", message.CreatedAt.Format("2 Jan 2006 15:04"), message.Name))
+	for _, line := range strings.Split(message.Body, "\n") {
+		builder.WriteString("> " + line + "\n")
+	}
+
+	return builder.String()
+}
+
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+
+	for limit > 0 && !utf8RuneStart(value[limit]) {
+		limit--
+	}
+
+	return value[:limit]
+}
+
+func utf8RuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func validateMessageRequest(validate *validator.Validate, request any, action string) error {
+	err := validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors []string
+	for _, e := range err.(validator.ValidationErrors) {
+		msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+		validationErrors = append(validationErrors, msg)
+	}
+
+	log.Printf("error %s message : %v", action, err)
+
+	return invalidMessageError(validationErrors...)
+}
+
+func invalidMessageError(details ...string) error {
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: details,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+func messageNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Message data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id message usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id message usecase:", err)
+	return fiber.ErrInternalServerError
+}
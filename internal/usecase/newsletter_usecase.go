@@ -0,0 +1,901 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	NewsletterTopicAnnouncements = "announcements"
+	NewsletterTopicKuliner       = "kuliner"
+	NewsletterTopicWisata        = "wisata"
+	NewsletterTopicEvents        = "events"
+
+	// confirmTokenTTL is how long an opt-in link stays valid, and
+	// confirmResendAfter how long a new sign-up of the same address waits
+	// before another confirmation mail goes out.
+	confirmTokenTTL    = 48 * time.Hour
+	confirmResendAfter = 10 * time.Minute
+	// digestLimit caps the items of each kind in one digest, the rest
+	// follows with the next one.
+	digestLimit = 20
+)
+
+// newsletterTopics lists the topics in the order the digest shows them.
+var newsletterTopics = []string{
+	NewsletterTopicAnnouncements,
+	NewsletterTopicKuliner,
+	NewsletterTopicWisata,
+	NewsletterTopicEvents,
+}
+
+type NewsletterUsecase interface {
+	Subscribe(ctx context.Context, request *model.SubscribeRequest) error
+	Confirm(ctx context.Context, token string) (*model.SubscriberResponse, error)
+	FindByToken(ctx context.Context, token string) (*model.SubscriberResponse, error)
+	UpdatePreferences(ctx context.Context, request *model.SubscriberPreferenceRequest) (*model.SubscriberResponse, error)
+	Unsubscribe(ctx context.Context, token string) error
+	FindAll(ctx context.Context, request *model.SubscriberFilterRequest) (*[]model.SubscriberResponse, error)
+	Delete(ctx context.Context, id uint, actorId uint) error
+	SendDigest(ctx context.Context, actorId uint) (*model.DigestResponse, error)
+	Notify()
+	Run(ctx context.Context)
+}
+
+// NewsletterConfig holds the links and schedule of the newsletter. The
+// confirm and preferences templates take a {token} placeholder and point at
+// the API itself unless a front-end page is configured.
+type NewsletterConfig struct {
+	Links               SiteLinks
+	APIURL              string
+	ConfirmTemplate     string
+	PreferencesTemplate string
+	SiteName            string
+	MailFrom            string
+	// DigestInterval is how often the digest goes out; zero turns the
+	// schedule off. With SendOnPublish a digest also goes out as soon as
+	// something new is published.
+	DigestInterval time.Duration
+	SendOnPublish  bool
+}
+
+// NewsletterUsecaseImpl keeps the subscribers and mails them digests of what
+// was published since their last one. Every subscriber has an own cursor,
+// LastSentAt, so a failed mail is simply tried again with the next digest.
+type NewsletterUsecaseImpl struct {
+	SubscriberRepo   repository.SubscriberRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	ContentRepo      repository.ContentRepository
+	EventRepo        repository.EventRepository
+	AdminRepo        repository.AdminRepository
+	DB               *gorm.DB
+	Validate         *validator.Validate
+	Mailer           util.Mailer
+	Config           NewsletterConfig
+
+	// digest serializes the digests, published wakes Run up
+	digest    sync.Mutex
+	published chan struct{}
+}
+
+func NewNewsletterUsecase(subscriberRepo repository.SubscriberRepository, announcementRepo repository.AnnouncementRepository, contentRepo repository.ContentRepository, eventRepo repository.EventRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate, mailer util.Mailer, config NewsletterConfig) NewsletterUsecase {
+	config.Links = config.Links.withDefaults()
+	config.APIURL = strings.TrimRight(config.APIURL, "/")
+	if config.ConfirmTemplate == "" {
+		config.ConfirmTemplate = config.APIURL + "/newsletter/confirm/{token}"
+	}
+	if config.PreferencesTemplate == "" {
+		config.PreferencesTemplate = config.APIURL + "/newsletter/preferences/{token}"
+	}
+	if config.SiteName == "" {
+		config.SiteName = "Web Profile"
+	}
+	if config.MailFrom == "" {
+		config.MailFrom = "no-reply@localhost"
+	}
+
+	return &NewsletterUsecaseImpl{
+		SubscriberRepo:   subscriberRepo,
+		AnnouncementRepo: announcementRepo,
+		ContentRepo:      contentRepo,
+		EventRepo:        eventRepo,
+		AdminRepo:        adminRepo,
+		DB:               DB,
+		Validate:         validate,
+		Mailer:           mailer,
+		Config:           config,
+		published:        make(chan struct{}, 1),
+	}
+}
+
+// Subscribe implements NewsletterUsecase. It answers the same whether or not
+// the address is already subscribed, so the form does not reveal who is.
+func (newsletterUsecase *NewsletterUsecaseImpl) Subscribe(ctx context.Context, request *model.SubscribeRequest) error {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := newsletterUsecase.validate(request, "subscribe"); err != nil {
+		return err
+	}
+
+	subscriber := &entity.Subscriber{Email: strings.ToLower(strings.TrimSpace(request.Email))}
+	err := newsletterUsecase.SubscriberRepo.FindByEmail(tx, subscriber)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("failed when find by email repo subscriber : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	now := time.Now()
+	found := err == nil
+
+	if found && subscriber.ConfirmedAt != nil {
+		log.Println("success subscribe from usecase newsletter : already confirmed")
+		return nil
+	}
+
+	if found && subscriber.ConfirmExpiresAt != nil && subscriber.ConfirmExpiresAt.Add(-confirmTokenTTL).Add(confirmResendAfter).After(now) {
+		log.Println("success subscribe from usecase newsletter : confirmation sent recently")
+		return nil
+	}
+
+	confirmToken := newToken()
+	confirmHash := hashToken(confirmToken)
+	expiresAt := now.Add(confirmTokenTTL)
+
+	subscriber.Name = strings.TrimSpace(request.Name)
+	subscriber.Topics = joinTopics(request.Topics)
+	subscriber.ConfirmToken = &confirmHash
+	subscriber.ConfirmExpiresAt = &expiresAt
+
+	if found {
+		err = newsletterUsecase.SubscriberRepo.Patch(tx, subscriber, []string{"name", "topics", "confirm_token", "confirm_expires_at"})
+	} else {
+		subscriber.Token = newToken()
+		err = newsletterUsecase.SubscriberRepo.Create(tx, subscriber)
+	}
+
+	if err != nil {
+		log.Println("failed when save repo subscriber : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	confirmURL := util.ExpandURLTemplate(newsletterUsecase.Config.ConfirmTemplate, map[string]string{"token": confirmToken})
+	text := fmt.Sprintf("Please confirm that you want to receive the newsletter of %s:\n\n%s\n\nThe link is valid for %d hours. If you did not sign up, simply ignore this mail.\n",
+		newsletterUsecase.Config.SiteName, confirmURL, int(confirmTokenTTL.Hours()))
+
+	if err := newsletterUsecase.Mailer.Send(ctx, &util.Mail{
+		From:    newsletterUsecase.Config.MailFrom,
+		To:      []string{subscriberAddress(subscriber)},
+		Subject: "Confirm your subscription to " + newsletterUsecase.Config.SiteName,
+		Text:    text,
+	}); err != nil {
+		log.Println("failed when send confirmation mail : ", err)
+		return mailFailedError("the confirmation mail was not sent, please try again later")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success subscribe from usecase newsletter")
+	return nil
+}
+
+// Confirm implements NewsletterUsecase. The digests of a new subscriber
+// start with what is published after the confirmation.
+func (newsletterUsecase *NewsletterUsecaseImpl) Confirm(ctx context.Context, token string) (*model.SubscriberResponse, error) {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	confirmHash := hashToken(token)
+	now := time.Now()
+
+	subscriber := &entity.Subscriber{ConfirmToken: &confirmHash}
+	if err := newsletterUsecase.SubscriberRepo.FindByConfirmToken(tx, subscriber, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorResponse := model.ErrorResponse{
+				Message: "Confirmation link is invalid or has expired",
+				Details: []string{},
+			}
+
+			jsonString, _ := json.Marshal(errorResponse)
+
+			log.Println("error confirm newsletter usecase : ", err)
+
+			return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+		}
+
+		log.Println("Error confirm newsletter usecase:", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	subscriber.ConfirmToken = nil
+	subscriber.ConfirmExpiresAt = nil
+	subscriber.ConfirmedAt = &now
+	subscriber.LastSentAt = &now
+
+	if err := newsletterUsecase.SubscriberRepo.Patch(tx, subscriber, []string{"confirm_token", "confirm_expires_at", "confirmed_at", "last_sent_at"}); err != nil {
+		log.Println("failed when patch repo subscriber : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success confirm from usecase newsletter")
+	return converter.SubscriberToResponse(subscriber), nil
+}
+
+// FindByToken implements NewsletterUsecase.
+func (newsletterUsecase *NewsletterUsecaseImpl) FindByToken(ctx context.Context, token string) (*model.SubscriberResponse, error) {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	subscriber := &entity.Subscriber{Token: token}
+	if err := newsletterUsecase.SubscriberRepo.FindByToken(tx, subscriber); err != nil {
+		return nil, subscriberNotFoundError(err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find by token from usecase newsletter")
+	return converter.SubscriberToResponse(subscriber), nil
+}
+
+// UpdatePreferences implements NewsletterUsecase.
+func (newsletterUsecase *NewsletterUsecaseImpl) UpdatePreferences(ctx context.Context, request *model.SubscriberPreferenceRequest) (*model.SubscriberResponse, error) {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := newsletterUsecase.validate(request, "update preferences"); err != nil {
+		return nil, err
+	}
+
+	subscriber := &entity.Subscriber{Token: request.Token}
+	if err := newsletterUsecase.SubscriberRepo.FindByToken(tx, subscriber); err != nil {
+		return nil, subscriberNotFoundError(err)
+	}
+
+	columns := []string{"topics"}
+	subscriber.Topics = joinTopics(request.Topics)
+	if request.Name != nil {
+		subscriber.Name = strings.TrimSpace(*request.Name)
+		columns = append(columns, "name")
+	}
+
+	if err := newsletterUsecase.SubscriberRepo.Patch(tx, subscriber, columns); err != nil {
+		log.Println("failed when patch repo subscriber : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success update preferences from usecase newsletter")
+	return converter.SubscriberToResponse(subscriber), nil
+}
+
+// Unsubscribe implements NewsletterUsecase. The subscriber is removed
+// altogether; unsubscribing twice is not an error, as mail clients may
+// retry a one-click unsubscribe.
+func (newsletterUsecase *NewsletterUsecaseImpl) Unsubscribe(ctx context.Context, token string) error {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	subscriber := &entity.Subscriber{Token: token}
+	if err := newsletterUsecase.SubscriberRepo.FindByToken(tx, subscriber); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("success unsubscribe from usecase newsletter : already gone")
+			return nil
+		}
+
+		log.Println("failed when find by token repo subscriber : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := newsletterUsecase.SubscriberRepo.Delete(tx, &entity.Subscriber{ID: subscriber.ID}); err != nil {
+		log.Println("failed when delete repo subscriber : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success unsubscribe from usecase newsletter")
+	return nil
+}
+
+// FindAll implements NewsletterUsecase.
+func (newsletterUsecase *NewsletterUsecaseImpl) FindAll(ctx context.Context, request *model.SubscriberFilterRequest) (*[]model.SubscriberResponse, error) {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := newsletterUsecase.validate(request, "find all"); err != nil {
+		return nil, err
+	}
+
+	if err := newsletterUsecase.checkAdmin(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	var subscribers = &[]entity.Subscriber{}
+	if err := newsletterUsecase.SubscriberRepo.FindAll(tx, request.Status, subscribers); err != nil {
+		log.Println("failed when find all repo subscriber : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase newsletter")
+	return converter.SubscriberToResponses(subscribers), nil
+}
+
+// Delete implements NewsletterUsecase.
+func (newsletterUsecase *NewsletterUsecaseImpl) Delete(ctx context.Context, id uint, actorId uint) error {
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := newsletterUsecase.checkAdmin(tx, actorId); err != nil {
+		return err
+	}
+
+	subscriber := &entity.Subscriber{ID: id}
+	if err := newsletterUsecase.SubscriberRepo.FindById(tx, subscriber); err != nil {
+		return subscriberNotFoundError(err)
+	}
+
+	if err := newsletterUsecase.SubscriberRepo.Delete(tx, &entity.Subscriber{ID: id}); err != nil {
+		log.Println("failed when delete repo subscriber : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success delete from usecase newsletter")
+	return nil
+}
+
+// SendDigest implements NewsletterUsecase.
+func (newsletterUsecase *NewsletterUsecaseImpl) SendDigest(ctx context.Context, actorId uint) (*model.DigestResponse, error) {
+	if err := newsletterUsecase.checkAdmin(newsletterUsecase.DB.WithContext(ctx), actorId); err != nil {
+		return nil, err
+	}
+
+	return newsletterUsecase.sendDigest(ctx)
+}
+
+// sendDigest mails the digests. Subscribers with nothing new in their
+// topics get no mail. A cursor only moves as far as the digest sent
+// reached, so items beyond digestLimit are not skipped but sent next time.
+func (newsletterUsecase *NewsletterUsecaseImpl) sendDigest(ctx context.Context) (*model.DigestResponse, error) {
+	newsletterUsecase.digest.Lock()
+	defer newsletterUsecase.digest.Unlock()
+
+	// items published while the digest is composed go into the next one
+	startedAt := time.Now()
+
+	tx := newsletterUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var subscribers = &[]entity.Subscriber{}
+	if err := newsletterUsecase.SubscriberRepo.FindConfirmed(tx, subscribers); err != nil {
+		log.Println("failed when find confirmed repo subscriber : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	response := &model.DigestResponse{}
+	if len(*subscribers) == 0 {
+		return response, nil
+	}
+
+	// subscribers mostly share the cursor of the last digest, so the items
+	// are looked up once per distinct cursor
+	batches := map[int64]*digestBatch{}
+	for _, subscriber := range *subscribers {
+		cursor := digestCursor(&subscriber)
+		if batches[cursor.UnixNano()] != nil {
+			continue
+		}
+
+		batch, err := newsletterUsecase.findDigestBatch(tx, cursor, startedAt)
+		if err != nil {
+			return nil, err
+		}
+		batches[cursor.UnixNano()] = batch
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for _, subscriber := range *subscribers {
+		batch := batches[digestCursor(&subscriber).UnixNano()]
+		until := batch.until(&subscriber, startedAt)
+
+		sections := digestSections(batch.items, &subscriber, until)
+		if len(sections) == 0 {
+			continue
+		}
+
+		if err := newsletterUsecase.Mailer.Send(ctx, newsletterUsecase.digestMail(&subscriber, sections)); err != nil {
+			log.Println("failed when send digest mail : ", err)
+			response.Failed++
+			continue
+		}
+
+		sentAt := until
+		if err := newsletterUsecase.SubscriberRepo.Patch(newsletterUsecase.DB.WithContext(ctx), &entity.Subscriber{ID: subscriber.ID, LastSentAt: &sentAt}, []string{"last_sent_at"}); err != nil {
+			log.Println("failed when patch repo subscriber : ", err)
+		}
+		response.Sent++
+	}
+
+	log.Printf("success send digest from usecase newsletter : %d sent, %d failed", response.Sent, response.Failed)
+	return response, nil
+}
+
+// Notify implements NewsletterUsecase. It is called after something was
+// published and only wakes Run up when digests go out on publish.
+func (newsletterUsecase *NewsletterUsecaseImpl) Notify() {
+	if !newsletterUsecase.Config.SendOnPublish {
+		return
+	}
+
+	select {
+	case newsletterUsecase.published <- struct{}{}:
+	default:
+		// a digest is already due
+	}
+}
+
+// Run implements NewsletterUsecase.
+func (newsletterUsecase *NewsletterUsecaseImpl) Run(ctx context.Context) {
+	var schedule <-chan time.Time
+	if newsletterUsecase.Config.DigestInterval > 0 {
+		ticker := time.NewTicker(newsletterUsecase.Config.DigestInterval)
+		defer ticker.Stop()
+		schedule = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-schedule:
+		case <-newsletterUsecase.published:
+		}
+
+		// a digest under way is finished, so its mails are not sent twice
+		if _, err := newsletterUsecase.sendDigest(context.WithoutCancel(ctx)); err != nil {
+			log.Println("failed to send digest : ", err)
+		}
+	}
+}
+
+// checkAdmin lets only admins see the subscribers, whose addresses are
+// personal data, or remove them and send digests by hand.
+func (newsletterUsecase *NewsletterUsecaseImpl) checkAdmin(tx *gorm.DB, actorId uint) error {
+	actor, err := findActor(tx, newsletterUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	if actor.Role != AdminRoleAdmin {
+		return forbiddenError("only admins can manage subscribers")
+	}
+
+	return nil
+}
+
+type digestItem struct {
+	topic     string
+	title     string
+	link      string
+	summary   string
+	createdAt time.Time
+}
+
+type digestSection struct {
+	title string
+	items []digestItem
+}
+
+// digestBatch holds the items published since one cursor, oldest first. A
+// kind with more items than a digest takes is cut off after the last one
+// taken, recorded in truncatedAt for its topics.
+type digestBatch struct {
+	items       []digestItem
+	truncatedAt map[string]time.Time
+}
+
+// until is how far a digest from the batch reaches for subscriber: up to
+// startedAt, or to where one of their topics was cut off.
+func (batch *digestBatch) until(subscriber *entity.Subscriber, startedAt time.Time) time.Time {
+	until := startedAt
+	for topic, chosen := range splitTopics(subscriber.Topics) {
+		if truncatedAt, ok := batch.truncatedAt[topic]; chosen && ok && truncatedAt.Before(until) {
+			until = truncatedAt
+		}
+	}
+
+	return until
+}
+
+// findDigestBatch looks up what was published after since and is visible
+// at now, one item more than a digest takes to tell whether there is more.
+func (newsletterUsecase *NewsletterUsecaseImpl) findDigestBatch(tx *gorm.DB, since time.Time, now time.Time) (*digestBatch, error) {
+	batch := &digestBatch{truncatedAt: map[string]time.Time{}}
+
+	var announcements = &[]entity.Announcement{}
+	if err := newsletterUsecase.AnnouncementRepo.FindCreatedSince(tx, since, now, digestLimit+1, announcements); err != nil {
+		log.Println("failed when find created since repo announcement : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if len(*announcements) > digestLimit {
+		times := make([]time.Time, len(*announcements))
+		for i := range *announcements {
+			times[i] = announcementVisibleAt(&(*announcements)[i])
+		}
+
+		kept, until := digestCut(times, digestLimit)
+		*announcements = (*announcements)[:kept]
+		batch.truncatedAt[NewsletterTopicAnnouncements] = until
+	}
+
+	var contents = &[]entity.Content{}
+	if err := newsletterUsecase.ContentRepo.FindCreatedSince(tx, []string{NewsletterTopicKuliner, NewsletterTopicWisata}, since, digestLimit*2+1, contents); err != nil {
+		log.Println("failed when find created since repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if len(*contents) > digestLimit*2 {
+		times := make([]time.Time, len(*contents))
+		for i, content := range *contents {
+			times[i] = content.CreatedAt
+		}
+
+		kept, until := digestCut(times, digestLimit*2)
+		*contents = (*contents)[:kept]
+		batch.truncatedAt[NewsletterTopicKuliner] = until
+		batch.truncatedAt[NewsletterTopicWisata] = until
+	}
+
+	var events = &[]entity.Event{}
+	if err := newsletterUsecase.EventRepo.FindCreatedSince(tx, since, digestLimit+1, events); err != nil {
+		log.Println("failed when find created since repo event : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if len(*events) > digestLimit {
+		times := make([]time.Time, len(*events))
+		for i, event := range *events {
+			times[i] = event.CreatedAt
+		}
+
+		kept, until := digestCut(times, digestLimit)
+		*events = (*events)[:kept]
+		batch.truncatedAt[NewsletterTopicEvents] = until
+	}
+
+	// events are listed by when they take place
+	sort.SliceStable(*events, func(i, j int) bool {
+		return (*events)[i].StartsAt.Before((*events)[j].StartsAt)
+	})
+
+	batch.items = newsletterUsecase.digestItems(announcements, contents, events)
+	return batch, nil
+}
+
+func (newsletterUsecase *NewsletterUsecaseImpl) digestItems(announcements *[]entity.Announcement, contents *[]entity.Content, events *[]entity.Event) []digestItem {
+	var items []digestItem
+	links := newsletterUsecase.Config.Links
+
+	for _, announcement := range *announcements {
+		items = append(items, digestItem{
+			topic:     NewsletterTopicAnnouncements,
+			title:     announcement.Title,
+			link:      links.Announcement(announcement.ID, util.DefaultLocale),
+			summary:   util.GenerateExcerpt(util.RenderContentHTML(announcement.ContentFormat, announcement.Content)),
+			createdAt: announcementVisibleAt(&announcement),
+		})
+	}
+
+	for _, content := range *contents {
+		items = append(items, digestItem{
+			topic:     content.Category,
+			title:     content.Title,
+			link:      links.Content(content.ID, content.Category, util.DefaultLocale),
+			summary:   util.GenerateExcerpt(util.RenderContentHTML(content.ContentFormat, content.Content)),
+			createdAt: content.CreatedAt,
+		})
+	}
+
+	for _, event := range *events {
+		when := event.StartsAt.Format("Monday, 2 January 2006 15:04")
+		if event.AllDay {
+			when = event.StartsAt.Format("Monday, 2 January 2006")
+		}
+
+		summary := when
+		if event.Location != "" {
+			summary += " - " + event.Location
+		}
+
+		items = append(items, digestItem{
+			topic:     NewsletterTopicEvents,
+			title:     event.Title,
+			link:      newsletterUsecase.Config.APIURL + "/events/" + strconv.FormatUint(uint64(event.ID), 10) + ".ics",
+			summary:   summary,
+			createdAt: event.CreatedAt,
+		})
+	}
+
+	return items
+}
+
+// digestCut tells how many of the times of a kind, found oldest first with
+// one more than limit, a digest takes and the time it reaches up to. The
+// cut falls before the first item left out, so the items created in the
+// same second go out together next time, unless all of them were.
+func digestCut(times []time.Time, limit int) (int, time.Time) {
+	kept := limit
+	for kept > 0 && !times[kept-1].Before(times[limit]) {
+		kept--
+	}
+
+	if kept == 0 {
+		kept = limit
+	}
+
+	return kept, times[kept-1]
+}
+
+// digestSections picks the items a subscriber has not been sent yet, up to
+// until, grouped by topic.
+func digestSections(items []digestItem, subscriber *entity.Subscriber, until time.Time) []digestSection {
+	cursor := digestCursor(subscriber)
+	topics := splitTopics(subscriber.Topics)
+
+	var sections []digestSection
+	for _, topic := range newsletterTopics {
+		if !topics[topic] {
+			continue
+		}
+
+		section := digestSection{title: topicTitle(topic)}
+		for _, item := range items {
+			if item.topic == topic && item.createdAt.After(cursor) && !item.createdAt.After(until) {
+				section.items = append(section.items, item)
+			}
+		}
+
+		if len(section.items) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+// digestMail renders a digest as text and HTML with the unsubscribe link in
+// the body and, for one-click unsubscribing (RFC 8058), in the headers.
+func (newsletterUsecase *NewsletterUsecaseImpl) digestMail(subscriber *entity.Subscriber, sections []digestSection) *util.Mail {
+	config := newsletterUsecase.Config
+	unsubscribeURL := config.APIURL + "/newsletter/unsubscribe/" + subscriber.Token
+	preferencesURL := util.ExpandURLTemplate(config.PreferencesTemplate, map[string]string{"token": subscriber.Token})
+
+	var text, body strings.Builder
+	text.WriteString("What is new at " + config.SiteName + "\n")
+	body.WriteString("<h1>What is new at " + html.EscapeString(config.SiteName) + "</h1>\n")
+
+	for _, section := range sections {
+		text.WriteString("\n" + section.title + "\n" + strings.Repeat("=", len(section.title)) + "\n\n")
+		body.WriteString("<h2>" + html.EscapeString(section.title) + "</h2>\n<ul>\n")
+
+		for _, item := range section.items {
+			text.WriteString("* " + item.title + "\n")
+			if item.summary != "" {
+				text.WriteString("  " + item.summary + "\n")
+			}
+			text.WriteString("  " + item.link + "\n\n")
+
+			body.WriteString(`<li><a href="` + html.EscapeString(item.link) + `">` + html.EscapeString(item.title) + "</a>")
+			if item.summary != "" {
+				body.WriteString("<br>" + html.EscapeString(item.summary))
+			}
+			body.WriteString("</li>\n")
+		}
+
+		body.WriteString("</ul>\n")
+	}
+
+	text.WriteString("--\nChange your topics: " + preferencesURL + "\nUnsubscribe: " + unsubscribeURL + "\n")
+	body.WriteString(`<hr><p><a href="` + html.EscapeString(preferencesURL) + `">Change your topics</a> | <a href="` + html.EscapeString(unsubscribeURL) + `">Unsubscribe</a></p>` + "\n")
+
+	return &util.Mail{
+		From:    config.MailFrom,
+		To:      []string{subscriberAddress(subscriber)},
+		Subject: "News from " + config.SiteName,
+		Text:    text.String(),
+		HTML:    body.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}
+}
+
+func (newsletterUsecase *NewsletterUsecaseImpl) validate(request any, action string) error {
+	err := newsletterUsecase.Validate.Struct(request)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors []string
+	for _, e := range err.(validator.ValidationErrors) {
+		msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+		validationErrors = append(validationErrors, msg)
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: validationErrors,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	log.Printf("error %s newsletter : %v", action, err)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+// digestCursor is when the last digest of a subscriber was composed.
+// announcementVisibleAt is when an announcement counts as new: one
+// scheduled ahead once its validity window opened.
+func announcementVisibleAt(announcement *entity.Announcement) time.Time {
+	if announcement.ValidFrom != nil && announcement.ValidFrom.After(announcement.CreatedAt) {
+		return *announcement.ValidFrom
+	}
+
+	return announcement.CreatedAt
+}
+
+func digestCursor(subscriber *entity.Subscriber) time.Time {
+	if subscriber.LastSentAt != nil {
+		return *subscriber.LastSentAt
+	}
+
+	if subscriber.ConfirmedAt != nil {
+		return *subscriber.ConfirmedAt
+	}
+
+	return subscriber.CreatedAt
+}
+
+// joinTopics stores the chosen topics in their canonical order; no choice
+// at all means every topic.
+func joinTopics(topics []string) string {
+	if len(topics) == 0 {
+		return strings.Join(newsletterTopics, ",")
+	}
+
+	chosen := map[string]bool{}
+	for _, topic := range topics {
+		chosen[topic] = true
+	}
+
+	var joined []string
+	for _, topic := range newsletterTopics {
+		if chosen[topic] {
+			joined = append(joined, topic)
+		}
+	}
+
+	return strings.Join(joined, ",")
+}
+
+func splitTopics(topics string) map[string]bool {
+	split := map[string]bool{}
+	for _, topic := range strings.Split(topics, ",") {
+		split[topic] = true
+	}
+
+	return split
+}
+
+func topicTitle(topic string) string {
+	switch topic {
+	case NewsletterTopicAnnouncements:
+		return "Announcements"
+	case NewsletterTopicEvents:
+		return "Events"
+	default:
+		return strings.ToUpper(topic[:1]) + topic[1:]
+	}
+}
+
+func subscriberAddress(subscriber *entity.Subscriber) string {
+	return (&mail.Address{Name: subscriber.Name, Address: subscriber.Email}).String()
+}
+
+// newToken returns 32 random bytes as hex, the size of the token columns.
+func newToken() string {
+	buffer := make([]byte, 32)
+	_, _ = rand.Read(buffer)
+
+	return hex.EncodeToString(buffer)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func mailFailedError(detail string) error {
+	errorResponse := model.ErrorResponse{
+		Message: "Mail could not be sent",
+		Details: []string{detail},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.StatusBadGateway, string(jsonString))
+}
+
+func subscriberNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Subscriber data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find subscriber usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find subscriber usecase:", err)
+	return fiber.ErrInternalServerError
+}
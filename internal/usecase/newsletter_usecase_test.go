@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+)
+
+func TestDigestSections(t *testing.T) {
+	base := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	items := []digestItem{
+		{topic: NewsletterTopicAnnouncements, title: "old notice", createdAt: at(-1)},
+		{topic: NewsletterTopicAnnouncements, title: "notice", createdAt: at(1)},
+		{topic: NewsletterTopicKuliner, title: "warung", createdAt: at(2)},
+		{topic: NewsletterTopicWisata, title: "pantai", createdAt: at(3)},
+		{topic: NewsletterTopicEvents, title: "festival", createdAt: at(4)},
+		{topic: NewsletterTopicAnnouncements, title: "late notice", createdAt: at(6)},
+	}
+
+	tests := []struct {
+		name   string
+		topics string
+		until  time.Time
+		want   map[string][]string
+	}{
+		{
+			name:   "all topics",
+			topics: "announcements,kuliner,wisata,events",
+			until:  at(10),
+			want: map[string][]string{
+				"Announcements": {"notice", "late notice"},
+				"Kuliner":       {"warung"},
+				"Wisata":        {"pantai"},
+				"Events":        {"festival"},
+			},
+		},
+		{
+			name:   "chosen topics only",
+			topics: "wisata,events",
+			until:  at(10),
+			want: map[string][]string{
+				"Wisata": {"pantai"},
+				"Events": {"festival"},
+			},
+		},
+		{
+			name:   "up to until",
+			topics: "announcements,events",
+			until:  at(4),
+			want: map[string][]string{
+				"Announcements": {"notice"},
+				"Events":        {"festival"},
+			},
+		},
+		{
+			name:   "nothing new",
+			topics: "kuliner",
+			until:  at(1),
+			want:   map[string][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			subscriber := &entity.Subscriber{Topics: test.topics, LastSentAt: &base}
+
+			got := map[string][]string{}
+			for _, section := range digestSections(items, subscriber, test.until) {
+				for _, item := range section.items {
+					got[section.title] = append(got[section.title], item.title)
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("digestSections() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDigestSectionsOrder(t *testing.T) {
+	base := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	subscriber := &entity.Subscriber{Topics: "events,announcements", LastSentAt: &base}
+
+	items := []digestItem{
+		{topic: NewsletterTopicEvents, title: "festival", createdAt: base.Add(time.Hour)},
+		{topic: NewsletterTopicAnnouncements, title: "notice", createdAt: base.Add(time.Hour)},
+	}
+
+	sections := digestSections(items, subscriber, base.Add(time.Hour))
+
+	var titles []string
+	for _, section := range sections {
+		titles = append(titles, section.title)
+	}
+
+	if want := []string{"Announcements", "Events"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("sections = %v, want %v", titles, want)
+	}
+}
+
+func TestDigestCut(t *testing.T) {
+	base := time.Date(2025, 8, 1, 9, 0, 0, 0, time.UTC)
+	seconds := func(offsets ...int) []time.Time {
+		times := make([]time.Time, len(offsets))
+		for i, offset := range offsets {
+			times[i] = base.Add(time.Duration(offset) * time.Second)
+		}
+		return times
+	}
+
+	tests := []struct {
+		name  string
+		times []time.Time
+		limit int
+		kept  int
+		until time.Time
+	}{
+		{"distinct times", seconds(1, 2, 3, 4), 3, 3, base.Add(3 * time.Second)},
+		{"same second as the one left out", seconds(1, 2, 3, 3), 3, 2, base.Add(2 * time.Second)},
+		{"run of the same second", seconds(1, 2, 2, 2), 3, 1, base.Add(1 * time.Second)},
+		{"all in one second", seconds(5, 5, 5, 5), 3, 3, base.Add(5 * time.Second)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kept, until := digestCut(test.times, test.limit)
+			if kept != test.kept || !until.Equal(test.until) {
+				t.Errorf("digestCut() = %d, %v, want %d, %v", kept, until, test.kept, test.until)
+			}
+		})
+	}
+}
+
+func TestDigestBatchUntil(t *testing.T) {
+	startedAt := time.Date(2025, 8, 2, 9, 0, 0, 0, time.UTC)
+	cut := startedAt.Add(-time.Hour)
+
+	batch := &digestBatch{truncatedAt: map[string]time.Time{NewsletterTopicKuliner: cut}}
+
+	tests := []struct {
+		topics string
+		want   time.Time
+	}{
+		{"announcements,events", startedAt},
+		{"kuliner", cut},
+		{"announcements,kuliner", cut},
+	}
+
+	for _, test := range tests {
+		if got := batch.until(&entity.Subscriber{Topics: test.topics}, startedAt); !got.Equal(test.want) {
+			t.Errorf("until(%q) = %v, want %v", test.topics, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	relatedContentLimit    = 6
+	relatedContentCacheTTL = time.Hour
+
+	relatedWeightCategory = 3.0
+	relatedWeightTag      = 4.0
+	relatedWeightTerms    = 5.0
+	relatedWeightDistance = 3.0
+	// distance at which the proximity score has dropped to a half
+	relatedDistanceHalfKm = 5.0
+	// listings farther away than this only score by distance as much as a
+	// sixteenth of its weight, they are no candidates for it
+	relatedNearbyKm = 4 * relatedDistanceHalfKm
+	// relatedCandidateLimit bounds the listings scored for one request
+	relatedCandidateLimit = 200
+)
+
+type RelatedContentUsecase interface {
+	FindRelated(ctx context.Context, contentId uint, locale string) (*[]model.ContentResponse, error)
+	Invalidate(contentIds ...uint)
+}
+
+type relatedCacheKey struct {
+	contentId uint
+	locale    string
+}
+
+type relatedCacheEntry struct {
+	responses *[]model.ContentResponse
+	// ids are the listings shown, whose changes make the entry stale
+	ids       []uint
+	expiresAt time.Time
+}
+
+// relatedTermsEntry keeps the term vector of a listing as of its last edit.
+type relatedTermsEntry struct {
+	updatedAt time.Time
+	terms     map[string]float64
+}
+
+type RelatedContentUsecaseImpl struct {
+	ContentRepo repository.ContentRepository
+	DB          *gorm.DB
+
+	mutex sync.RWMutex
+	cache map[relatedCacheKey]relatedCacheEntry
+	terms map[uint]relatedTermsEntry
+}
+
+func NewRelatedContentUsecase(contentRepo repository.ContentRepository, DB *gorm.DB) RelatedContentUsecase {
+	return &RelatedContentUsecaseImpl{
+		ContentRepo: contentRepo,
+		DB:          DB,
+		cache:       map[relatedCacheKey]relatedCacheEntry{},
+		terms:       map[uint]relatedTermsEntry{},
+	}
+}
+
+// FindRelated implements RelatedContentUsecase.
+func (relatedContentUsecase *RelatedContentUsecaseImpl) FindRelated(ctx context.Context, contentId uint, locale string) (*[]model.ContentResponse, error) {
+	key := relatedCacheKey{contentId: contentId, locale: locale}
+
+	relatedContentUsecase.mutex.RLock()
+	entry, ok := relatedContentUsecase.cache[key]
+	relatedContentUsecase.mutex.RUnlock()
+
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.responses, nil
+	}
+
+	tx := relatedContentUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	content := &entity.Content{ID: contentId}
+	if err := relatedContentUsecase.ContentRepo.FindById(tx, content); err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	var candidates = &[]entity.Content{}
+	if err := relatedContentUsecase.ContentRepo.FindRelatedCandidates(tx, content, relatedNearbyKm, relatedCandidateLimit, candidates); err != nil {
+		log.Println("failed when find related candidates repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	ids := rankRelatedContents(content, *candidates, relatedContentUsecase.termsOf)
+
+	var related = []entity.Content{}
+	if len(ids) > 0 {
+		var found = &[]entity.Content{}
+		if err := relatedContentUsecase.ContentRepo.FindByIds(tx, ids, found); err != nil {
+			log.Println("failed when find by ids repo content : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		// in the order of the ranking, leaving out any deleted meanwhile
+		byId := make(map[uint]entity.Content, len(*found))
+		for _, item := range *found {
+			byId[item.ID] = item
+		}
+		for _, id := range ids {
+			if item, ok := byId[id]; ok {
+				related = append(related, item)
+			}
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	for i := range related {
+		localizeContent(&related[i], locale)
+	}
+
+	responses := converter.ContentToResponses(&related)
+
+	relatedContentUsecase.mutex.Lock()
+	relatedContentUsecase.cache[key] = relatedCacheEntry{
+		responses: responses,
+		ids:       ids,
+		expiresAt: time.Now().Add(relatedContentCacheTTL),
+	}
+	relatedContentUsecase.mutex.Unlock()
+
+	log.Println("success find related from usecase content")
+	return responses, nil
+}
+
+// Invalidate implements RelatedContentUsecase. It drops the results of the
+// changed listings and the results showing them. A listing that became
+// related to another one shows up there once that entry expires.
+func (relatedContentUsecase *RelatedContentUsecaseImpl) Invalidate(contentIds ...uint) {
+	changed := make(map[uint]bool, len(contentIds))
+	for _, id := range contentIds {
+		changed[id] = true
+	}
+
+	relatedContentUsecase.mutex.Lock()
+	defer relatedContentUsecase.mutex.Unlock()
+
+	for key, entry := range relatedContentUsecase.cache {
+		if changed[key.contentId] || slices.ContainsFunc(entry.ids, func(id uint) bool { return changed[id] }) {
+			delete(relatedContentUsecase.cache, key)
+		}
+	}
+
+	for id := range changed {
+		delete(relatedContentUsecase.terms, id)
+	}
+}
+
+// termsOf returns the term vector of a listing, rendering its body only when
+// it was edited since the vector was cached.
+func (relatedContentUsecase *RelatedContentUsecaseImpl) termsOf(content *entity.Content) map[string]float64 {
+	relatedContentUsecase.mutex.RLock()
+	entry, ok := relatedContentUsecase.terms[content.ID]
+	relatedContentUsecase.mutex.RUnlock()
+
+	if ok && entry.updatedAt.Equal(content.UpdatedAt) {
+		return entry.terms
+	}
+
+	terms := relatedTerms(content)
+
+	relatedContentUsecase.mutex.Lock()
+	relatedContentUsecase.terms[content.ID] = relatedTermsEntry{updatedAt: content.UpdatedAt, terms: terms}
+	relatedContentUsecase.mutex.Unlock()
+
+	return terms
+}
+
+// rankRelatedContents scores every candidate against content and returns the
+// ids of the best ones. The score adds up a shared category, the overlap of
+// the tags, the similarity of the words in title and body and, when both
+// listings have coordinates, how close they are to each other.
+func rankRelatedContents(content *entity.Content, candidates []entity.Content, termsOf func(content *entity.Content) map[string]float64) []uint {
+	type scored struct {
+		id    uint
+		score float64
+	}
+
+	terms := termsOf(content)
+	tags := map[string]bool{}
+	for _, tag := range content.Tags {
+		tags[tag.Tag] = true
+	}
+
+	var results []scored
+	for _, candidate := range candidates {
+		var score float64
+
+		if candidate.Category == content.Category {
+			score += relatedWeightCategory
+		}
+
+		if len(tags) > 0 && len(candidate.Tags) > 0 {
+			var shared int
+			for _, tag := range candidate.Tags {
+				if tags[tag.Tag] {
+					shared++
+				}
+			}
+			union := len(tags) + len(candidate.Tags) - shared
+			score += relatedWeightTag * float64(shared) / float64(union)
+		}
+
+		score += relatedWeightTerms * util.CosineSimilarity(terms, termsOf(&candidate))
+
+		if content.Latitude != nil && content.Longitude != nil && candidate.Latitude != nil && candidate.Longitude != nil {
+			distance := util.DistanceKm(*content.Latitude, *content.Longitude, *candidate.Latitude, *candidate.Longitude)
+			score += relatedWeightDistance * math.Exp2(-distance/relatedDistanceHalfKm)
+		}
+
+		if score > 0 {
+			results = append(results, scored{id: candidate.ID, score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	var ids []uint
+	for i := 0; i < len(results) && i < relatedContentLimit; i++ {
+		ids = append(ids, results[i].id)
+	}
+
+	return ids
+}
+
+func relatedTerms(content *entity.Content) map[string]float64 {
+	terms := util.TermFrequencies(util.PlainText(util.RenderContentHTML(content.ContentFormat, content.Content)))
+
+	// words of the title say more about a listing than words of the body
+	for term, weight := range util.TermFrequencies(content.Title) {
+		terms[term] += 2 * weight
+	}
+
+	return terms
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRelatedContentInvalidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		changed []uint
+		kept    []uint
+	}{
+		{"nothing changed", nil, []uint{1, 2, 3}},
+		{"listing with results", []uint{1}, []uint{2, 3}},
+		{"listing shown in results", []uint{4}, []uint{2, 3}},
+		{"listing shown nowhere", []uint{9}, []uint{1, 2, 3}},
+		{"many listings", []uint{5, 3}, []uint{1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expiresAt := time.Now().Add(time.Hour)
+			relatedContentUsecase := &RelatedContentUsecaseImpl{
+				cache: map[relatedCacheKey]relatedCacheEntry{
+					{contentId: 1, locale: "id"}: {ids: []uint{4}, expiresAt: expiresAt},
+					{contentId: 2, locale: "en"}: {ids: []uint{5}, expiresAt: expiresAt},
+					{contentId: 3, locale: "id"}: {expiresAt: expiresAt},
+				},
+				terms: map[uint]relatedTermsEntry{1: {}, 9: {}},
+			}
+
+			relatedContentUsecase.Invalidate(test.changed...)
+
+			var kept []uint
+			for key := range relatedContentUsecase.cache {
+				kept = append(kept, key.contentId)
+			}
+			sort.Slice(kept, func(i, j int) bool { return kept[i] < kept[j] })
+
+			if !reflect.DeepEqual(kept, test.kept) {
+				t.Errorf("kept = %v, want %v", kept, test.kept)
+			}
+
+			for _, id := range test.changed {
+				if _, ok := relatedContentUsecase.terms[id]; ok {
+					t.Errorf("terms of %d kept, want them dropped", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type ReviewUsecase interface {
+	Create(ctx context.Context, request *model.ReviewCreateRequest) (*model.ReviewResponse, error)
+	Moderate(ctx context.Context, request *model.ReviewModerateRequest) (*model.ReviewResponse, error)
+	Delete(ctx context.Context, reviewId uint) error
+	FindAll(ctx context.Context, status string) (*[]model.ReviewResponse, error)
+	FindByContentId(ctx context.Context, contentId uint) (*[]model.ReviewResponse, error)
+}
+
+type ReviewUsecaseImpl struct {
+	ReviewRepo            repository.ReviewRepository
+	ContentRepo           repository.ContentRepository
+	RelatedContentUsecase RelatedContentUsecase
+	DB                    *gorm.DB
+	Validate              *validator.Validate
+}
+
+func NewReviewUsecase(reviewRepo repository.ReviewRepository, contentRepo repository.ContentRepository, relatedContentUsecase RelatedContentUsecase, DB *gorm.DB, validate *validator.Validate) ReviewUsecase {
+	return &ReviewUsecaseImpl{
+		ReviewRepo:            reviewRepo,
+		ContentRepo:           contentRepo,
+		RelatedContentUsecase: relatedContentUsecase,
+		DB:                    DB,
+		Validate:              validate,
+	}
+}
+
+// Create implements ReviewUsecase.
+func (reviewUsecase *ReviewUsecaseImpl) Create(ctx context.Context, request *model.ReviewCreateRequest) (*model.ReviewResponse, error) {
+	tx := reviewUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	errorResponse := &model.ErrorResponse{}
+
+	err := reviewUsecase.Validate.Struct(request)
+	if err != nil {
+		var validationErrors []string
+		for _, e := range err.(validator.ValidationErrors) {
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = validationErrors
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error create review : ", err)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	content := &entity.Content{ID: request.ContentID}
+	if err := reviewUsecase.ContentRepo.FindById(tx, content); err != nil {
+		return nil, contentNotFoundError(err)
+	}
+
+	if content.Category != "kuliner" && content.Category != "wisata" {
+		errorResponse.Message = "invalid request parameter"
+		errorResponse.Details = []string{"only kuliner and wisata contents can be reviewed"}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		return nil, fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+	}
+
+	review := &entity.Review{
+		ContentID:       request.ContentID,
+		Rating:          request.Rating,
+		Body:            strings.TrimSpace(request.Body),
+		ReviewerName:    strings.TrimSpace(request.ReviewerName),
+		ReviewerContact: strings.TrimSpace(request.ReviewerContact),
+		Status:          "pending",
+	}
+
+	if err := reviewUsecase.ReviewRepo.Create(tx, review); err != nil {
+		log.Println("failed when create repo review : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success create from usecase review")
+	return converter.ReviewToPublicResponse(review), nil
+}
+
+// Moderate implements ReviewUsecase.
+func (reviewUsecase *ReviewUsecaseImpl) Moderate(ctx context.Context, request *model.ReviewModerateRequest) (*model.ReviewResponse, error) {
+	tx := reviewUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := reviewUsecase.Validate.Struct(request); err != nil {
+		log.Println("error moderate review : ", err)
+		return nil, fiber.ErrBadRequest
+	}
+
+	review := &entity.Review{ID: request.ID}
+	if err := reviewUsecase.ReviewRepo.FindById(tx, review); err != nil {
+		return nil, reviewNotFoundError(err)
+	}
+
+	now := time.Now()
+	review.Status = request.Status
+	review.ModeratedBy = &request.ModeratedBy
+	review.ModeratedAt = &now
+
+	if err := reviewUsecase.ReviewRepo.UpdateStatus(tx, review); err != nil {
+		log.Println("failed when update repo review : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := reviewUsecase.ContentRepo.RefreshRating(tx, review.ContentID); err != nil {
+		log.Println("failed when refresh rating repo content : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// the related listings show the rating of the content
+	reviewUsecase.RelatedContentUsecase.Invalidate(review.ContentID)
+
+	log.Println("success moderate from usecase review")
+	return converter.ReviewToResponse(review), nil
+}
+
+// Delete implements ReviewUsecase.
+func (reviewUsecase *ReviewUsecaseImpl) Delete(ctx context.Context, reviewId uint) error {
+	tx := reviewUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	review := &entity.Review{ID: reviewId}
+	if err := reviewUsecase.ReviewRepo.FindById(tx, review); err != nil {
+		return reviewNotFoundError(err)
+	}
+
+	if err := reviewUsecase.ReviewRepo.Delete(tx, review); err != nil {
+		log.Println("failed when delete repo review : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := reviewUsecase.ContentRepo.RefreshRating(tx, review.ContentID); err != nil {
+		log.Println("failed when refresh rating repo content : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	reviewUsecase.RelatedContentUsecase.Invalidate(review.ContentID)
+
+	log.Println("success delete from usecase review")
+	return nil
+}
+
+// FindAll implements ReviewUsecase.
+func (reviewUsecase *ReviewUsecaseImpl) FindAll(ctx context.Context, status string) (*[]model.ReviewResponse, error) {
+	tx := reviewUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	status = strings.ToLower(status)
+	if status != "pending" && status != "approved" && status != "rejected" {
+		status = ""
+	}
+
+	var reviews = &[]entity.Review{}
+	if err := reviewUsecase.ReviewRepo.FindAll(tx, status, reviews); err != nil {
+		log.Println("failed when find all repo review : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase review")
+	return converter.ReviewToResponses(reviews), nil
+}
+
+// FindByContentId implements ReviewUsecase.
+func (reviewUsecase *ReviewUsecaseImpl) FindByContentId(ctx context.Context, contentId uint) (*[]model.ReviewResponse, error) {
+	tx := reviewUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	var reviews = &[]entity.Review{}
+	if err := reviewUsecase.ReviewRepo.FindApprovedByContentId(tx, contentId, reviews); err != nil {
+		log.Println("failed when find by content id repo review : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find by content id from usecase review")
+	return converter.ReviewToPublicResponses(reviews), nil
+}
+
+func reviewNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Review data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id review usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id review usecase:", err)
+	return fiber.ErrInternalServerError
+}
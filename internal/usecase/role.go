@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	AdminRoleEditor    = "editor"
+	AdminRolePublisher = "publisher"
+	AdminRoleAdmin     = "admin"
+)
+
+// canPublish reports whether the role may change live contents and
+// announcements without a review.
+func canPublish(role string) bool {
+	return role == AdminRolePublisher || role == AdminRoleAdmin
+}
+
+// findActor loads the admin a request is made on behalf of.
+func findActor(tx *gorm.DB, adminRepo repository.AdminRepository, actorId uint) (*entity.Admin, error) {
+	if actorId == 0 {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	actor := &entity.Admin{ID: actorId}
+	if err := adminRepo.FindById(tx, actor); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("error find actor : ", err)
+			return nil, fiber.ErrUnauthorized
+		}
+
+		log.Println("Error find actor:", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return actor, nil
+}
+
+func forbiddenError(detail string) error {
+	errorResponse := model.ErrorResponse{
+		Message: "Forbidden",
+		Details: []string{detail},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrForbidden.Code, string(jsonString))
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Bangdams/web-profile-API/internal/util"
+)
+
+// SiteLinks builds the public front-end URLs of contents and announcements
+// from configurable templates. The templates may use the {site}, {id},
+// {category} and {locale} placeholders; a template without {locale} gets
+// ?lang= appended for the locales other than the default one.
+type SiteLinks struct {
+	SiteURL              string
+	ContentTemplate      string
+	AnnouncementTemplate string
+}
+
+// withDefaults trims the site URL and fills in the templates left empty.
+func (links SiteLinks) withDefaults() SiteLinks {
+	links.SiteURL = strings.TrimRight(links.SiteURL, "/")
+	if links.ContentTemplate == "" {
+		links.ContentTemplate = "{site}/contents/{id}"
+	}
+	if links.AnnouncementTemplate == "" {
+		links.AnnouncementTemplate = "{site}/announcements/{id}"
+	}
+
+	return links
+}
+
+// Content returns the page of a content in the given locale.
+func (links SiteLinks) Content(id uint, category string, locale string) string {
+	return links.expand(links.ContentTemplate, id, category, locale)
+}
+
+// Announcement returns the page of an announcement in the given locale.
+func (links SiteLinks) Announcement(id uint, locale string) string {
+	return links.expand(links.AnnouncementTemplate, id, "", locale)
+}
+
+func (links SiteLinks) expand(template string, id uint, category string, locale string) string {
+	link := util.ExpandURLTemplate(template, map[string]string{
+		"site":     links.SiteURL,
+		"id":       strconv.FormatUint(uint64(id), 10),
+		"category": url.PathEscape(category),
+		"locale":   locale,
+	})
+
+	if strings.Contains(template, "{locale}") || locale == util.DefaultLocale {
+		return link
+	}
+
+	separator := "?"
+	if strings.Contains(link, "?") {
+		separator = "&"
+	}
+
+	return link + separator + "lang=" + url.QueryEscape(locale)
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// SitemapConfig holds the front-end links and the robots.txt settings.
+type SitemapConfig struct {
+	Links          SiteLinks
+	RobotsFile     string
+	RobotsDisallow []string
+}
+
+type SitemapUsecase interface {
+	Index(ctx context.Context, baseURL string) ([]byte, error)
+	Page(ctx context.Context, page int) ([]byte, error)
+	Robots(baseURL string) []byte
+}
+
+// SitemapUsecaseImpl builds the sitemap from the published contents and
+// announcements and keeps it in memory. Before serving, it compares a cheap
+// summary of both tables (row counts and the latest update) with the one the
+// cache was built from, so any change, wherever it came from, regenerates
+// the sitemap on the next request.
+type SitemapUsecaseImpl struct {
+	ContentRepo      repository.ContentRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	DB               *gorm.DB
+	Config           SitemapConfig
+
+	mutex    sync.Mutex
+	state    string
+	pages    [][]byte
+	lastMods []time.Time
+}
+
+func NewSitemapUsecase(contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, DB *gorm.DB, config SitemapConfig) SitemapUsecase {
+	config.Links = config.Links.withDefaults()
+
+	return &SitemapUsecaseImpl{
+		ContentRepo:      contentRepo,
+		AnnouncementRepo: announcementRepo,
+		DB:               DB,
+		Config:           config,
+	}
+}
+
+// Index implements SitemapUsecase. Up to SitemapMaxURLs URLs it is the
+// sitemap itself; above that it is an index of the numbered pages.
+func (sitemapUsecase *SitemapUsecaseImpl) Index(ctx context.Context, baseURL string) ([]byte, error) {
+	sitemapUsecase.mutex.Lock()
+	defer sitemapUsecase.mutex.Unlock()
+
+	if err := sitemapUsecase.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(sitemapUsecase.pages) == 1 {
+		return sitemapUsecase.pages[0], nil
+	}
+
+	refs := make([]util.SitemapRef, len(sitemapUsecase.pages))
+	for i := range sitemapUsecase.pages {
+		refs[i] = util.SitemapRef{
+			Loc:     fmt.Sprintf("%s/sitemap-%d.xml", strings.TrimRight(baseURL, "/"), i+1),
+			LastMod: util.SitemapLastMod(sitemapUsecase.lastMods[i]),
+		}
+	}
+
+	index, err := util.MarshalSitemapIndex(refs)
+	if err != nil {
+		log.Println("failed when marshal sitemap index : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success index from usecase sitemap")
+	return index, nil
+}
+
+// Page implements SitemapUsecase. Pages are numbered from 1.
+func (sitemapUsecase *SitemapUsecaseImpl) Page(ctx context.Context, page int) ([]byte, error) {
+	sitemapUsecase.mutex.Lock()
+	defer sitemapUsecase.mutex.Unlock()
+
+	if err := sitemapUsecase.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	if page < 1 || page > len(sitemapUsecase.pages) {
+		return nil, fiber.ErrNotFound
+	}
+
+	log.Println("success page from usecase sitemap")
+	return sitemapUsecase.pages[page-1], nil
+}
+
+// Robots implements SitemapUsecase. A configured robots file is served as
+// is; otherwise the rules are generated from the disallowed paths and point
+// crawlers at the sitemap.
+func (sitemapUsecase *SitemapUsecaseImpl) Robots(baseURL string) []byte {
+	if sitemapUsecase.Config.RobotsFile != "" {
+		body, err := os.ReadFile(sitemapUsecase.Config.RobotsFile)
+		if err == nil {
+			return body
+		}
+
+		log.Println("failed when read robots file, falling back to generated rules : ", err)
+	}
+
+	var builder strings.Builder
+	builder.WriteString("User-agent: *\n")
+	if len(sitemapUsecase.Config.RobotsDisallow) == 0 {
+		builder.WriteString("Disallow:\n")
+	}
+	for _, path := range sitemapUsecase.Config.RobotsDisallow {
+		builder.WriteString("Disallow: " + path + "\n")
+	}
+	builder.WriteString("\nSitemap: " + strings.TrimRight(baseURL, "/") + "/sitemap.xml\n")
+
+	return []byte(builder.String())
+}
+
+// refresh rebuilds the cached pages when the published items changed since
+// they were built. The caller holds the mutex.
+func (sitemapUsecase *SitemapUsecaseImpl) refresh(ctx context.Context) error {
+	tx := sitemapUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	contentState, announcementState := &model.SitemapState{}, &model.SitemapState{}
+	if err := sitemapUsecase.ContentRepo.SitemapState(tx, contentState); err != nil {
+		log.Println("failed when sitemap state repo content : ", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := sitemapUsecase.AnnouncementRepo.SitemapState(tx, now, announcementState); err != nil {
+		log.Println("failed when sitemap state repo announcement : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	state := sitemapStateKey(contentState) + "|" + sitemapStateKey(announcementState)
+	if sitemapUsecase.pages != nil && state == sitemapUsecase.state {
+		return nil
+	}
+
+	var contents, announcements = &[]model.SitemapEntry{}, &[]model.SitemapEntry{}
+	if err := sitemapUsecase.ContentRepo.FindSitemapEntries(tx, contents); err != nil {
+		log.Println("failed when find sitemap entries repo content : ", err)
+		return fiber.ErrInternalServerError
+	}
+	if err := sitemapUsecase.AnnouncementRepo.FindSitemapEntries(tx, now, announcements); err != nil {
+		log.Println("failed when find sitemap entries repo announcement : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	var urls []util.SitemapURL
+	var lastMods []time.Time
+	for _, entry := range *contents {
+		entryURLs := sitemapEntryURLs(&entry, func(locale string) string {
+			return sitemapUsecase.Config.Links.Content(entry.ID, entry.Category, locale)
+		})
+		urls = append(urls, entryURLs...)
+		for range entryURLs {
+			lastMods = append(lastMods, entry.UpdatedAt)
+		}
+	}
+	for _, entry := range *announcements {
+		entryURLs := sitemapEntryURLs(&entry, func(locale string) string {
+			return sitemapUsecase.Config.Links.Announcement(entry.ID, locale)
+		})
+		urls = append(urls, entryURLs...)
+		for range entryURLs {
+			lastMods = append(lastMods, entry.UpdatedAt)
+		}
+	}
+
+	var pages [][]byte
+	var pageLastMods []time.Time
+	for start := 0; start == 0 || start < len(urls); start += util.SitemapMaxURLs {
+		end := min(start+util.SitemapMaxURLs, len(urls))
+
+		page, err := util.MarshalURLSet(urls[start:end])
+		if err != nil {
+			log.Println("failed when marshal sitemap : ", err)
+			return fiber.ErrInternalServerError
+		}
+
+		var lastMod time.Time
+		for _, modified := range lastMods[start:end] {
+			if modified.After(lastMod) {
+				lastMod = modified
+			}
+		}
+
+		pages = append(pages, page)
+		pageLastMods = append(pageLastMods, lastMod)
+	}
+
+	sitemapUsecase.state = state
+	sitemapUsecase.pages = pages
+	sitemapUsecase.lastMods = pageLastMods
+
+	log.Printf("success regenerate sitemap from usecase sitemap : %d urls in %d pages", len(urls), len(pages))
+	return nil
+}
+
+// sitemapEntryURLs lists one URL per locale the item is available in. When
+// it has translations, every URL carries the hreflang alternates of all of
+// them, with the default locale doubling as x-default.
+func sitemapEntryURLs(entry *model.SitemapEntry, link func(locale string) string) []util.SitemapURL {
+	locales := []string{util.DefaultLocale}
+	if entry.Locales != nil {
+		for _, locale := range strings.Split(*entry.Locales, ",") {
+			if util.IsSupportedLocale(locale) && !slices.Contains(locales, locale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	lastMod := util.SitemapLastMod(entry.UpdatedAt)
+	if len(locales) == 1 {
+		return []util.SitemapURL{{
+			Loc:     link(util.DefaultLocale),
+			LastMod: lastMod,
+		}}
+	}
+
+	alternates := make([]util.SitemapAlternate, 0, len(locales)+1)
+	for _, locale := range locales {
+		alternates = append(alternates, util.SitemapAlternate{
+			Rel:      "alternate",
+			Hreflang: locale,
+			Href:     link(locale),
+		})
+	}
+	alternates = append(alternates, util.SitemapAlternate{
+		Rel:      "alternate",
+		Hreflang: "x-default",
+		Href:     alternates[0].Href,
+	})
+
+	urls := make([]util.SitemapURL, len(locales))
+	for i := range locales {
+		urls[i] = util.SitemapURL{
+			Loc:        alternates[i].Href,
+			LastMod:    lastMod,
+			Alternates: alternates,
+		}
+	}
+
+	return urls
+}
+
+func sitemapStateKey(state *model.SitemapState) string {
+	key := fmt.Sprintf("%d/%d", state.Total, state.Translations)
+	if state.LastModified != nil {
+		key += "/" + state.LastModified.Format(time.RFC3339Nano)
+	}
+	if state.LastTranslated != nil {
+		key += "/" + state.LastTranslated.Format(time.RFC3339Nano)
+	}
+
+	return key
+}
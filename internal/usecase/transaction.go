@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type joinedTxKey struct{}
+
+// joinedTx is an open transaction handed down to the usecases called with
+// a context from withTx, along with what they left to do after the commit.
+type joinedTx struct {
+	tx          *gorm.DB
+	savepoints  int
+	afterCommit []func()
+}
+
+// withTx makes the usecases called with the returned context run inside tx
+// instead of a transaction of their own, so a reviewer approving a change
+// and the change itself are committed together or not at all. The returned
+// func runs their after-commit work and is called once tx is committed.
+func withTx(ctx context.Context, tx *gorm.DB) (context.Context, func()) {
+	joined := &joinedTx{tx: tx}
+
+	return context.WithValue(ctx, joinedTxKey{}, joined), func() {
+		for _, fn := range joined.afterCommit {
+			fn()
+		}
+	}
+}
+
+// begin starts the transaction of a usecase call. A call made with a
+// context from withTx joins that transaction under a savepoint instead: its
+// rollback undoes only its own work and its commit leaves the decision to
+// the outer transaction.
+func begin(ctx context.Context, db *gorm.DB) *gorm.DB {
+	joined, ok := ctx.Value(joinedTxKey{}).(*joinedTx)
+	if !ok {
+		return db.WithContext(ctx).Begin()
+	}
+
+	joined.savepoints++
+	name := fmt.Sprintf("joined_%d", joined.savepoints)
+
+	tx := joined.tx.Session(&gorm.Session{Context: ctx})
+	if err := joined.tx.Session(&gorm.Session{}).SavePoint(name).Error; err != nil {
+		tx.AddError(err)
+		return tx
+	}
+
+	tx.Statement.ConnPool = &savepoint{ConnPool: joined.tx.Statement.ConnPool, tx: joined.tx, name: name}
+	return tx
+}
+
+// afterCommit runs fn once the work of a usecase call is committed: right
+// away for a call with a transaction of its own, after the outer commit for
+// one that joined a transaction.
+func afterCommit(ctx context.Context, fn func()) {
+	if joined, ok := ctx.Value(joinedTxKey{}).(*joinedTx); ok {
+		joined.afterCommit = append(joined.afterCommit, fn)
+		return
+	}
+
+	fn()
+}
+
+// savepoint stands in for the transaction of a joined usecase call.
+type savepoint struct {
+	gorm.ConnPool
+	tx   *gorm.DB
+	name string
+	done bool
+}
+
+// Commit keeps the work for the outer transaction.
+func (savepoint *savepoint) Commit() error {
+	savepoint.done = true
+	return nil
+}
+
+// Rollback undoes the work since the savepoint. Like sql.Tx it is a no-op
+// after Commit, so the deferred rollback of a usecase call stays harmless.
+func (savepoint *savepoint) Rollback() error {
+	if savepoint.done {
+		return sql.ErrTxDone
+	}
+
+	savepoint.done = true
+	return savepoint.tx.Session(&gorm.Session{}).RollbackTo(savepoint.name).Error
+}
@@ -0,0 +1,400 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	TrashItemContent      = "content"
+	TrashItemAnnouncement = "announcement"
+)
+
+type TrashUsecase interface {
+	FindAll(ctx context.Context, itemType string) (*[]model.TrashItemResponse, error)
+	Restore(ctx context.Context, itemType string, itemId uint, actorId uint) error
+	Purge(ctx context.Context, itemType string, itemId uint, actorId uint) error
+	PurgeExpired(ctx context.Context) error
+	Run(ctx context.Context)
+}
+
+// TrashUsecaseImpl manages soft deleted contents and announcements. Items stay
+// restorable for Retention after their deletion, then Run purges them along
+// with their co-editors, image files and attachments. Restoring or purging
+// an item by hand takes the same rights as deleting it.
+type TrashUsecaseImpl struct {
+	ContentRepo           repository.ContentRepository
+	AnnouncementRepo      repository.AnnouncementRepository
+	AttachmentRepo        repository.AnnouncementAttachmentRepository
+	ItemEditorRepo        repository.ItemEditorRepository
+	AdminRepo             repository.AdminRepository
+	RelatedContentUsecase RelatedContentUsecase
+	WebhookUsecase        WebhookUsecase
+	DB                    *gorm.DB
+	Retention             time.Duration
+	UploadDir             string
+	AttachmentDir         string
+}
+
+func NewTrashUsecase(contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, attachmentRepo repository.AnnouncementAttachmentRepository, itemEditorRepo repository.ItemEditorRepository, adminRepo repository.AdminRepository, relatedContentUsecase RelatedContentUsecase, webhookUsecase WebhookUsecase, DB *gorm.DB, retention time.Duration) TrashUsecase {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+
+	return &TrashUsecaseImpl{
+		ContentRepo:           contentRepo,
+		AnnouncementRepo:      announcementRepo,
+		AttachmentRepo:        attachmentRepo,
+		ItemEditorRepo:        itemEditorRepo,
+		AdminRepo:             adminRepo,
+		RelatedContentUsecase: relatedContentUsecase,
+		WebhookUsecase:        webhookUsecase,
+		DB:                    DB,
+		Retention:             retention,
+		UploadDir:             UploadDir,
+		AttachmentDir:         AttachmentDir,
+	}
+}
+
+// FindAll implements TrashUsecase.
+func (trashUsecase *TrashUsecaseImpl) FindAll(ctx context.Context, itemType string) (*[]model.TrashItemResponse, error) {
+	if itemType != "" && itemType != TrashItemContent && itemType != TrashItemAnnouncement {
+		return nil, trashTypeError()
+	}
+
+	tx := trashUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	responses := []model.TrashItemResponse{}
+	deletedAt := []time.Time{}
+
+	if itemType == "" || itemType == TrashItemContent {
+		var contents []entity.Content
+		if err := trashUsecase.ContentRepo.FindDeleted(tx, nil, &contents); err != nil {
+			log.Println("failed when find deleted repo content : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		for i := range contents {
+			responses = append(responses, *converter.ContentToTrashResponse(&contents[i], trashUsecase.Retention))
+			deletedAt = append(deletedAt, contents[i].DeletedAt.Time)
+		}
+	}
+
+	if itemType == "" || itemType == TrashItemAnnouncement {
+		var announcements []entity.Announcement
+		if err := trashUsecase.AnnouncementRepo.FindDeleted(tx, nil, &announcements); err != nil {
+			log.Println("failed when find deleted repo announcement : ", err)
+			return nil, fiber.ErrInternalServerError
+		}
+
+		for i := range announcements {
+			responses = append(responses, *converter.AnnouncementToTrashResponse(&announcements[i], trashUsecase.Retention))
+			deletedAt = append(deletedAt, announcements[i].DeletedAt.Time)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	// contents and announcements come as two lists, newest first overall
+	order := make([]int, len(responses))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return deletedAt[order[a]].After(deletedAt[order[b]])
+	})
+
+	sorted := make([]model.TrashItemResponse, len(responses))
+	for i, index := range order {
+		sorted[i] = responses[index]
+	}
+
+	log.Println("success find all from usecase trash")
+	return &sorted, nil
+}
+
+// Restore implements TrashUsecase.
+func (trashUsecase *TrashUsecaseImpl) Restore(ctx context.Context, itemType string, itemId uint, actorId uint) error {
+	tx := trashUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	actor, err := findActor(tx, trashUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	// a restored content is new again to the sites that got its deletion
+	var restoredContent *entity.Content
+
+	switch itemType {
+	case TrashItemContent:
+		content := &entity.Content{ID: itemId}
+		if err := trashUsecase.ContentRepo.FindDeletedById(tx, content); err != nil {
+			return trashNotFoundError(err)
+		}
+		restoredContent = content
+
+		if err := checkOwnership(tx, trashUsecase.ItemEditorRepo, actor, EditorItemContent, content.ID, content.CreatedBy); err != nil {
+			return err
+		}
+
+		if err := trashUsecase.ContentRepo.Restore(tx, content); err != nil {
+			log.Println("failed when restore repo content : ", err)
+			return fiber.ErrInternalServerError
+		}
+	case TrashItemAnnouncement:
+		announcement := &entity.Announcement{ID: itemId}
+		if err := trashUsecase.AnnouncementRepo.FindDeletedById(tx, announcement); err != nil {
+			return trashNotFoundError(err)
+		}
+
+		if err := checkOwnership(tx, trashUsecase.ItemEditorRepo, actor, EditorItemAnnouncement, announcement.ID, announcement.PublishedBy); err != nil {
+			return err
+		}
+
+		if err := trashUsecase.AnnouncementRepo.Restore(tx, announcement); err != nil {
+			log.Println("failed when restore repo announcement : ", err)
+			return fiber.ErrInternalServerError
+		}
+	default:
+		return trashTypeError()
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if restoredContent != nil {
+		trashUsecase.RelatedContentUsecase.Invalidate(restoredContent.ID)
+		trashUsecase.WebhookUsecase.Dispatch(ctx, WebhookEventContentCreated, converter.ContentToResponse(restoredContent))
+	}
+
+	log.Println("success restore from usecase trash")
+	return nil
+}
+
+// Purge implements TrashUsecase.
+func (trashUsecase *TrashUsecaseImpl) Purge(ctx context.Context, itemType string, itemId uint, actorId uint) error {
+	tx := trashUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	actor, err := findActor(tx, trashUsecase.AdminRepo, actorId)
+	if err != nil {
+		return err
+	}
+
+	var image string
+	var attachments []string
+
+	switch itemType {
+	case TrashItemContent:
+		content := &entity.Content{ID: itemId}
+		if err := trashUsecase.ContentRepo.FindDeletedById(tx, content); err != nil {
+			return trashNotFoundError(err)
+		}
+
+		if err := checkOwnership(tx, trashUsecase.ItemEditorRepo, actor, EditorItemContent, content.ID, content.CreatedBy); err != nil {
+			return err
+		}
+
+		if err := trashUsecase.ContentRepo.Purge(tx, content); err != nil {
+			log.Println("failed when purge repo content : ", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := trashUsecase.ItemEditorRepo.DeleteByItem(tx, EditorItemContent, content.ID); err != nil {
+			log.Println("failed when delete repo item editor : ", err)
+			return fiber.ErrInternalServerError
+		}
+		image = content.Image
+	case TrashItemAnnouncement:
+		announcement := &entity.Announcement{ID: itemId}
+		if err := trashUsecase.AnnouncementRepo.FindDeletedById(tx, announcement); err != nil {
+			return trashNotFoundError(err)
+		}
+
+		if err := checkOwnership(tx, trashUsecase.ItemEditorRepo, actor, EditorItemAnnouncement, announcement.ID, announcement.PublishedBy); err != nil {
+			return err
+		}
+
+		var err error
+		if attachments, err = trashUsecase.attachmentFiles(tx, announcement.ID); err != nil {
+			log.Println("failed when find by announcement repo announcement attachment : ", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := trashUsecase.AnnouncementRepo.Purge(tx, announcement); err != nil {
+			log.Println("failed when purge repo announcement : ", err)
+			return fiber.ErrInternalServerError
+		}
+
+		if err := trashUsecase.ItemEditorRepo.DeleteByItem(tx, EditorItemAnnouncement, announcement.ID); err != nil {
+			log.Println("failed when delete repo item editor : ", err)
+			return fiber.ErrInternalServerError
+		}
+		image = announcement.Image
+	default:
+		return trashTypeError()
+	}
+
+	unused, err := unusedImages(tx, trashUsecase.ContentRepo, trashUsecase.AnnouncementRepo, []string{image})
+	if err != nil {
+		log.Println("failed when count image usage : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	removeImages(trashUsecase.UploadDir, unused)
+	removeFiles(trashUsecase.AttachmentDir, attachments)
+
+	log.Println("success purge from usecase trash")
+	return nil
+}
+
+// PurgeExpired implements TrashUsecase.
+func (trashUsecase *TrashUsecaseImpl) PurgeExpired(ctx context.Context) error {
+	tx := trashUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	before := time.Now().Add(-trashUsecase.Retention)
+
+	var contents []entity.Content
+	if err := trashUsecase.ContentRepo.FindDeleted(tx, &before, &contents); err != nil {
+		return err
+	}
+
+	var announcements []entity.Announcement
+	if err := trashUsecase.AnnouncementRepo.FindDeleted(tx, &before, &announcements); err != nil {
+		return err
+	}
+
+	if len(contents) == 0 && len(announcements) == 0 {
+		return nil
+	}
+
+	var images []string
+	var attachments []string
+	for i := range contents {
+		if err := trashUsecase.ContentRepo.Purge(tx, &contents[i]); err != nil {
+			return err
+		}
+		if err := trashUsecase.ItemEditorRepo.DeleteByItem(tx, EditorItemContent, contents[i].ID); err != nil {
+			return err
+		}
+		images = append(images, contents[i].Image)
+	}
+
+	for i := range announcements {
+		files, err := trashUsecase.attachmentFiles(tx, announcements[i].ID)
+		if err != nil {
+			return err
+		}
+		attachments = append(attachments, files...)
+
+		if err := trashUsecase.AnnouncementRepo.Purge(tx, &announcements[i]); err != nil {
+			return err
+		}
+		if err := trashUsecase.ItemEditorRepo.DeleteByItem(tx, EditorItemAnnouncement, announcements[i].ID); err != nil {
+			return err
+		}
+		images = append(images, announcements[i].Image)
+	}
+
+	unused, err := unusedImages(tx, trashUsecase.ContentRepo, trashUsecase.AnnouncementRepo, images)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	removeImages(trashUsecase.UploadDir, unused)
+	removeFiles(trashUsecase.AttachmentDir, attachments)
+
+	log.Printf("success purge %d contents and %d announcements from usecase trash", len(contents), len(announcements))
+	return nil
+}
+
+// Run implements TrashUsecase.
+func (trashUsecase *TrashUsecaseImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if err := trashUsecase.PurgeExpired(ctx); err != nil {
+			log.Println("failed to purge trash : ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func trashTypeError() error {
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: []string{"type must be one of content or announcement"},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+func trashNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Deleted data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find deleted item trash usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find deleted item trash usecase:", err)
+	return fiber.ErrInternalServerError
+}
+
+// attachmentFiles lists the stored files of an announcement, which its purge
+// leaves behind as the rows go with the foreign key.
+func (trashUsecase *TrashUsecaseImpl) attachmentFiles(tx *gorm.DB, announcementId uint) ([]string, error) {
+	var attachments []entity.AnnouncementAttachment
+	if err := trashUsecase.AttachmentRepo.FindByAnnouncement(tx, announcementId, &attachments); err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, attachment := range attachments {
+		files = append(files, attachment.StoredName)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"gorm.io/gorm"
+)
+
+// UploadDir is where uploaded images are stored in all their sizes and
+// served from.
+const UploadDir = "./upload"
+
+// unusedImages keeps the images no content or announcement refers to any
+// more, as an imported image can be shared by many rows.
+func unusedImages(tx *gorm.DB, contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, images []string) ([]string, error) {
+	var unused []string
+	seen := map[string]bool{}
+
+	for _, image := range images {
+		if image == "" || seen[image] {
+			continue
+		}
+		seen[image] = true
+
+		contents, err := contentRepo.CountByImage(tx, image)
+		if err != nil {
+			return nil, err
+		}
+
+		announcements, err := announcementRepo.CountByImage(tx, image)
+		if err != nil {
+			return nil, err
+		}
+
+		if contents+announcements == 0 {
+			unused = append(unused, image)
+		}
+	}
+
+	return unused, nil
+}
+
+// removeImages deletes unused uploaded images along with the sizes and
+// WebP copies they were stored in.
+func removeImages(dir string, images []string) {
+	removeFiles(dir, util.ImageFiles(images))
+}
+
+// removeFiles deletes uploaded images or attachments from dir, once no row
+// refers to them any more.
+func removeFiles(dir string, names []string) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+
+		path := filepath.Join(dir, filepath.Base(name))
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Println("failed to remove file : ", err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// checkVersion compares the version sent back through If-Match with the one
+// of the locked row. An expected version of zero stands for "If-Match: *".
+func checkVersion(current uint, expected uint) error {
+	if expected == 0 || current == expected {
+		return nil
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "Precondition failed",
+		Details: []string{fmt.Sprintf("the data was changed by someone else, the current version is %d", current)},
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	return fiber.NewError(fiber.StatusPreconditionFailed, string(jsonString))
+}
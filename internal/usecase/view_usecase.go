@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	ViewItemContent      = "content"
+	ViewItemAnnouncement = "announcement"
+)
+
+type ViewUsecase interface {
+	Record(itemType string, itemId uint, visitorIp string)
+	Flush(ctx context.Context) error
+	Run(ctx context.Context)
+}
+
+type viewKey struct {
+	itemType string
+	itemId   uint
+	date     time.Time
+}
+
+// ViewUsecaseImpl counts views in memory and writes them to the database in
+// batches every FlushInterval, so a public GET never waits on MySQL. Views of
+// the same visitor on the same item within DedupWindow are counted once; a
+// zero window disables the deduplication.
+type ViewUsecaseImpl struct {
+	ViewRepo         repository.ViewRepository
+	ContentRepo      repository.ContentRepository
+	AnnouncementRepo repository.AnnouncementRepository
+	DB               *gorm.DB
+	FlushInterval    time.Duration
+	DedupWindow      time.Duration
+
+	mutex    sync.Mutex
+	pending  map[viewKey]uint64
+	lastSeen map[string]time.Time
+}
+
+func NewViewUsecase(viewRepo repository.ViewRepository, contentRepo repository.ContentRepository, announcementRepo repository.AnnouncementRepository, DB *gorm.DB, flushInterval time.Duration, dedupWindow time.Duration) ViewUsecase {
+	if flushInterval <= 0 {
+		flushInterval = 30 * time.Second
+	}
+
+	return &ViewUsecaseImpl{
+		ViewRepo:         viewRepo,
+		ContentRepo:      contentRepo,
+		AnnouncementRepo: announcementRepo,
+		DB:               DB,
+		FlushInterval:    flushInterval,
+		DedupWindow:      dedupWindow,
+		pending:          map[viewKey]uint64{},
+		lastSeen:         map[string]time.Time{},
+	}
+}
+
+// Record implements ViewUsecase.
+func (viewUsecase *ViewUsecaseImpl) Record(itemType string, itemId uint, visitorIp string) {
+	now := time.Now()
+
+	viewUsecase.mutex.Lock()
+	defer viewUsecase.mutex.Unlock()
+
+	if viewUsecase.DedupWindow > 0 && visitorIp != "" {
+		visitor := visitorIp + "|" + viewKey{itemType: itemType, itemId: itemId}.String()
+		if seen, ok := viewUsecase.lastSeen[visitor]; ok && now.Sub(seen) < viewUsecase.DedupWindow {
+			return
+		}
+		viewUsecase.lastSeen[visitor] = now
+	}
+
+	year, month, day := now.Date()
+	key := viewKey{
+		itemType: itemType,
+		itemId:   itemId,
+		date:     time.Date(year, month, day, 0, 0, 0, 0, now.Location()),
+	}
+	viewUsecase.pending[key]++
+}
+
+// Flush implements ViewUsecase.
+func (viewUsecase *ViewUsecaseImpl) Flush(ctx context.Context) error {
+	viewUsecase.mutex.Lock()
+	pending := viewUsecase.pending
+	viewUsecase.pending = map[viewKey]uint64{}
+
+	for visitor, seen := range viewUsecase.lastSeen {
+		if time.Since(seen) >= viewUsecase.DedupWindow {
+			delete(viewUsecase.lastSeen, visitor)
+		}
+	}
+	viewUsecase.mutex.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := viewUsecase.write(ctx, pending); err != nil {
+		// keep the counts for the next flush instead of losing them
+		viewUsecase.mutex.Lock()
+		for key, views := range pending {
+			viewUsecase.pending[key] += views
+		}
+		viewUsecase.mutex.Unlock()
+
+		return err
+	}
+
+	log.Println("success flush views : ", len(pending))
+	return nil
+}
+
+// Run implements ViewUsecase.
+func (viewUsecase *ViewUsecaseImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(viewUsecase.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := viewUsecase.Flush(context.Background()); err != nil {
+				log.Println("failed to flush views : ", err)
+			}
+			return
+		case <-ticker.C:
+			if err := viewUsecase.Flush(ctx); err != nil {
+				log.Println("failed to flush views : ", err)
+			}
+		}
+	}
+}
+
+func (viewUsecase *ViewUsecaseImpl) write(ctx context.Context, pending map[viewKey]uint64) error {
+	tx := viewUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	dailyViews := make([]entity.DailyView, 0, len(pending))
+	totals := map[viewKey]uint64{}
+
+	for key, views := range pending {
+		dailyViews = append(dailyViews, entity.DailyView{
+			ItemType: key.itemType,
+			ItemID:   key.itemId,
+			ViewDate: key.date,
+			Views:    views,
+		})
+		totals[viewKey{itemType: key.itemType, itemId: key.itemId}] += views
+	}
+
+	if err := viewUsecase.ViewRepo.AddDailyViews(tx, &dailyViews); err != nil {
+		log.Println("failed when add daily views repo view : ", err)
+		return err
+	}
+
+	for key, views := range totals {
+		var err error
+		switch key.itemType {
+		case ViewItemContent:
+			err = viewUsecase.ContentRepo.IncrementViewCount(tx, key.itemId, views)
+		case ViewItemAnnouncement:
+			err = viewUsecase.AnnouncementRepo.IncrementViewCount(tx, key.itemId, views)
+		}
+
+		if err != nil {
+			log.Println("failed when increment view count : ", err)
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (key viewKey) String() string {
+	return key.itemType + ":" + strconv.FormatUint(uint64(key.itemId), 10)
+}
@@ -0,0 +1,687 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bangdams/web-profile-API/internal/entity"
+	"github.com/Bangdams/web-profile-API/internal/model"
+	"github.com/Bangdams/web-profile-API/internal/model/converter"
+	"github.com/Bangdams/web-profile-API/internal/repository"
+	"github.com/Bangdams/web-profile-API/internal/util"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	WebhookEventContentCreated        = "content.created"
+	WebhookEventContentUpdated        = "content.updated"
+	WebhookEventContentDeleted        = "content.deleted"
+	WebhookEventAnnouncementPublished = "announcement.published"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+
+	// a delivery is attempted webhookMaxAttempts times, waiting twice as long
+	// after every failure, from webhookRetryBase up to webhookRetryMax
+	webhookMaxAttempts = 8
+	webhookRetryBase   = time.Minute
+	webhookRetryMax    = time.Hour
+	// webhookDisableAfter deliveries failing in a row disable a webhook
+	webhookDisableAfter = 5
+
+	webhookTimeout       = 10 * time.Second
+	webhookPollInterval  = 15 * time.Second
+	webhookBatchSize     = 50
+	webhookDeliveryLimit = 100
+	webhookResponseLimit = 1024
+)
+
+// webhookEvents lists the events in the order they are stored.
+var webhookEvents = []string{
+	WebhookEventContentCreated,
+	WebhookEventContentUpdated,
+	WebhookEventContentDeleted,
+	WebhookEventAnnouncementPublished,
+}
+
+type WebhookUsecase interface {
+	Create(ctx context.Context, request *model.WebhookCreateRequest) (*model.WebhookResponse, error)
+	Update(ctx context.Context, request *model.WebhookUpdateRequest) (*model.WebhookResponse, error)
+	Delete(ctx context.Context, id uint, actorId uint) error
+	FindById(ctx context.Context, id uint, actorId uint) (*model.WebhookResponse, error)
+	FindAll(ctx context.Context, actorId uint) (*[]model.WebhookResponse, error)
+	FindDeliveries(ctx context.Context, request *model.WebhookDeliveryFilterRequest) (*[]model.WebhookDeliveryResponse, error)
+	FindDelivery(ctx context.Context, request *model.WebhookDeliveryRequest) (*model.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, request *model.WebhookDeliveryRequest) (*model.WebhookDeliveryResponse, error)
+	Dispatch(ctx context.Context, event string, data any)
+	Run(ctx context.Context)
+}
+
+// WebhookUsecaseImpl tells partner sites about changes. Dispatch queues a
+// delivery for every webhook subscribed to the event and Run sends them in
+// the background, so a failing partner never slows down the admin panel.
+type WebhookUsecaseImpl struct {
+	WebhookRepo         repository.WebhookRepository
+	WebhookDeliveryRepo repository.WebhookDeliveryRepository
+	AdminRepo           repository.AdminRepository
+	DB                  *gorm.DB
+	Validate            *validator.Validate
+	Client              *http.Client
+
+	queued chan struct{}
+}
+
+func NewWebhookUsecase(webhookRepo repository.WebhookRepository, webhookDeliveryRepo repository.WebhookDeliveryRepository, adminRepo repository.AdminRepository, DB *gorm.DB, validate *validator.Validate) WebhookUsecase {
+	return &WebhookUsecaseImpl{
+		WebhookRepo:         webhookRepo,
+		WebhookDeliveryRepo: webhookDeliveryRepo,
+		AdminRepo:           adminRepo,
+		DB:                  DB,
+		Validate:            validate,
+		Client: &http.Client{
+			Timeout: webhookTimeout,
+			// a redirect is answered like any other status, never followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		queued: make(chan struct{}, 1),
+	}
+}
+
+// Create implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) Create(ctx context.Context, request *model.WebhookCreateRequest) (*model.WebhookResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := webhookUsecase.validate(request, request.URL, "create"); err != nil {
+		return nil, err
+	}
+
+	actor, err := webhookUsecase.findAdmin(tx, request.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		secret = newToken()
+	}
+
+	webhook := &entity.Webhook{
+		URL:         request.URL,
+		Secret:      secret,
+		Events:      joinWebhookEvents(request.Events),
+		Description: strings.TrimSpace(request.Description),
+		Active:      true,
+		CreatedBy:   actor.ID,
+	}
+
+	if err := webhookUsecase.WebhookRepo.Create(tx, webhook); err != nil {
+		log.Println("failed when create repo webhook : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	webhook.Admin = *actor
+
+	response := converter.WebhookToResponse(webhook)
+	response.Secret = webhook.Secret
+
+	log.Println("success create from usecase webhook")
+	return response, nil
+}
+
+// Update implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) Update(ctx context.Context, request *model.WebhookUpdateRequest) (*model.WebhookResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := webhookUsecase.validate(request, request.URL, "update"); err != nil {
+		return nil, err
+	}
+
+	if _, err := webhookUsecase.findAdmin(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{ID: request.ID}
+	if err := webhookUsecase.WebhookRepo.FindById(tx, webhook); err != nil {
+		return nil, webhookNotFoundError(err)
+	}
+
+	columns := []string{"url", "events", "description"}
+	webhook.URL = request.URL
+	webhook.Events = joinWebhookEvents(request.Events)
+	webhook.Description = strings.TrimSpace(request.Description)
+
+	if *request.Active != webhook.Active {
+		// enabling again starts the count of failures over
+		webhook.Active = *request.Active
+		webhook.FailureCount = 0
+		webhook.DisabledAt = nil
+		if !webhook.Active {
+			now := time.Now()
+			webhook.DisabledAt = &now
+		}
+		columns = append(columns, "active", "failure_count", "disabled_at")
+	}
+
+	if err := webhookUsecase.WebhookRepo.Patch(tx, &entity.Webhook{
+		ID:           webhook.ID,
+		URL:          webhook.URL,
+		Events:       webhook.Events,
+		Description:  webhook.Description,
+		Active:       webhook.Active,
+		FailureCount: webhook.FailureCount,
+		DisabledAt:   webhook.DisabledAt,
+	}, columns); err != nil {
+		log.Println("failed when patch repo webhook : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if webhook.Active {
+		webhookUsecase.wake()
+	}
+
+	log.Println("success update from usecase webhook")
+	return converter.WebhookToResponse(webhook), nil
+}
+
+// Delete implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) Delete(ctx context.Context, id uint, actorId uint) error {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := webhookUsecase.findAdmin(tx, actorId); err != nil {
+		return err
+	}
+
+	webhook := &entity.Webhook{ID: id}
+	if err := webhookUsecase.WebhookRepo.FindById(tx, webhook); err != nil {
+		return webhookNotFoundError(err)
+	}
+
+	if err := webhookUsecase.WebhookRepo.Delete(tx, &entity.Webhook{ID: id}); err != nil {
+		log.Println("failed when delete repo webhook : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return fiber.ErrInternalServerError
+	}
+
+	log.Println("success delete from usecase webhook")
+	return nil
+}
+
+// FindById implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) FindById(ctx context.Context, id uint, actorId uint) (*model.WebhookResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := webhookUsecase.findAdmin(tx, actorId); err != nil {
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{ID: id}
+	if err := webhookUsecase.WebhookRepo.FindById(tx, webhook); err != nil {
+		return nil, webhookNotFoundError(err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find by id from usecase webhook")
+	return converter.WebhookToResponse(webhook), nil
+}
+
+// FindAll implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) FindAll(ctx context.Context, actorId uint) (*[]model.WebhookResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if _, err := webhookUsecase.findAdmin(tx, actorId); err != nil {
+		return nil, err
+	}
+
+	var webhooks = &[]entity.Webhook{}
+	if err := webhookUsecase.WebhookRepo.FindAll(tx, webhooks); err != nil {
+		log.Println("failed when find all repo webhook : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find all from usecase webhook")
+	return converter.WebhookToResponses(webhooks), nil
+}
+
+// FindDeliveries implements WebhookUsecase. It lists the latest deliveries
+// of a webhook.
+func (webhookUsecase *WebhookUsecaseImpl) FindDeliveries(ctx context.Context, request *model.WebhookDeliveryFilterRequest) (*[]model.WebhookDeliveryResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := webhookUsecase.validate(request, "", "find deliveries"); err != nil {
+		return nil, err
+	}
+
+	if _, err := webhookUsecase.findAdmin(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	webhook := &entity.Webhook{ID: request.WebhookID}
+	if err := webhookUsecase.WebhookRepo.FindById(tx, webhook); err != nil {
+		return nil, webhookNotFoundError(err)
+	}
+
+	var deliveries = &[]entity.WebhookDelivery{}
+	if err := webhookUsecase.WebhookDeliveryRepo.FindByWebhook(tx, webhook.ID, request.Status, webhookDeliveryLimit, deliveries); err != nil {
+		log.Println("failed when find by webhook repo webhook delivery : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find deliveries from usecase webhook")
+	return converter.WebhookDeliveryToResponses(deliveries), nil
+}
+
+// FindDelivery implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) FindDelivery(ctx context.Context, request *model.WebhookDeliveryRequest) (*model.WebhookDeliveryResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	delivery, err := webhookUsecase.findDelivery(tx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success find delivery from usecase webhook")
+	return converter.WebhookDeliveryToResponse(delivery, true), nil
+}
+
+// Redeliver implements WebhookUsecase. The payload is sent again, unchanged,
+// as a new delivery that is attempted right away, also when the webhook is
+// disabled. Should the attempt fail, it is retried like any other delivery.
+func (webhookUsecase *WebhookUsecaseImpl) Redeliver(ctx context.Context, request *model.WebhookDeliveryRequest) (*model.WebhookDeliveryResponse, error) {
+	tx := webhookUsecase.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	original, err := webhookUsecase.findDelivery(tx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &entity.WebhookDelivery{
+		WebhookID:    original.WebhookID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		Status:       WebhookDeliveryPending,
+		RedeliveryOf: &original.ID,
+	}
+
+	if err := webhookUsecase.WebhookDeliveryRepo.Create(tx, delivery); err != nil {
+		log.Println("failed when create repo webhook delivery : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		log.Println("Failed commit transaction : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	if err := webhookUsecase.attempt(ctx, delivery, &original.Webhook); err != nil {
+		log.Println("failed when attempt webhook delivery : ", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	log.Println("success redeliver from usecase webhook")
+	return converter.WebhookDeliveryToResponse(delivery, true), nil
+}
+
+// Dispatch implements WebhookUsecase. It is called once the change is
+// committed; failing to queue the deliveries is logged, never returned, as
+// the change itself went through.
+func (webhookUsecase *WebhookUsecaseImpl) Dispatch(ctx context.Context, event string, data any) {
+	db := webhookUsecase.DB.WithContext(ctx)
+
+	var webhooks []entity.Webhook
+	if err := webhookUsecase.WebhookRepo.FindActiveByEvent(db, event, &webhooks); err != nil {
+		log.Println("failed when find active by event repo webhook : ", err)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	// every webhook gets the same id, which receivers can deduplicate on
+	payload, err := json.Marshal(map[string]any{
+		"id":         newToken()[:32],
+		"event":      event,
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+		"data":       data,
+	})
+	if err != nil {
+		log.Println("failed when marshal webhook payload : ", err)
+		return
+	}
+
+	now := time.Now()
+	for _, webhook := range webhooks {
+		delivery := &entity.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		}
+
+		if err := webhookUsecase.WebhookDeliveryRepo.Create(db, delivery); err != nil {
+			log.Println("failed when create repo webhook delivery : ", err)
+		}
+	}
+
+	webhookUsecase.wake()
+
+	log.Println("success dispatch from usecase webhook : ", event)
+}
+
+// Run implements WebhookUsecase.
+func (webhookUsecase *WebhookUsecaseImpl) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-webhookUsecase.queued:
+		}
+
+		if err := webhookUsecase.deliverDue(ctx); err != nil {
+			log.Println("failed to deliver webhooks : ", err)
+		}
+	}
+}
+
+// deliverDue attempts the deliveries that are due, batch by batch. Once ctx
+// is cancelled no new attempt is started, while the one under way is still
+// recorded so it is not sent again.
+func (webhookUsecase *WebhookUsecaseImpl) deliverDue(ctx context.Context) error {
+	for {
+		var deliveries []entity.WebhookDelivery
+		if err := webhookUsecase.WebhookDeliveryRepo.FindDue(webhookUsecase.DB.WithContext(ctx), time.Now(), webhookBatchSize, &deliveries); err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if err := webhookUsecase.attempt(context.WithoutCancel(ctx), &delivery, &delivery.Webhook); err != nil {
+				return err
+			}
+		}
+
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+// attempt posts a delivery once and records the outcome. Only failing to
+// record it is an error; a failed request is part of the outcome.
+func (webhookUsecase *WebhookUsecaseImpl) attempt(ctx context.Context, delivery *entity.WebhookDelivery, webhook *entity.Webhook) error {
+	db := webhookUsecase.DB.WithContext(ctx)
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "web-profile-webhooks/1.0")
+	request.Header.Set("X-Webhook-Event", delivery.Event)
+	request.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	request.Header.Set("X-Webhook-Signature", util.SignWebhook(webhook.Secret, timestamp, body))
+
+	delivery.Attempts++
+	delivery.ResponseCode = nil
+	delivery.ResponseBody = ""
+	delivery.Error = ""
+
+	response, err := webhookUsecase.Client.Do(request)
+	if err == nil {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, webhookResponseLimit))
+		response.Body.Close()
+
+		delivery.ResponseCode = &response.StatusCode
+		delivery.ResponseBody = strings.ToValidUTF8(string(responseBody), "")
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			err = fmt.Errorf("unexpected status %d", response.StatusCode)
+		}
+	}
+
+	now := time.Now()
+	delivery.NextAttemptAt = nil
+
+	switch {
+	case err == nil:
+		delivery.Status = WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = WebhookDeliveryFailed
+	default:
+		delivery.Status = WebhookDeliveryPending
+		next := now.Add(webhookRetryDelay(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+
+	if err != nil {
+		delivery.Error = truncate(err.Error(), 500)
+	}
+
+	if err := webhookUsecase.WebhookDeliveryRepo.Patch(db, &entity.WebhookDelivery{
+		ID:            delivery.ID,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		ResponseCode:  delivery.ResponseCode,
+		ResponseBody:  delivery.ResponseBody,
+		Error:         delivery.Error,
+		NextAttemptAt: delivery.NextAttemptAt,
+		DeliveredAt:   delivery.DeliveredAt,
+	}, []string{"status", "attempts", "response_code", "response_body", "error", "next_attempt_at", "delivered_at"}); err != nil {
+		return err
+	}
+
+	switch delivery.Status {
+	case WebhookDeliverySucceeded:
+		return webhookUsecase.WebhookRepo.RecordSuccess(db, webhook.ID)
+	case WebhookDeliveryFailed:
+		log.Printf("webhook %d failed to take delivery %d : %s", webhook.ID, delivery.ID, delivery.Error)
+		return webhookUsecase.WebhookRepo.RecordFailure(db, webhook.ID, webhookDisableAfter, now)
+	}
+
+	return nil
+}
+
+func (webhookUsecase *WebhookUsecaseImpl) wake() {
+	select {
+	case webhookUsecase.queued <- struct{}{}:
+	default:
+	}
+}
+
+// findAdmin loads the actor, who must be an admin: webhooks send every
+// change to outside sites.
+func (webhookUsecase *WebhookUsecaseImpl) findAdmin(tx *gorm.DB, actorId uint) (*entity.Admin, error) {
+	actor, err := findActor(tx, webhookUsecase.AdminRepo, actorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor.Role != AdminRoleAdmin {
+		return nil, forbiddenError("only admins can manage webhooks")
+	}
+
+	return actor, nil
+}
+
+func (webhookUsecase *WebhookUsecaseImpl) findDelivery(tx *gorm.DB, request *model.WebhookDeliveryRequest) (*entity.WebhookDelivery, error) {
+	if err := webhookUsecase.validate(request, "", "find delivery"); err != nil {
+		return nil, err
+	}
+
+	if _, err := webhookUsecase.findAdmin(tx, request.ActorID); err != nil {
+		return nil, err
+	}
+
+	delivery := &entity.WebhookDelivery{ID: request.DeliveryID}
+	err := webhookUsecase.WebhookDeliveryRepo.FindById(tx, delivery)
+	if err == nil && delivery.WebhookID != request.WebhookID {
+		err = gorm.ErrRecordNotFound
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			errorResponse := model.ErrorResponse{
+				Message: "Webhook delivery data was not found",
+				Details: []string{},
+			}
+
+			jsonString, _ := json.Marshal(errorResponse)
+
+			log.Println("error find delivery webhook usecase : ", err)
+
+			return nil, fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+		}
+
+		log.Println("Error find delivery webhook usecase:", err)
+		return nil, fiber.ErrInternalServerError
+	}
+
+	return delivery, nil
+}
+
+// validate checks the request and, when given, that the webhook URL is an
+// absolute http(s) URL.
+func (webhookUsecase *WebhookUsecaseImpl) validate(request any, webhookURL string, action string) error {
+	var validationErrors []string
+
+	err := webhookUsecase.Validate.Struct(request)
+	if err != nil {
+		for _, e := range err.(validator.ValidationErrors) {
+			msg := fmt.Sprintf("Field '%s' failed on '%s' rule", e.Field(), e.Tag())
+			validationErrors = append(validationErrors, msg)
+		}
+	} else if webhookURL != "" {
+		parsed, parseErr := url.Parse(webhookURL)
+		if parseErr != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			validationErrors = append(validationErrors, "Field 'URL' must be an http or https URL")
+		}
+	}
+
+	if len(validationErrors) == 0 {
+		return nil
+	}
+
+	errorResponse := model.ErrorResponse{
+		Message: "invalid request parameter",
+		Details: validationErrors,
+	}
+
+	jsonString, _ := json.Marshal(errorResponse)
+
+	log.Printf("error %s webhook : %v", action, validationErrors)
+
+	return fiber.NewError(fiber.ErrBadRequest.Code, string(jsonString))
+}
+
+// webhookRetryDelay is the wait after the given number of failed attempts.
+func webhookRetryDelay(attempts uint) time.Duration {
+	delay := webhookRetryBase
+	for i := uint(1); i < attempts && delay < webhookRetryMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, webhookRetryMax)
+}
+
+// joinWebhookEvents stores the events in their canonical order.
+func joinWebhookEvents(events []string) string {
+	chosen := map[string]bool{}
+	for _, event := range events {
+		chosen[event] = true
+	}
+
+	var joined []string
+	for _, event := range webhookEvents {
+		if chosen[event] {
+			joined = append(joined, event)
+		}
+	}
+
+	return strings.Join(joined, ",")
+}
+
+func webhookNotFoundError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		errorResponse := model.ErrorResponse{
+			Message: "Webhook data was not found",
+			Details: []string{},
+		}
+
+		jsonString, _ := json.Marshal(errorResponse)
+
+		log.Println("error find by id webhook usecase : ", err)
+
+		return fiber.NewError(fiber.ErrNotFound.Code, string(jsonString))
+	}
+
+	log.Println("Error find by id webhook usecase:", err)
+	return fiber.ErrInternalServerError
+}
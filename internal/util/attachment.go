@@ -0,0 +1,64 @@
+package util
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"net/http"
+)
+
+const (
+	MimeTypePDF  = "application/pdf"
+	MimeTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+var ErrUnsupportedAttachment = errors.New("only PDF and DOCX files can be attached")
+
+// attachmentExtensions maps the allowed types to the extension their files
+// are stored and downloaded with.
+var attachmentExtensions = map[string]string{
+	MimeTypePDF:  ".pdf",
+	MimeTypeDOCX: ".docx",
+}
+
+// SniffAttachment tells the type of an uploaded file from its content, so a
+// renamed executable can not pass for a letter. It returns the MIME type
+// and the extension that goes with it.
+func SniffAttachment(file io.ReaderAt, size int64) (string, string, error) {
+	head := make([]byte, 512)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", "", err
+	}
+
+	switch http.DetectContentType(head[:n]) {
+	case MimeTypePDF:
+		return MimeTypePDF, attachmentExtensions[MimeTypePDF], nil
+	case "application/zip":
+		// a DOCX is a zip package with a Word document part
+		if isWordPackage(file, size) {
+			return MimeTypeDOCX, attachmentExtensions[MimeTypeDOCX], nil
+		}
+	}
+
+	return "", "", ErrUnsupportedAttachment
+}
+
+func isWordPackage(file io.ReaderAt, size int64) bool {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return false
+	}
+
+	var contentTypes, document bool
+	for _, entry := range archive.File {
+		switch entry.Name {
+		case "[Content_Types].xml":
+			contentTypes = true
+		case "word/document.xml":
+			document = true
+		}
+	}
+
+	return contentTypes && document
+}
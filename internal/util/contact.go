@@ -0,0 +1,131 @@
+package util
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	ContactPhone     = "phone"
+	ContactWhatsApp  = "whatsapp"
+	ContactEmail     = "email"
+	ContactInstagram = "instagram"
+	ContactFacebook  = "facebook"
+	ContactWebsite   = "website"
+)
+
+var ErrInvalidContact = errors.New("invalid contact")
+
+var (
+	e164Pattern      = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	instagramPattern = regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`)
+	facebookPattern  = regexp.MustCompile(`^([A-Za-z0-9.\-]{1,50}|profile\.php\?id=[0-9]+)$`)
+	phoneSeparators  = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+)
+
+// NormalizePhoneNumber turns the usual Indonesian ways of writing a number,
+// such as 0812-3456-789 or 62812..., into E.164. Anything else is returned
+// without the separators and left to the E.164 validation.
+func NormalizePhoneNumber(value string) string {
+	value = phoneSeparators.Replace(strings.TrimSpace(value))
+
+	switch {
+	case strings.HasPrefix(value, "+"):
+		return value
+	case strings.HasPrefix(value, "00"):
+		return "+" + value[2:]
+	case strings.HasPrefix(value, "0"):
+		return "+62" + value[1:]
+	case strings.HasPrefix(value, "62"):
+		return "+" + value
+	default:
+		return value
+	}
+}
+
+// NormalizeContact validates one contact channel and returns it in the form
+// it is stored: E.164 for phone numbers, a lower case address for email, the
+// bare handle for Instagram and Facebook and an absolute URL for websites.
+func NormalizeContact(contactType string, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch contactType {
+	case ContactPhone, ContactWhatsApp:
+		value = NormalizePhoneNumber(value)
+		if !e164Pattern.MatchString(value) {
+			return "", ErrInvalidContact
+		}
+		return value, nil
+	case ContactEmail:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return "", ErrInvalidContact
+		}
+		return strings.ToLower(address.Address), nil
+	case ContactInstagram:
+		value, _, _ = strings.Cut(socialHandle(value, "instagram.com"), "?")
+		value = strings.TrimPrefix(strings.Trim(value, "/"), "@")
+		if !instagramPattern.MatchString(value) {
+			return "", ErrInvalidContact
+		}
+		return value, nil
+	case ContactFacebook:
+		value = socialHandle(socialHandle(value, "facebook.com"), "fb.com")
+		if !facebookPattern.MatchString(value) {
+			return "", ErrInvalidContact
+		}
+		return value, nil
+	case ContactWebsite:
+		if !strings.Contains(value, "://") {
+			value = "https://" + value
+		}
+
+		website, err := url.Parse(value)
+		if err != nil || (website.Scheme != "http" && website.Scheme != "https") || !strings.Contains(website.Hostname(), ".") {
+			return "", ErrInvalidContact
+		}
+		return website.String(), nil
+	default:
+		return "", ErrInvalidContact
+	}
+}
+
+// ContactLink returns a link that opens the contact channel directly.
+func ContactLink(contactType string, value string) string {
+	switch contactType {
+	case ContactPhone:
+		return "tel:" + value
+	case ContactWhatsApp:
+		return "https://wa.me/" + strings.TrimPrefix(value, "+")
+	case ContactEmail:
+		return "mailto:" + value
+	case ContactInstagram:
+		return "https://www.instagram.com/" + value
+	case ContactFacebook:
+		return "https://www.facebook.com/" + value
+	default:
+		return value
+	}
+}
+
+// socialHandle strips the profile URL of a social network down to its path,
+// e.g. https://www.instagram.com/kopi.bandung/ becomes kopi.bandung.
+func socialHandle(value string, host string) string {
+	lower := strings.ToLower(value)
+	for _, prefix := range []string{"https://", "http://"} {
+		lower = strings.TrimPrefix(lower, prefix)
+	}
+	lower = strings.TrimPrefix(lower, "www.")
+	lower = strings.TrimPrefix(lower, "m.")
+
+	if !strings.HasPrefix(lower, host+"/") {
+		return value
+	}
+
+	// keep the original case of the handle itself
+	handle := value[len(value)-len(lower)+len(host)+1:]
+	return strings.Trim(handle, "/")
+}
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalDateFormat     = "20060102"
+	icalDateTimeFormat = "20060102T150405Z"
+	icalLocalFormat    = "20060102T150405"
+	// icalTimeZone is the zone recurring events are written in, so a rule
+	// such as BYDAY=MO keeps its weekday and hour where the site is.
+	icalTimeZone = "Asia/Jakarta"
+	// icalLineLimit is the most octets a content line may take before it
+	// has to be folded.
+	icalLineLimit = 75
+)
+
+// icalZone is Western Indonesian Time, which has had no daylight saving
+// since 1964, so one fixed offset describes it.
+var icalZone = time.FixedZone(icalTimeZone, 7*60*60)
+
+// ICalEvent is one VEVENT of a calendar. The End of an all-day event is
+// its last day; the exclusive DTEND is derived from it.
+type ICalEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	RRule        string
+	Created      time.Time
+	LastModified time.Time
+}
+
+// ICalendar renders the events as an iCalendar (RFC 5545) file calendar
+// apps can subscribe to.
+func ICalendar(name string, events []ICalEvent) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//web-profile-API//Events//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:" + vcardEscaper.Replace(name),
+	}
+
+	for _, event := range events {
+		if event.zoned() {
+			lines = append(lines,
+				"BEGIN:VTIMEZONE",
+				"TZID:"+icalTimeZone,
+				"BEGIN:STANDARD",
+				"DTSTART:19700101T000000",
+				"TZOFFSETFROM:+0700",
+				"TZOFFSETTO:+0700",
+				"TZNAME:WIB",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			)
+			break
+		}
+	}
+
+	for _, event := range events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+event.LastModified.UTC().Format(icalDateTimeFormat),
+		)
+
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format(icalDateFormat),
+				"DTEND;VALUE=DATE:"+event.End.AddDate(0, 0, 1).Format(icalDateFormat),
+			)
+		} else if event.zoned() {
+			lines = append(lines,
+				"DTSTART;TZID="+icalTimeZone+":"+event.Start.In(icalZone).Format(icalLocalFormat),
+				"DTEND;TZID="+icalTimeZone+":"+event.End.In(icalZone).Format(icalLocalFormat),
+			)
+		} else {
+			lines = append(lines,
+				"DTSTART:"+event.Start.UTC().Format(icalDateTimeFormat),
+				"DTEND:"+event.End.UTC().Format(icalDateTimeFormat),
+			)
+		}
+
+		if event.RRule != "" {
+			lines = append(lines, "RRULE:"+event.RRule)
+		}
+
+		lines = append(lines, "SUMMARY:"+vcardEscaper.Replace(event.Summary))
+
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+vcardEscaper.Replace(event.Description))
+		}
+
+		if event.Location != "" {
+			lines = append(lines, "LOCATION:"+vcardEscaper.Replace(event.Location))
+		}
+
+		if event.URL != "" {
+			lines = append(lines, "URL:"+event.URL)
+		}
+
+		lines = append(lines,
+			"CREATED:"+event.Created.UTC().Format(icalDateTimeFormat),
+			"LAST-MODIFIED:"+event.LastModified.UTC().Format(icalDateTimeFormat),
+			"END:VEVENT",
+		)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for i, line := range lines {
+		lines[i] = foldICalLine(line)
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// zoned tells whether the times of the event are written in the local
+// zone. The weekdays and hours of a rule are read in the zone of DTSTART,
+// so a recurring event given in UTC could move to another day.
+func (event *ICalEvent) zoned() bool {
+	return event.RRule != "" && !event.AllDay
+}
+
+// foldICalLine splits a line longer than the limit into continuation lines
+// starting with a space, never in the middle of a UTF-8 character.
+func foldICalLine(line string) string {
+	if len(line) <= icalLineLimit {
+		return line
+	}
+
+	var builder strings.Builder
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		builder.WriteString(line[:cut])
+		builder.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space of a continuation line counts too
+		limit = icalLineLimit - 1
+	}
+	builder.WriteString(line)
+
+	return builder.String()
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestICalendar(t *testing.T) {
+	utc := func(day, hour int) time.Time { return time.Date(2025, 8, day, hour, 0, 0, 0, time.UTC) }
+	stamp := utc(1, 0)
+
+	tests := []struct {
+		name    string
+		event   ICalEvent
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "timed",
+			event:   ICalEvent{Start: utc(4, 2), End: utc(4, 4)},
+			want:    []string{"DTSTART:20250804T020000Z", "DTEND:20250804T040000Z"},
+			notWant: []string{"BEGIN:VTIMEZONE", "RRULE:"},
+		},
+		{
+			name: "timed and recurring",
+			// 23:00 UTC on Sunday is 06:00 on Monday in Jakarta
+			event: ICalEvent{Start: utc(3, 23), End: utc(4, 1), RRule: "FREQ=WEEKLY;BYDAY=MO"},
+			want: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Asia/Jakarta",
+				"TZOFFSETTO:+0700",
+				"DTSTART;TZID=Asia/Jakarta:20250804T060000",
+				"DTEND;TZID=Asia/Jakarta:20250804T080000",
+				"RRULE:FREQ=WEEKLY;BYDAY=MO",
+			},
+			notWant: []string{"DTSTART:20250803"},
+		},
+		{
+			name: "all day and recurring",
+			event: ICalEvent{
+				Start:  time.Date(2025, 8, 4, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+				RRule:  "FREQ=YEARLY",
+			},
+			want:    []string{"DTSTART;VALUE=DATE:20250804", "DTEND;VALUE=DATE:20250806", "RRULE:FREQ=YEARLY"},
+			notWant: []string{"BEGIN:VTIMEZONE"},
+		},
+		{
+			name:  "escaped text",
+			event: ICalEvent{Start: utc(4, 2), End: utc(4, 4), Summary: "Pasar, malam; musik", Location: "Jl. Pantai 1"},
+			want:  []string{`SUMMARY:Pasar\, malam\; musik`, "LOCATION:Jl. Pantai 1"},
+		},
+		{
+			name:  "folded line",
+			event: ICalEvent{Start: utc(4, 2), End: utc(4, 4), Description: strings.Repeat("a", 100)},
+			want:  []string{"DESCRIPTION:" + strings.Repeat("a", 63) + "\r\n " + strings.Repeat("a", 37)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.event.UID = "event-1@example.com"
+			test.event.Created = stamp
+			test.event.LastModified = stamp
+
+			calendar := ICalendar("Agenda", []ICalEvent{test.event})
+
+			if !strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(calendar, "END:VEVENT\r\nEND:VCALENDAR\r\n") {
+				t.Errorf("calendar = %q, want a whole VCALENDAR", calendar)
+			}
+
+			lines := "\r\n" + calendar
+			for _, want := range test.want {
+				if !strings.Contains(lines, "\r\n"+want+"\r\n") {
+					t.Errorf("calendar = %q, want line %q", calendar, want)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(lines, "\r\n"+notWant) {
+					t.Errorf("calendar = %q, want no line starting with %q", calendar, notWant)
+				}
+			}
+		})
+	}
+}
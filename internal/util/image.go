@@ -0,0 +1,203 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/disintegration/imaging"
+)
+
+// ImageVariant is one of the predefined sizes an uploaded image is stored
+// in, bounded by the length of its longest side.
+type ImageVariant struct {
+	Name    string
+	MaxSide int
+}
+
+const (
+	ImageVariantThumb  = "thumb"
+	ImageVariantMedium = "medium"
+	ImageVariantLarge  = "large"
+)
+
+// ImageVariants lists the sizes every upload is stored in. The large one is
+// kept under the name the rows refer to, so old links keep working.
+var ImageVariants = []ImageVariant{
+	{Name: ImageVariantThumb, MaxSide: 320},
+	{Name: ImageVariantMedium, MaxSide: 800},
+	{Name: ImageVariantLarge, MaxSide: 1600},
+}
+
+const (
+	MimeTypeWebP = "image/webp"
+
+	imageMaxPixels   = 50_000_000
+	imageJPEGQuality = 85
+	imageWebPQuality = 80
+)
+
+var (
+	ErrUnsupportedImage = errors.New("image must be a JPEG, PNG, GIF or WebP file")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+// UploadedImage is an uploaded image that was decoded and given the name
+// rows will refer to it by, but is not stored yet.
+type UploadedImage struct {
+	Name   string
+	source image.Image
+}
+
+// DecodeImage decodes an uploaded image, turning phone photos upright.
+// Photos are going to be stored as JPEG, images that may be transparent as
+// PNG.
+func DecodeImage(file io.Reader) (*UploadedImage, error) {
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	if config.Width*config.Height > imageMaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	source, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	ext := ".jpg"
+	if format == "png" || format == "gif" || format == "webp" {
+		ext = ".png"
+	}
+
+	return &UploadedImage{Name: GenerateRandomFilename(ext), source: source}, nil
+}
+
+// Save stores the image in dir in every variant, each once in its own
+// format and, in builds that can encode it, once as WebP.
+func (uploaded *UploadedImage) Save(dir string) error {
+	var written []string
+
+	for _, variant := range ImageVariants {
+		resized := uploaded.source
+		if bounds := resized.Bounds(); bounds.Dx() > variant.MaxSide || bounds.Dy() > variant.MaxSide {
+			resized = imaging.Fit(uploaded.source, variant.MaxSide, variant.MaxSide, imaging.Lanczos)
+		}
+
+		filename := ImageVariantName(uploaded.Name, variant.Name)
+		targets := []string{filename}
+		if webpEncoding {
+			targets = append(targets, WebPName(filename))
+		}
+
+		for _, target := range targets {
+			written = append(written, target)
+
+			if err := writeImage(filepath.Join(dir, target), resized); err != nil {
+				for _, done := range written {
+					os.Remove(filepath.Join(dir, done))
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeImage(path string, img image.Image) error {
+	var buffer bytes.Buffer
+
+	var err error
+	switch filepath.Ext(path) {
+	case ".webp":
+		err = encodeWebP(&buffer, img)
+	case ".png":
+		err = png.Encode(&buffer, img)
+	default:
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: imageJPEGQuality})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// ImageVariantName gives the file a variant of image is stored in, e.g.
+// "abc-thumb.jpg" for "abc.jpg". The large variant is image itself.
+func ImageVariantName(image string, variant string) string {
+	if variant == ImageVariantLarge {
+		return image
+	}
+
+	ext := filepath.Ext(image)
+	return strings.TrimSuffix(image, ext) + "-" + variant + ext
+}
+
+// ImageOriginalName undoes ImageVariantName, so a variant an older upload
+// was never stored in can fall back to the file itself.
+func ImageOriginalName(filename string) string {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+
+	for _, variant := range ImageVariants {
+		if trimmed, ok := strings.CutSuffix(base, "-"+variant.Name); ok {
+			return trimmed + ext
+		}
+	}
+
+	return filename
+}
+
+// WebPName gives the WebP sibling of an image file.
+func WebPName(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + ".webp"
+}
+
+// ImageFiles lists every file stored for the given images, so removing an
+// image takes its variants along.
+func ImageFiles(images []string) []string {
+	var files []string
+
+	for _, image := range images {
+		if image == "" {
+			continue
+		}
+
+		for _, variant := range ImageVariants {
+			filename := ImageVariantName(image, variant.Name)
+			files = append(files, filename, WebPName(filename))
+		}
+	}
+
+	return files
+}
+
+// ImageSrcset maps each variant of image to the URL it is served from.
+func ImageSrcset(image string) map[string]string {
+	if image == "" {
+		return nil
+	}
+
+	srcset := make(map[string]string, len(ImageVariants))
+	for _, variant := range ImageVariants {
+		srcset[variant.Name] = "/assets/image/" + ImageVariantName(image, variant.Name)
+	}
+
+	return srcset
+}
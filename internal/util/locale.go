@@ -0,0 +1,25 @@
+package util
+
+const DefaultLocale = "id"
+
+var SupportedLocales = []string{"id", "en"}
+
+// localeFallbacks lists, for each locale, the locales tried in order when an
+// item has no translation in the requested one.
+var localeFallbacks = map[string][]string{
+	"id": {"id"},
+	"en": {"en", "id"},
+}
+
+func IsSupportedLocale(locale string) bool {
+	_, ok := localeFallbacks[locale]
+	return ok
+}
+
+func LocaleFallbacks(locale string) []string {
+	if chain, ok := localeFallbacks[locale]; ok {
+		return chain
+	}
+
+	return localeFallbacks[DefaultLocale]
+}
@@ -0,0 +1,260 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+var headerBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
+// Mail is one outgoing email. HTML is optional; when set the mail carries
+// both parts as multipart/alternative.
+type Mail struct {
+	From    string
+	To      []string
+	ReplyTo string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string
+}
+
+// Mailer sends mails. Implementations are picked at start-up, so the
+// usecases do not know whether mails leave the machine.
+type Mailer interface {
+	Send(ctx context.Context, mail *Mail) error
+}
+
+// Bytes renders the mail as an RFC 5322 message.
+func (m *Mail) Bytes() ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, errors.New("mail has no recipient")
+	}
+
+	for _, address := range append([]string{m.From}, m.To...) {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", address, err)
+		}
+	}
+
+	var buffer bytes.Buffer
+	// line breaks in a value would let it inject headers of its own
+	header := func(key string, value string) {
+		buffer.WriteString(key + ": " + headerBreaks.Replace(value) + "\r\n")
+	}
+
+	header("From", m.From)
+	header("To", strings.Join(m.To, ", "))
+	if m.ReplyTo != "" {
+		header("Reply-To", m.ReplyTo)
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(m.From))
+	header("MIME-Version", "1.0")
+
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		header(textproto.CanonicalMIMEHeaderKey(key), m.Headers[key])
+	}
+
+	if m.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buffer.WriteString("\r\n")
+
+		if err := writeQuotedPrintable(&buffer, m.Text); err != nil {
+			return nil, err
+		}
+
+		return buffer.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buffer)
+	header("Content-Type", "multipart/alternative; boundary="+parts.Boundary())
+	buffer.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := writeQuotedPrintable(writer, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// FileMailer writes every mail as an .eml file into Dir instead of sending
+// it, for development and tests.
+type FileMailer struct {
+	Dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{Dir: dir}
+}
+
+// Send implements Mailer.
+func (mailer *FileMailer) Send(ctx context.Context, m *Mail) error {
+	message, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(mailer.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), randomHex(4))
+
+	return os.WriteFile(filepath.Join(mailer.Dir, name), message, 0o644)
+}
+
+// SMTPMailer delivers mails to an SMTP server. STARTTLS is used whenever the
+// server offers it and authentication only when Username is set, so it also
+// talks to local stand-ins such as MailHog.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+func NewSMTPMailer(host string, port string, username string, password string) *SMTPMailer {
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		Timeout:  30 * time.Second,
+	}
+}
+
+// Send implements Mailer.
+func (mailer *SMTPMailer) Send(ctx context.Context, m *Mail) error {
+	message, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: mailer.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(mailer.Host, mailer.Port))
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(mailer.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, mailer.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: mailer.Host}); err != nil {
+			return err
+		}
+	}
+
+	if mailer.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", mailer.Username, mailer.Password, mailer.Host)); err != nil {
+			return err
+		}
+	}
+
+	from, _ := mail.ParseAddress(m.From)
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+
+	for _, to := range m.To {
+		address, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(address.Address); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(address.Address, "@"); at >= 0 {
+			domain = address.Address[at+1:]
+		}
+	}
+
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), randomHex(8), domain)
+}
+
+func randomHex(size int) string {
+	buffer := make([]byte, size)
+	_, _ = rand.Read(buffer)
+	return hex.EncodeToString(buffer)
+}
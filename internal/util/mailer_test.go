@@ -0,0 +1,173 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestMailBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		mail  Mail
+		parts map[string]string
+	}{
+		{
+			name: "plain text",
+			mail: Mail{
+				From:    "Web Profile <noreply@example.com>",
+				To:      []string{"Budi <budi@example.com>"},
+				Subject: "Balasan pesan",
+				Text:    "Halo Budi,\n\nterima kasih atas pesannya.",
+			},
+			parts: map[string]string{
+				"text/plain": "Halo Budi,\n\nterima kasih atas pesannya.",
+			},
+		},
+		{
+			name: "plain text beyond ascii",
+			mail: Mail{
+				From:    "noreply@example.com",
+				To:      []string{"ana@example.com"},
+				Subject: "Konfirmasi — langganan",
+				Text:    strings.Repeat("café ", 40),
+			},
+			parts: map[string]string{
+				"text/plain": strings.Repeat("café ", 40),
+			},
+		},
+		{
+			name: "text and html",
+			mail: Mail{
+				From:    "noreply@example.com",
+				To:      []string{"ana@example.com"},
+				Subject: "Digest",
+				Text:    "Pengumuman baru",
+				HTML:    "<p>Pengumuman baru</p>",
+			},
+			parts: map[string]string{
+				"text/plain": "Pengumuman baru",
+				"text/html":  "<p>Pengumuman baru</p>",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw, err := test.mail.Bytes()
+			if err != nil {
+				t.Fatalf("Bytes() error = %v", err)
+			}
+
+			message, err := mail.ReadMessage(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("ReadMessage() error = %v", err)
+			}
+
+			subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			if err != nil || subject != test.mail.Subject {
+				t.Errorf("Subject = %q, want %q", subject, test.mail.Subject)
+			}
+
+			got := mailParts(t, message.Header.Get("Content-Type"), message.Body)
+			if len(got) != len(test.parts) {
+				t.Fatalf("parts = %v, want %v", got, test.parts)
+			}
+
+			for contentType, want := range test.parts {
+				if got[contentType] != want {
+					t.Errorf("%s body = %q, want %q", contentType, got[contentType], want)
+				}
+			}
+		})
+	}
+}
+
+func TestMailBytesRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		mail Mail
+	}{
+		{"no recipient", Mail{From: "noreply@example.com"}},
+		{"bad sender", Mail{From: "not an address", To: []string{"ana@example.com"}}},
+		{"bad recipient", Mail{From: "noreply@example.com", To: []string{"ana"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.mail.Bytes(); err == nil {
+				t.Error("Bytes() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestMailBytesHeaderInjection(t *testing.T) {
+	m := Mail{
+		From:    "noreply@example.com",
+		To:      []string{"ana@example.com"},
+		ReplyTo: "ana@example.com\r\nBcc: all@example.com",
+		Text:    "hi",
+	}
+
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+
+	if bcc := message.Header.Get("Bcc"); bcc != "" {
+		t.Errorf("Bcc = %q, want no such header", bcc)
+	}
+}
+
+// mailParts decodes the body of a mail into its parts by content type.
+func mailParts(t *testing.T, contentType string, body io.Reader) map[string]string {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("ParseMediaType(%q) error = %v", contentType, err)
+	}
+
+	parts := map[string]string{}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		parts[mediaType] = readQuotedPrintable(t, body)
+		return parts
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("NextRawPart() error = %v", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[partType] = readQuotedPrintable(t, part)
+	}
+}
+
+func readQuotedPrintable(t *testing.T, body io.Reader) string {
+	t.Helper()
+
+	decoded, err := io.ReadAll(quotedprintable.NewReader(body))
+	if err != nil {
+		t.Fatalf("reading quoted-printable body: %v", err)
+	}
+
+	// text bodies travel with CRLF line breaks
+	return strings.ReplaceAll(string(decoded), "\r\n", "\n")
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidMergePatch = errors.New("merge patch must be a JSON object")
+
+// MergePatch applies an RFC 7386 JSON Merge Patch to the original document
+// and returns the merged document.
+func MergePatch(original []byte, patch []byte) ([]byte, error) {
+	var target any
+	if err := json.Unmarshal(original, &target); err != nil {
+		return nil, err
+	}
+
+	var changes any
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergeValue(target, changes))
+}
+
+// MergePatchFields returns the top level members named by a merge patch.
+func MergePatchFields(patch []byte) ([]string, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil || members == nil {
+		return nil, ErrInvalidMergePatch
+	}
+
+	fields := make([]string, 0, len(members))
+	for field := range members {
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func mergeValue(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = map[string]any{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+
+	return targetObject
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"bytes"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+
+	excerptLength = 200
+)
+
+var (
+	markdown    = goldmark.New(goldmark.WithExtensions(extension.GFM))
+	htmlPolicy  = bluemonday.UGCPolicy()
+	stripPolicy = bluemonday.StrictPolicy()
+)
+
+// SanitizeHTML removes every element and attribute outside the allowlist
+// for user generated content (no scripts, styles, iframes or event handlers).
+func SanitizeHTML(source string) string {
+	return htmlPolicy.Sanitize(source)
+}
+
+// RenderContentHTML turns a stored body into HTML that is safe to inject in
+// the front end. Markdown is rendered first, the result is always sanitized.
+func RenderContentHTML(format string, source string) string {
+	if format != ContentFormatMarkdown {
+		return SanitizeHTML(source)
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return SanitizeHTML(html.EscapeString(source))
+	}
+
+	return SanitizeHTML(buf.String())
+}
+
+// PlainText strips every tag from rendered HTML and collapses whitespace.
+func PlainText(renderedHTML string) string {
+	// keep words of adjacent block elements apart once the tags are gone
+	text := stripPolicy.Sanitize(strings.ReplaceAll(renderedHTML, "<", " <"))
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// GenerateExcerpt returns the leading plain text of rendered HTML, cut on a
+// word boundary.
+func GenerateExcerpt(renderedHTML string) string {
+	text := PlainText(renderedHTML)
+
+	if utf8.RuneCountInString(text) <= excerptLength {
+		return text
+	}
+
+	runes := []rune(text)[:excerptLength]
+	if cut := strings.LastIndex(string(runes), " "); cut > 0 {
+		return string(runes)[:cut] + "…"
+	}
+
+	return string(runes) + "…"
+}
+
+// PrepareContentBody resolves the declared format of a body before it is
+// stored. Missing formats fall back to HTML, which is sanitized on write.
+func PrepareContentBody(format string, source string) (string, string) {
+	if format == ContentFormatMarkdown {
+		return format, source
+	}
+
+	return ContentFormatHTML, SanitizeHTML(source)
+}
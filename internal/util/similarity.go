@@ -0,0 +1,71 @@
+package util
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stopWords holds common Indonesian and English words that carry no meaning
+// when comparing two listings.
+var stopWords = map[string]bool{
+	"dan": true, "yang": true, "di": true, "ke": true, "dari": true, "ini": true, "itu": true,
+	"untuk": true, "dengan": true, "ada": true, "juga": true, "atau": true, "pada": true,
+	"dalam": true, "akan": true, "bisa": true, "tidak": true, "karena": true, "sangat": true,
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"are": true, "was": true, "from": true, "you": true, "your": true, "has": true,
+}
+
+// TermFrequencies counts the meaningful words of a text.
+func TermFrequencies(text string) map[string]float64 {
+	terms := map[string]float64{}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if len([]rune(word)) < 3 || stopWords[word] {
+			continue
+		}
+		terms[word]++
+	}
+
+	return terms
+}
+
+// CosineSimilarity compares two term vectors, 0 meaning nothing in common and
+// 1 meaning the same distribution of words.
+func CosineSimilarity(a map[string]float64, b map[string]float64) float64 {
+	var dot, normA, normB float64
+
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+
+	for _, weight := range b {
+		normB += weight * weight
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// DistanceKm returns the great-circle distance between two coordinates.
+func DistanceKm(latA float64, lngA float64, latB float64, lngB float64) float64 {
+	const earthRadiusKm = 6371.0
+
+	toRadians := func(degree float64) float64 { return degree * math.Pi / 180 }
+
+	deltaLat := toRadians(latB - latA)
+	deltaLng := toRadians(lngB - lngA)
+
+	h := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) +
+		math.Cos(toRadians(latA))*math.Cos(toRadians(latB))*math.Sin(deltaLng/2)*math.Sin(deltaLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
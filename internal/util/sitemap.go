@@ -0,0 +1,90 @@
+package util
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// SitemapMaxURLs is the most URLs the sitemap protocol allows in one file.
+const SitemapMaxURLs = 50000
+
+const (
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	xhtmlNamespace   = "http://www.w3.org/1999/xhtml"
+)
+
+type SitemapAlternate struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type SitemapURL struct {
+	Loc        string             `xml:"loc"`
+	LastMod    string             `xml:"lastmod,omitempty"`
+	Alternates []SitemapAlternate `xml:"xhtml:link"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Xhtml   string       `xml:"xmlns:xhtml,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+type SitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []SitemapRef `xml:"sitemap"`
+}
+
+// SitemapLastMod formats a time the way the lastmod element expects it.
+func SitemapLastMod(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// MarshalURLSet renders one sitemap file.
+func MarshalURLSet(urls []SitemapURL) ([]byte, error) {
+	return marshalSitemap(sitemapURLSet{
+		Xmlns: sitemapNamespace,
+		Xhtml: xhtmlNamespace,
+		URLs:  urls,
+	})
+}
+
+// MarshalSitemapIndex renders the index pointing at the sitemap files.
+func MarshalSitemapIndex(refs []SitemapRef) ([]byte, error) {
+	return marshalSitemap(sitemapIndex{
+		Xmlns:    sitemapNamespace,
+		Sitemaps: refs,
+	})
+}
+
+func marshalSitemap(document any) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// ExpandURLTemplate fills the {placeholders} of a front-end URL template.
+func ExpandURLTemplate(template string, values map[string]string) string {
+	pairs := make([]string, 0, len(values)*2)
+	for key, value := range values {
+		pairs = append(pairs, "{"+key+"}", value)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(template)
+}
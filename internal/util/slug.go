@@ -0,0 +1,28 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify turns a name into a lower case, dash separated URL segment, e.g.
+// "Pengumuman Resmi" into "pengumuman-resmi".
+func Slugify(name string) string {
+	var builder strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			builder.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && builder.Len() > 0 {
+			builder.WriteByte('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "-")
+}
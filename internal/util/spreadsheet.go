@@ -0,0 +1,35 @@
+package util
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrUnsupportedSpreadsheet = errors.New("only .csv and .xlsx files are supported")
+
+// ReadSpreadsheet returns every row of a CSV file or of the first sheet of an
+// XLSX workbook, the header included.
+func ReadSpreadsheet(filename string, reader io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		csvReader := csv.NewReader(reader)
+		csvReader.FieldsPerRecord = -1
+		csvReader.TrimLeadingSpace = true
+		return csvReader.ReadAll()
+	case ".xlsx":
+		workbook, err := excelize.OpenReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer workbook.Close()
+
+		return workbook.GetRows(workbook.GetSheetName(0))
+	default:
+		return nil, ErrUnsupportedSpreadsheet
+	}
+}
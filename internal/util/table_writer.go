@@ -0,0 +1,171 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+	ExportFormatXLSX = "xlsx"
+)
+
+var ErrUnsupportedExportFormat = errors.New("format must be one of csv, json or xlsx")
+
+// TableWriter writes an export one row at a time. Close must be called once
+// every row has been written.
+type TableWriter interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+// NewTableWriter starts an export in the given format, writing the header
+// straight away for the formats that have one.
+func NewTableWriter(format string, writer io.Writer, columns []string) (TableWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvTableWriter{writer: csvWriter}, nil
+	case ExportFormatJSON:
+		if _, err := io.WriteString(writer, "["); err != nil {
+			return nil, err
+		}
+		return &jsonTableWriter{writer: writer, columns: columns}, nil
+	case ExportFormatXLSX:
+		workbook := excelize.NewFile()
+		streamWriter, err := workbook.NewStreamWriter(workbook.GetSheetName(0))
+		if err != nil {
+			return nil, err
+		}
+
+		header := make([]any, len(columns))
+		for index, column := range columns {
+			header[index] = column
+		}
+
+		if err := streamWriter.SetRow("A1", header); err != nil {
+			return nil, err
+		}
+		return &xlsxTableWriter{writer: writer, workbook: workbook, streamWriter: streamWriter, row: 1}, nil
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+}
+
+// ExportContentType returns the Content-Type header of an export format.
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatJSON:
+		return "application/json; charset=utf-8"
+	case ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+type csvTableWriter struct {
+	writer *csv.Writer
+}
+
+func (tableWriter *csvTableWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for index, value := range values {
+		switch value := value.(type) {
+		case nil:
+			record[index] = ""
+		case time.Time:
+			record[index] = value.Format(time.RFC3339)
+		default:
+			record[index] = fmt.Sprint(value)
+		}
+	}
+
+	return tableWriter.writer.Write(record)
+}
+
+func (tableWriter *csvTableWriter) Close() error {
+	tableWriter.writer.Flush()
+	return tableWriter.writer.Error()
+}
+
+type jsonTableWriter struct {
+	writer  io.Writer
+	columns []string
+	rows    int
+}
+
+// WriteRow writes one object by hand so the keys keep the column order.
+func (tableWriter *jsonTableWriter) WriteRow(values []any) error {
+	buffer := []byte{'{'}
+	if tableWriter.rows > 0 {
+		buffer = []byte{',', '{'}
+	}
+
+	for index, value := range values {
+		if index > 0 {
+			buffer = append(buffer, ',')
+		}
+
+		key, _ := json.Marshal(tableWriter.columns[index])
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		buffer = append(buffer, key...)
+		buffer = append(buffer, ':')
+		buffer = append(buffer, encoded...)
+	}
+
+	buffer = append(buffer, '}')
+	tableWriter.rows++
+
+	_, err := tableWriter.writer.Write(buffer)
+	return err
+}
+
+func (tableWriter *jsonTableWriter) Close() error {
+	_, err := io.WriteString(tableWriter.writer, "]")
+	return err
+}
+
+// xlsxTableWriter keeps the sheet in excelize's stream writer, which spills to
+// a temporary file once it grows, and writes the workbook out on Close.
+type xlsxTableWriter struct {
+	writer       io.Writer
+	workbook     *excelize.File
+	streamWriter *excelize.StreamWriter
+	row          int
+}
+
+func (tableWriter *xlsxTableWriter) WriteRow(values []any) error {
+	tableWriter.row++
+
+	cell, err := excelize.CoordinatesToCellName(1, tableWriter.row)
+	if err != nil {
+		return err
+	}
+
+	return tableWriter.streamWriter.SetRow(cell, values)
+}
+
+func (tableWriter *xlsxTableWriter) Close() error {
+	defer tableWriter.workbook.Close()
+
+	if err := tableWriter.streamWriter.Flush(); err != nil {
+		return err
+	}
+
+	return tableWriter.workbook.Write(tableWriter.writer)
+}
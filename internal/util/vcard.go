@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// VCard is the business card of a listing, written as vCard 3.0 (RFC 2426).
+type VCard struct {
+	Name      string
+	Address   string
+	Phones    []string
+	Emails    []string
+	URLs      []string
+	Latitude  *float64
+	Longitude *float64
+}
+
+// String renders the card with the CRLF line endings the format requires.
+func (card *VCard) String() string {
+	lines := []string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:" + vcardEscaper.Replace(card.Name),
+		"ORG:" + vcardEscaper.Replace(card.Name),
+	}
+
+	if card.Address != "" {
+		lines = append(lines, "ADR;TYPE=WORK:;;"+vcardEscaper.Replace(card.Address)+";;;;")
+	}
+
+	for _, phone := range card.Phones {
+		lines = append(lines, "TEL;TYPE=WORK,VOICE:"+phone)
+	}
+
+	for _, email := range card.Emails {
+		lines = append(lines, "EMAIL;TYPE=INTERNET:"+email)
+	}
+
+	for _, url := range card.URLs {
+		lines = append(lines, "URL:"+url)
+	}
+
+	if card.Latitude != nil && card.Longitude != nil {
+		lines = append(lines, fmt.Sprintf("GEO:%f;%f", *card.Latitude, *card.Longitude))
+	}
+
+	lines = append(lines, "END:VCARD")
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
@@ -0,0 +1,49 @@
+package util
+
+import "testing"
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"event":"content.created"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      string
+	}{
+		{
+			name:      "signed body",
+			secret:    "rahasia",
+			timestamp: 1754038800,
+			body:      body,
+			want:      "sha256=d569a0f3a38487f8bb679c9650b0c898c9689420d9539f92f84d6ef7f6bb7329",
+		},
+		{
+			name:      "another timestamp",
+			secret:    "rahasia",
+			timestamp: 1754038801,
+			body:      body,
+			want:      "sha256=d3c29ade72bf14ae58312c918432ebc315856b9d31e18a68a414af2bbf8e9821",
+		},
+		{
+			name:      "another secret",
+			secret:    "lain",
+			timestamp: 1754038800,
+			body:      body,
+			want:      "sha256=8ae611279a7d7239e4b778d8435897b07a89ca63d53b93376a898c2bf77f0dc7",
+		},
+		{
+			name:      "empty body",
+			secret:    "rahasia",
+			timestamp: 1754038800,
+			want:      "sha256=cfd47d6d3ac6c96558eceff6d83fa97fb8196f9c871f330ca238953b90f08fcb",
+		},
+	}
+
+	for _, test := range tests {
+		if got := SignWebhook(test.secret, test.timestamp, test.body); got != test.want {
+			t.Errorf("%s: SignWebhook() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
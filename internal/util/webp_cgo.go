@@ -0,0 +1,18 @@
+//go:build cgo
+
+package util
+
+import (
+	"image"
+	"io"
+
+	"github.com/chai2010/webp"
+)
+
+// webpEncoding tells whether uploads get a WebP copy. The lossy encoder
+// wraps libwebp and so needs cgo.
+const webpEncoding = true
+
+func encodeWebP(w io.Writer, img image.Image) error {
+	return webp.Encode(w, img, &webp.Options{Quality: imageWebPQuality})
+}
@@ -0,0 +1,21 @@
+//go:build !cgo
+
+package util
+
+import (
+	"errors"
+	"image"
+	"io"
+
+	// WebP uploads are still decoded, in pure Go
+	_ "golang.org/x/image/webp"
+)
+
+// webpEncoding tells whether uploads get a WebP copy. Without cgo there is
+// no lossy encoder, so images are stored in their own format only and
+// served as such to every browser.
+const webpEncoding = false
+
+func encodeWebP(w io.Writer, img image.Image) error {
+	return errors.New("webp encoding needs a build with cgo")
+}